@@ -7,6 +7,10 @@ import (
 )
 
 // Cache defines the interface for caching.
+//
+// Implementations must honor the context passed to each operation: once it is
+// cancelled or its deadline has passed, operations return ctx.Err() (or report a
+// miss for lookups) instead of blocking, so remote stores cannot stall callers.
 type Cache interface {
 	// Get retrieves a value from the cache.
 	// It returns the value and a boolean indicating whether the value was found.
@@ -17,9 +21,22 @@ type Cache interface {
 	GetWithType(ctx context.Context, key string, value interface{}) bool
 
 	// Set stores a value in the cache with the given key and TTL.
-	// If ttl is 0, the value will not expire.
+	// If ttl is 0, the value will not expire; Config.DefaultTTL is not applied.
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 
+	// SetDefault stores a value in the cache using the configured Config.DefaultTTL.
+	SetDefault(ctx context.Context, key string, value interface{}) error
+
+	// SetNX stores a value only if the key does not already exist (or has expired).
+	// It returns true if the value was stored. The check and the write are atomic,
+	// which makes SetNX suitable for simple leases and locks.
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+
+	// CompareAndSwap atomically replaces the value of key with newValue if its current
+	// value equals old. It returns true if the swap happened; a missing or expired
+	// key never matches.
+	CompareAndSwap(ctx context.Context, key string, old, newValue interface{}, ttl time.Duration) (bool, error)
+
 	// Delete removes a value from the cache.
 	Delete(ctx context.Context, key string) error
 
@@ -37,6 +54,10 @@ type Cache interface {
 	// If ttl is 0, the values will not expire.
 	SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error
 
+	// SetMultiTTL stores multiple values in the cache, each with its own TTL.
+	// An item whose TTL is 0 does not expire.
+	SetMultiTTL(ctx context.Context, items map[string]CacheItem) error
+
 	// DeleteMulti removes multiple values from the cache.
 	DeleteMulti(ctx context.Context, keys []string) error
 
@@ -44,6 +65,11 @@ type Cache interface {
 	// It returns the new value.
 	Increment(ctx context.Context, key string, amount int64) (int64, error)
 
+	// IncrementWithTTL increments a counter by the given amount, creating it
+	// with the given TTL if it does not exist; an existing counter keeps its
+	// expiration. It returns the new value.
+	IncrementWithTTL(ctx context.Context, key string, amount int64, ttl time.Duration) (int64, error)
+
 	// Decrement decrements a counter by the given amount.
 	// It returns the new value.
 	Decrement(ctx context.Context, key string, amount int64) (int64, error)
@@ -52,6 +78,43 @@ type Cache interface {
 	Close() error
 }
 
+// CacheItem is a value and its TTL, as written by SetMultiTTL.
+type CacheItem struct {
+	Value interface{}
+	TTL   time.Duration
+}
+
+// Persister is implemented by caches that can snapshot their contents, e.g. to
+// persist an in-memory cache on shutdown and warm it again on start.
+type Persister interface {
+	// Dump serializes all non-expired entries together with their expiry times.
+	Dump(ctx context.Context) ([]byte, error)
+
+	// Load restores entries produced by Dump, adding them to the current contents.
+	// Entries that expired since the dump are skipped.
+	Load(ctx context.Context, data []byte) error
+}
+
+// KeyLister is implemented by caches that can enumerate their keys, e.g. for
+// targeted invalidation in maintenance tasks.
+type KeyLister interface {
+	// Keys returns the sorted non-expired keys matching a glob-style pattern:
+	// * matches any sequence (including none), ? matches one character,
+	// [abc], [a-z] and [^a] match character classes and \ escapes the next
+	// character. Matching visits every key, so it is O(n) in the size of the
+	// store and should not be used on hot paths of large caches.
+	Keys(ctx context.Context, pattern string) ([]string, error)
+}
+
+// Pinger is implemented by remote caches with a cheap connectivity probe,
+// such as a Redis PING. Lookups report only hit or miss, so callers that must
+// tell a miss from an unreachable store, like health checks and the
+// circuit-breaking cache, use Ping to find out.
+type Pinger interface {
+	// Ping returns an error if the store cannot be reached.
+	Ping(ctx context.Context) error
+}
+
 // StoreType defines the type of cache store.
 type StoreType string
 
@@ -63,6 +126,16 @@ const (
 	StoreTypeRedis StoreType = "redis"
 )
 
+// Codec converts cache values to and from bytes for stores that keep values
+// serialized and for GetWithType conversions.
+type Codec interface {
+	// Marshal encodes v.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes data into the value pointed to by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
 // Config holds configuration for caching.
 type Config struct {
 	// Enabled determines if caching is enabled.
@@ -71,7 +144,8 @@ type Config struct {
 	// StoreType is the type of cache store to use.
 	StoreType StoreType
 
-	// DefaultTTL is the default time-to-live for cache entries.
+	// DefaultTTL is the time-to-live applied by SetDefault. Set always uses the
+	// TTL it is given, so a zero TTL there still means no expiry.
 	DefaultTTL time.Duration
 
 	// CleanupInterval is the interval at which to clean up expired entries.
@@ -82,6 +156,16 @@ type Config struct {
 	// Only applicable for memory store.
 	MaxEntries int
 
+	// SlidingExpiration makes Get and GetWithType restart an entry's TTL on
+	// every hit, using the TTL it was stored with, so entries such as
+	// sessions expire only after going unread for that long. Entries
+	// without a TTL are unaffected. Only applicable for memory store.
+	SlidingExpiration bool
+
+	// Codec serializes values for GetWithType and for remote stores. Nil
+	// means JSON.
+	Codec Codec
+
 	// Redis configuration
 	Redis struct {
 		// Address is the Redis server address.
@@ -98,29 +182,38 @@ type Config struct {
 
 		// PoolSize is the maximum number of connections in the Redis connection pool.
 		PoolSize int
+
+		// DropWritesWhenOpen makes writes report success instead of the
+		// breaker's error while the cache circuit breaker is open, for
+		// services that treat the cache as best effort.
+		DropWritesWhenOpen bool
 	}
 }
 
 // DefaultConfig returns the default configuration for caching.
 func DefaultConfig() Config {
 	return Config{
-		Enabled:         true,
-		StoreType:       StoreTypeMemory,
-		DefaultTTL:      time.Minute * 5,
-		CleanupInterval: time.Minute,
-		MaxEntries:      10000,
+		Enabled:           true,
+		StoreType:         StoreTypeMemory,
+		DefaultTTL:        time.Minute * 5,
+		CleanupInterval:   time.Minute,
+		MaxEntries:        10000,
+		SlidingExpiration: false,
 		Redis: struct {
 			Address    string
 			Password   string
 			DB         int
 			MaxRetries int
 			PoolSize   int
+
+			DropWritesWhenOpen bool
 		}{
-			Address:    "localhost:6379",
-			Password:   "",
-			DB:         0,
-			MaxRetries: 3,
-			PoolSize:   10,
+			Address:            "localhost:6379",
+			Password:           "",
+			DB:                 0,
+			MaxRetries:         3,
+			PoolSize:           10,
+			DropWritesWhenOpen: false,
 		},
 	}
 }
@@ -24,6 +24,12 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.MaxEntries <= 0 {
 		t.Fatalf("expected positive MaxEntries")
 	}
+	if cfg.SlidingExpiration {
+		t.Fatalf("expected SlidingExpiration disabled by default")
+	}
+	if cfg.Codec != nil {
+		t.Fatalf("expected nil Codec (JSON) by default")
+	}
 	if cfg.Redis.Address == "" {
 		t.Fatalf("expected default Redis address")
 	}
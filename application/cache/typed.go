@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// GetTyped retrieves a value from c as T. Values stored with the same type are
+// returned directly; anything else (e.g. values restored from a serialized form)
+// is converted via GetWithType. It returns false if the key is missing or the
+// value cannot be converted to T.
+func GetTyped[T any](ctx context.Context, c Cache, key string) (T, bool) {
+	var value T
+
+	raw, found := c.Get(ctx, key)
+	if !found {
+		return value, false
+	}
+	if typed, ok := raw.(T); ok {
+		return typed, true
+	}
+
+	if !c.GetWithType(ctx, key, &value) {
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
+
+// SetTyped stores value in c under key with the given TTL.
+// If ttl is 0, the value will not expire.
+func SetTyped[T any](ctx context.Context, c Cache, key string, value T, ttl time.Duration) error {
+	return c.Set(ctx, key, value, ttl)
+}
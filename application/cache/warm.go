@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// warmConcurrency bounds the number of loader calls Warm runs at once.
+const warmConcurrency = 8
+
+// Loader loads the value for a cache key from the source of truth.
+type Loader func(ctx context.Context, key string) (interface{}, error)
+
+// Warm pre-populates c with the keys it is missing, typically on startup.
+// Missing keys are loaded concurrently, with at most 8 loader calls in
+// flight, and stored with a single SetMulti using ttl. Keys already cached
+// are not loaded again and duplicate keys are loaded once.
+//
+// A failed load does not stop the others: every value that loaded is still
+// stored, and the load errors are returned joined together. Once ctx is done
+// no further loads are started, but the values already loaded are stored.
+func Warm(ctx context.Context, c Cache, keys []string, loader Loader, ttl time.Duration) error {
+	unique := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, dup := seen[key]; !dup {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+
+	_, missing := c.GetMulti(ctx, unique)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		loaded = make(map[string]interface{}, len(missing))
+		errs   []error
+	)
+	sem := make(chan struct{}, warmConcurrency)
+	for _, key := range missing {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			value, err := loader(ctx, key)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("warm %q: %w", key, err))
+				return
+			}
+			loaded[key] = value
+		}(key)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	if len(loaded) > 0 {
+		// Store what was loaded even if ctx ended while loading
+		if err := c.SetMulti(context.WithoutCancel(ctx), loaded, ttl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+func TestWarm(t *testing.T) {
+	ctx := context.Background()
+	c := testsupport.NewCache(0)
+	if err := c.Set(ctx, "cached", "already", 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		calls    = map[string]int{}
+		inFlight atomic.Int32
+		peak     atomic.Int32
+	)
+	loader := func(_ context.Context, key string) (interface{}, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		calls[key]++
+		mu.Unlock()
+		return "value:" + key, nil
+	}
+
+	keys := []string{"cached"}
+	for i := 0; i < 50; i++ {
+		keys = append(keys, "k"+string(rune('a'+i%26))+string(rune('a'+i/26)))
+	}
+	keys = append(keys, keys[1]) // duplicate
+
+	if err := appcache.Warm(ctx, c, keys, loader, time.Minute); err != nil {
+		t.Fatalf("warm: %v", err)
+	}
+
+	for _, key := range keys[1:] {
+		if v, ok := c.Get(ctx, key); !ok || v != "value:"+key {
+			t.Fatalf("expected %s to be warmed, got %v %v", key, v, ok)
+		}
+		if calls[key] != 1 {
+			t.Fatalf("expected loader to run once for %s, ran %d times", key, calls[key])
+		}
+	}
+	if calls["cached"] != 0 {
+		t.Fatalf("expected cached key not to be loaded")
+	}
+	if v, _ := c.Get(ctx, "cached"); v != "already" {
+		t.Fatalf("expected cached value untouched, got %v", v)
+	}
+	if peak.Load() > 8 {
+		t.Fatalf("expected at most 8 concurrent loads, saw %d", peak.Load())
+	}
+}
+
+func TestWarm_LoaderErrors(t *testing.T) {
+	ctx := context.Background()
+	c := testsupport.NewCache(0)
+	errBoom := errors.New("boom")
+
+	err := appcache.Warm(ctx, c, []string{"good", "bad"}, func(_ context.Context, key string) (interface{}, error) {
+		if key == "bad" {
+			return nil, errBoom
+		}
+		return key, nil
+	}, 0)
+
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected loader error, got %v", err)
+	}
+	if _, ok := c.Get(ctx, "good"); !ok {
+		t.Fatalf("expected successful loads to be stored despite failures")
+	}
+	if _, ok := c.Get(ctx, "bad"); ok {
+		t.Fatalf("expected failed key to stay missing")
+	}
+}
+
+func TestWarm_StoresLoadedValuesAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := testsupport.NewCache(0)
+
+	err := appcache.Warm(ctx, c, []string{"loaded"}, func(_ context.Context, key string) (interface{}, error) {
+		cancel()
+		return key, nil
+	}, 0)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation to be reported, got %v", err)
+	}
+	if _, ok := c.Get(context.Background(), "loaded"); !ok {
+		t.Fatalf("expected the value loaded before the cancellation to be stored")
+	}
+}
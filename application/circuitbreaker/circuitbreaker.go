@@ -48,6 +48,22 @@ type CircuitBreaker interface {
 
 	// Reset resets the circuit breaker for the given name to its initial state.
 	Reset(name string)
+
+	// ResetAll resets every breaker to its initial state, e.g. after a broad
+	// incident has been resolved.
+	ResetAll()
+
+	// List returns the status of every breaker created so far, sorted by name,
+	// e.g. for an admin endpoint.
+	List() []BreakerStatus
+}
+
+// BreakerStatus is the name and current state of one breaker. Implementations
+// that record metrics label them with the breaker name under the "breaker"
+// label, so many breakers can share one metrics namespace.
+type BreakerStatus struct {
+	Name  string `json:"name"`
+	State State  `json:"state"`
 }
 
 // Config holds configuration for circuit breakers.
@@ -72,6 +88,20 @@ type Config struct {
 
 	// HealthCheckInterval is the interval at which to check the health of the circuit.
 	HealthCheckInterval time.Duration
+
+	// FallbackTimeout bounds the fallback of ExecuteWithFallback when the primary
+	// failed on a deadline. The fallback then gets a fresh context with this
+	// timeout, keeping the original context's values, instead of the expired one.
+	// Zero uses Timeout.
+	FallbackTimeout time.Duration
+
+	// OpenAlertThreshold is how long a breaker may stay open before the open
+	// monitor starts warning about it. Zero disables the monitor.
+	OpenAlertThreshold time.Duration
+
+	// OpenAlertInterval is how often the open monitor checks the breakers and
+	// repeats its warning while a breaker stays open.
+	OpenAlertInterval time.Duration
 }
 
 // DefaultConfig returns the default configuration for circuit breakers.
@@ -84,5 +114,8 @@ func DefaultConfig() Config {
 		ErrorThresholdPercentage: 50,
 		SleepWindow:              time.Second * 5,
 		HealthCheckInterval:      time.Second * 10,
+		FallbackTimeout:          time.Second * 1,
+		OpenAlertThreshold:       time.Minute * 1,
+		OpenAlertInterval:        time.Second * 30,
 	}
 }
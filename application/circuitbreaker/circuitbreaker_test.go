@@ -31,4 +31,13 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.HealthCheckInterval != time.Second*10 {
 		t.Fatalf("unexpected HealthCheckInterval: %v", cfg.HealthCheckInterval)
 	}
+	if cfg.FallbackTimeout != time.Second*1 {
+		t.Fatalf("unexpected FallbackTimeout: %v", cfg.FallbackTimeout)
+	}
+	if cfg.OpenAlertThreshold != time.Minute*1 {
+		t.Fatalf("unexpected OpenAlertThreshold: %v", cfg.OpenAlertThreshold)
+	}
+	if cfg.OpenAlertInterval != time.Second*30 {
+		t.Fatalf("unexpected OpenAlertInterval: %v", cfg.OpenAlertInterval)
+	}
 }
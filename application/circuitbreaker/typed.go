@@ -0,0 +1,29 @@
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExecuteTyped runs fn through cb.Execute and returns its result as T, so call
+// sites don't need to assert the interface{} result themselves. On error the
+// zero value of T is returned.
+func ExecuteTyped[T any](ctx context.Context, cb CircuitBreaker, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	result, err := cb.Execute(ctx, name, func(ctx context.Context) (interface{}, error) {
+		return fn(ctx)
+	})
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, nil
+	}
+
+	typed, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("circuit breaker '%s' returned %T, expected %T", name, result, zero)
+	}
+	return typed, nil
+}
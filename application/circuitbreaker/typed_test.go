@@ -0,0 +1,65 @@
+package circuitbreaker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appcb "github.com/next-trace/scg-service-api/application/circuitbreaker"
+)
+
+// passThroughBreaker executes every call directly, or fails them all when open.
+type passThroughBreaker struct{ open bool }
+
+func (b passThroughBreaker) Execute(ctx context.Context, _ string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if b.open {
+		return nil, errors.New("circuit open")
+	}
+	return fn(ctx)
+}
+
+func (b passThroughBreaker) ExecuteWithFallback(ctx context.Context, name string, fn func(ctx context.Context) (interface{}, error), fallback func(ctx context.Context, err error) (interface{}, error)) (interface{}, error) {
+	result, err := b.Execute(ctx, name, fn)
+	if err != nil {
+		return fallback(ctx, err)
+	}
+	return result, nil
+}
+
+func (passThroughBreaker) GetState(_ string) appcb.State { return appcb.StateClosed }
+
+func (passThroughBreaker) Reset(_ string) {}
+
+func (passThroughBreaker) ResetAll() {}
+
+func (passThroughBreaker) List() []appcb.BreakerStatus { return nil }
+
+type profile struct {
+	ID   string
+	Name string
+}
+
+func TestExecuteTyped(t *testing.T) {
+	ctx := context.Background()
+
+	got, err := appcb.ExecuteTyped(ctx, passThroughBreaker{}, "profiles", func(_ context.Context) (profile, error) {
+		return profile{ID: "1", Name: "Ada"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "Ada" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+
+	got, err = appcb.ExecuteTyped(ctx, passThroughBreaker{open: true}, "profiles", func(_ context.Context) (profile, error) {
+		t.Fatalf("fn must not run when the circuit is open")
+		return profile{}, nil
+	})
+	if err == nil {
+		t.Fatalf("expected error from open circuit")
+	}
+	if got != (profile{}) {
+		t.Fatalf("expected zero value on error, got %+v", got)
+	}
+}
@@ -0,0 +1,4 @@
+// Package events defines a small publish/subscribe port for domain events, so
+// producers and consumers do not depend on a specific broker. See
+// infrastructure/events for an in-memory adapter.
+package events
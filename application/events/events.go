@@ -0,0 +1,59 @@
+// Package events defines the abstract interface (PORT) for publishing and
+// subscribing to events.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a message published on a topic.
+type Event struct {
+	// Topic names the kind of event, e.g. "item.created".
+	Topic string
+
+	// Payload carries the event data.
+	Payload interface{}
+
+	// OccurredAt is when the event happened. Publish sets it to the current
+	// time when zero.
+	OccurredAt time.Time
+}
+
+// Handler processes an event delivered to a subscriber.
+type Handler func(ctx context.Context, event Event) error
+
+// EventBus defines the interface for publishing and subscribing to events.
+type EventBus interface {
+	// Publish delivers event to every handler subscribed to its topic.
+	// Whether it waits for the handlers, and so reports their errors,
+	// depends on the implementation and its configuration.
+	Publish(ctx context.Context, event Event) error
+
+	// Subscribe registers handler for events on topic. The returned function
+	// removes the subscription.
+	Subscribe(topic string, handler Handler) (unsubscribe func())
+
+	// Close stops accepting events and waits, until ctx is done, for the
+	// events already published to be delivered.
+	Close(ctx context.Context) error
+}
+
+// Config holds configuration for event buses.
+type Config struct {
+	// Workers is the number of goroutines delivering events. Zero delivers
+	// synchronously in Publish, which then returns the handlers' errors.
+	Workers int
+
+	// QueueSize is the number of published events that may wait for a
+	// worker before Publish blocks. Only used when Workers is positive.
+	QueueSize int
+}
+
+// DefaultConfig returns the default configuration for event buses.
+func DefaultConfig() Config {
+	return Config{
+		Workers:   0,
+		QueueSize: 100,
+	}
+}
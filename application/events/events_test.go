@@ -0,0 +1,17 @@
+package events_test
+
+import (
+	"testing"
+
+	appevents "github.com/next-trace/scg-service-api/application/events"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := appevents.DefaultConfig()
+	if cfg.Workers != 0 {
+		t.Fatalf("expected synchronous delivery by default, got %d workers", cfg.Workers)
+	}
+	if cfg.QueueSize != 100 {
+		t.Fatalf("unexpected default QueueSize: %d", cfg.QueueSize)
+	}
+}
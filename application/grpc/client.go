@@ -19,12 +19,30 @@ type Client interface {
 	GetConnection() interface{}
 }
 
+// Pinger is implemented by clients that can check the server is reachable
+// and serving.
+type Pinger interface {
+	// Ping returns an error unless the server answers and reports itself
+	// as serving.
+	Ping(ctx context.Context) error
+}
+
+// ConnectionMonitor is implemented by clients that can watch their
+// connection in the background, logging state transitions and nudging a
+// reconnect when the connection drops.
+type ConnectionMonitor interface {
+	// MonitorConnection blocks until ctx is done or the client is closed;
+	// run it in its own goroutine.
+	MonitorConnection(ctx context.Context)
+}
+
 // ClientConfig holds configuration for gRPC clients.
 type ClientConfig struct {
 	// Target is the server address in the format "host:port".
 	Target string
 
-	// Timeout is the timeout for connection establishment.
+	// Timeout is the timeout for connection establishment, and the deadline
+	// given to unary calls made with a context that has none.
 	Timeout time.Duration
 
 	// MaxRecvMsgSize is the maximum message size the client can receive.
@@ -47,6 +65,10 @@ type ClientConfig struct {
 
 	// TLSCertPath is the path to the TLS certificate file.
 	TLSCertPath string
+
+	// PoolSize is the number of connections a pooled client keeps to Target.
+	// Values below 1 are treated as 1.
+	PoolSize int
 }
 
 // DefaultClientConfig returns the default configuration for a gRPC client.
@@ -61,5 +83,6 @@ func DefaultClientConfig() ClientConfig {
 		RetryBackoff:     time.Second * 1,
 		EnableTLS:        false,
 		TLSCertPath:      "",
+		PoolSize:         1,
 	}
 }
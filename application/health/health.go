@@ -20,6 +20,19 @@ const (
 	StatusDegraded Status = "DEGRADED"
 )
 
+// ResponseFormat selects the JSON body of a health endpoint.
+type ResponseFormat string
+
+const (
+	// ResponseFormatFull reports the overall status, a timestamp and every
+	// check result. It is used when no format is configured.
+	ResponseFormatFull ResponseFormat = "full"
+
+	// ResponseFormatSimple reports only the overall status, e.g.
+	// {"status":"UP"}, for probe tooling that expects a flat body.
+	ResponseFormatSimple ResponseFormat = "simple"
+)
+
 // CheckType represents the type of health check.
 type CheckType string
 
@@ -66,6 +79,21 @@ type Registry interface {
 	GetChecks(checkType CheckType) map[string]Check
 }
 
+// TimeoutRegistry is a Registry that can also give a single check its own
+// timeout, overriding Config.Timeout for that check only. Use it for checks
+// such as a database ping that may legitimately take longer than the rest.
+type TimeoutRegistry interface {
+	Registry
+
+	// RegisterCheckWithTimeout registers a health check that may run for up
+	// to timeout, regardless of the global timeout.
+	RegisterCheckWithTimeout(name string, checkType CheckType, timeout time.Duration, check Check)
+
+	// CheckTimeout returns the timeout registered for a check, or 0 if it
+	// uses the global one.
+	CheckTimeout(name string, checkType CheckType) time.Duration
+}
+
 // Handler defines the interface for handling health check requests.
 type Handler interface {
 	// LivenessHandler returns an HTTP handler for liveness checks.
@@ -94,6 +122,12 @@ type Config struct {
 
 	// Timeout is the maximum time to wait for a health check to complete.
 	Timeout time.Duration
+
+	// ProbeFormat is the response format of the liveness and readiness endpoints.
+	ProbeFormat ResponseFormat
+
+	// Format is the response format of the aggregate endpoint at Path.
+	Format ResponseFormat
 }
 
 // DefaultConfig returns the default configuration for health checks.
@@ -104,5 +138,7 @@ func DefaultConfig() Config {
 		LivenessPath:  "/health/liveness",
 		ReadinessPath: "/health/readiness",
 		Timeout:       time.Second * 5,
+		ProbeFormat:   ResponseFormatFull,
+		Format:        ResponseFormatFull,
 	}
 }
@@ -18,4 +18,7 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Timeout != 5*time.Second {
 		t.Fatalf("unexpected default Timeout: %v", cfg.Timeout)
 	}
+	if cfg.ProbeFormat != health.ResponseFormatFull || cfg.Format != health.ResponseFormatFull {
+		t.Fatalf("expected full response formats by default, got %q and %q", cfg.ProbeFormat, cfg.Format)
+	}
 }
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+// IfMatch returns the entity tags of the request's If-Match header without
+// their quotes, for use as the expected versions of a conditional update
+// (RFC 9110 section 13.1.1): the update may proceed when the current version
+// is any one of them. It returns nil when the header is absent or is "*",
+// both of which accept any current version. Weak tags keep their W/ prefix,
+// so they never equal a version: If-Match requires a strong match. Tags in
+// repeated If-Match headers are combined into one list.
+func IfMatch(r *http.Request) []string {
+	var tags []string
+	for _, value := range r.Header.Values("If-Match") {
+		if strings.TrimSpace(value) == "*" {
+			return nil
+		}
+		tags = appendEntityTags(tags, value)
+	}
+	return tags
+}
+
+// appendEntityTags appends the tags of a comma-separated entity-tag list to
+// tags. A quoted tag may itself contain commas. Unquoted values, which the
+// RFC does not allow, are accepted as they are for lenient clients.
+func appendEntityTags(tags []string, list string) []string {
+	for {
+		list = strings.TrimLeft(list, " \t,")
+		if list == "" {
+			return tags
+		}
+
+		weak := ""
+		if strings.HasPrefix(list, "W/") {
+			weak, list = "W/", list[2:]
+		}
+		if rest, ok := strings.CutPrefix(list, `"`); ok {
+			if tag, after, closed := strings.Cut(rest, `"`); closed {
+				if weak != "" {
+					tag = weak + `"` + tag + `"`
+				}
+				tags = append(tags, tag)
+				list = after
+				continue
+			}
+		}
+
+		tag, after, _ := strings.Cut(list, ",")
+		tags = append(tags, weak+strings.TrimSpace(tag))
+		list = after
+	}
+}
+
+// ETag formats version as a strong entity tag for the ETag response header,
+// the form clients send back in If-Match.
+func ETag(version string) string {
+	return `"` + version + `"`
+}
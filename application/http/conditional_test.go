@@ -0,0 +1,45 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		want    []string
+	}{
+		{"absent", nil, nil},
+		{"any", []string{"*"}, nil},
+		{"strong", []string{`"42"`}, []string{"42"}},
+		{"unquoted", []string{"42"}, []string{"42"}},
+		{"weak", []string{`W/"42"`}, []string{`W/"42"`}},
+		{"list", []string{`"42", "43"`}, []string{"42", "43"}},
+		{"list without spaces", []string{`"42","43",W/"44"`}, []string{"42", "43", `W/"44"`}},
+		{"comma inside a tag", []string{`"a,b", "c"`}, []string{"a,b", "c"}},
+		{"empty elements", []string{` , "42" ,, `}, []string{"42"}},
+		{"repeated headers", []string{`"42"`, `"43"`}, []string{"42", "43"}},
+		{"any among repeated headers", []string{`"42"`, "*"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/items/1", nil)
+			for _, h := range tt.headers {
+				r.Header.Add("If-Match", h)
+			}
+			assert.Equal(t, tt.want, apphttp.IfMatch(r))
+		})
+	}
+}
+
+func TestETag_RoundTripsThroughIfMatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPut, "/items/1", nil)
+	r.Header.Set("If-Match", apphttp.ETag("1700000000"))
+	assert.Equal(t, []string{"1700000000"}, apphttp.IfMatch(r))
+}
@@ -10,10 +10,12 @@
 //   - RequestDecoder abstracts deserialization concerns.
 //   - ResponseWriter standardizes success and error payloads.
 //   - Run helper starts an http.Server and performs graceful shutdown upon context cancel or SIGINT/SIGTERM.
+//   - RunListener does the same on a given listener; with a DrainingListener new connections are
+//     refused as soon as shutdown begins while in-flight requests finish.
 //
 // Quickstart
 //
-//	logger := infralog.NewSlogAdapter(os.Stdout, "info")
+//	logger := infralog.New(infralog.WithOutput(os.Stdout), infralog.WithLevel("info"))
 //	mux := http.NewServeMux()
 //	srv := &nethttp.Server{Addr: ":8080", Handler: mux}
 //	ctx := context.Background()
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net"
+	"sync"
+)
+
+// DrainingListener wraps a net.Listener so that new connections can be
+// refused before the server shuts down. After Drain the underlying listener
+// is closed, so connection attempts are refused by the operating system,
+// while connections that were already accepted keep being served.
+//
+// Unlike closing the listener directly, draining does not make the server's
+// Serve loop fail: Accept blocks until Close is called, which http.Server
+// does during Shutdown. RunListener drains it when shutdown begins; Drain can
+// also be called earlier, e.g. when a load balancer has been told to stop
+// sending traffic.
+type DrainingListener struct {
+	net.Listener
+
+	drainOnce sync.Once
+	draining  chan struct{}
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewDrainingListener wraps ln.
+func NewDrainingListener(ln net.Listener) *DrainingListener {
+	return &DrainingListener{
+		Listener: ln,
+		draining: make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Accept waits for the next connection. Once the listener is draining it
+// blocks until Close and then returns net.ErrClosed.
+func (l *DrainingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil && l.Draining() {
+		<-l.closed
+		return nil, net.ErrClosed
+	}
+	return conn, err
+}
+
+// Drain stops accepting new connections. It is safe to call more than once.
+func (l *DrainingListener) Drain() {
+	_ = l.drain()
+}
+
+// Draining reports whether Drain or Close has been called.
+func (l *DrainingListener) Draining() bool {
+	select {
+	case <-l.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close drains the listener, if it was not drained yet, and releases Accept.
+func (l *DrainingListener) Close() error {
+	err := l.drain()
+	l.closeOnce.Do(func() { close(l.closed) })
+	return err
+}
+
+// drain closes the underlying listener the first time it is called and
+// returns the error from closing it; later calls return nil.
+func (l *DrainingListener) drain() error {
+	var err error
+	l.drainOnce.Do(func() {
+		close(l.draining)
+		err = l.Listener.Close()
+	})
+	return err
+}
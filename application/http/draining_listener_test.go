@@ -0,0 +1,108 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+)
+
+func TestDrainingListener_RefusesNewConnectionsAfterDrain(t *testing.T) {
+	lc := net.ListenConfig{}
+	inner, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ln := apphttp.NewDrainingListener(inner)
+	addr := ln.Addr().String()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(entered)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() { runDone <- apphttp.RunListener(ctx, srv, ln, simpleLogger{}) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	inFlight := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		inFlight <- result{body: string(body), err: err}
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("request never reached the handler")
+	}
+
+	ln.Drain()
+	dialer := net.Dialer{Timeout: time.Second}
+	if conn, err := dialer.DialContext(context.Background(), "tcp", addr); err == nil {
+		_ = conn.Close()
+		t.Fatalf("expected new connections to be refused after Drain")
+	}
+
+	close(release)
+	res := <-inFlight
+	if res.err != nil || res.body != "done" {
+		t.Fatalf("expected the in-flight request to complete, got %q, %v", res.body, res.err)
+	}
+
+	cancel()
+	select {
+	case err := <-runDone:
+		if err != nil {
+			t.Fatalf("expected graceful shutdown after Drain, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for shutdown")
+	}
+}
+
+func TestRunListener_DrainsOnShutdown(t *testing.T) {
+	lc := net.ListenConfig{}
+	inner, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ln := apphttp.NewDrainingListener(inner)
+	srv := &http.Server{Handler: http.NewServeMux(), ReadHeaderTimeout: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() { runDone <- apphttp.RunListener(ctx, srv, ln, simpleLogger{}) }()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-runDone:
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for shutdown")
+	}
+	if !ln.Draining() {
+		t.Fatalf("expected RunListener to drain the listener on shutdown")
+	}
+}
@@ -41,6 +41,14 @@ func (m *MockResponseWriter) Error(w http.ResponseWriter, r *http.Request, err e
 	m.Called(w, r, err)
 }
 
+func (m *MockResponseWriter) ErrorWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	m.Called(w, r, statusCode, err)
+}
+
+func (m *MockResponseWriter) NoContent(w http.ResponseWriter, r *http.Request, statusCode int) {
+	m.Called(w, r, statusCode)
+}
+
 // TestData is a sample data structure for testing
 type TestData struct {
 	ID   int    `json:"id"`
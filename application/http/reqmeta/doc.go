@@ -0,0 +1,4 @@
+// Package reqmeta stores request metadata (request ID, tenant, user ID and hop
+// count) in a request's context under typed keys, so middlewares and handlers
+// share one set of setters and getters instead of ad-hoc context keys.
+package reqmeta
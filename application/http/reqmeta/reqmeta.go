@@ -0,0 +1,61 @@
+package reqmeta
+
+import (
+	"context"
+
+	"github.com/next-trace/scg-service-api/application/tenant"
+)
+
+// Unexported key types cannot collide with context keys of other packages.
+type (
+	requestIDKey struct{}
+	userIDKey    struct{}
+	hopCountKey  struct{}
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithTenant returns a copy of ctx carrying the tenant ID. It is stored with
+// tenant.WithTenant, so tenant-scoped keys see it too.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return tenant.WithTenant(ctx, tenantID)
+}
+
+// Tenant returns the tenant ID stored in ctx, or "" if there is none.
+func Tenant(ctx context.Context) string {
+	tenantID, _ := tenant.FromContext(ctx)
+	return tenantID
+}
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID.
+// Authentication middleware calls it once the caller is known.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserID returns the user ID stored in ctx, or "" if there is none.
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
+
+// WithHopCount returns a copy of ctx carrying the number of service hops
+// the request has made, counting the current one.
+func WithHopCount(ctx context.Context, hops int) context.Context {
+	return context.WithValue(ctx, hopCountKey{}, hops)
+}
+
+// HopCount returns the hop count stored in ctx, or 0 if there is none.
+func HopCount(ctx context.Context) int {
+	hops, _ := ctx.Value(hopCountKey{}).(int)
+	return hops
+}
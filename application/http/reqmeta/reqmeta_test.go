@@ -0,0 +1,49 @@
+package reqmeta_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	"github.com/next-trace/scg-service-api/application/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, reqmeta.RequestID(ctx))
+	assert.Empty(t, reqmeta.Tenant(ctx))
+	assert.Empty(t, reqmeta.UserID(ctx))
+	assert.Zero(t, reqmeta.HopCount(ctx))
+
+	ctx = reqmeta.WithRequestID(ctx, "req-1")
+	ctx = reqmeta.WithTenant(ctx, "acme")
+	ctx = reqmeta.WithUserID(ctx, "user-7")
+	ctx = reqmeta.WithHopCount(ctx, 3)
+
+	assert.Equal(t, "req-1", reqmeta.RequestID(ctx))
+	assert.Equal(t, "acme", reqmeta.Tenant(ctx))
+	assert.Equal(t, "user-7", reqmeta.UserID(ctx))
+	assert.Equal(t, 3, reqmeta.HopCount(ctx))
+
+	// The tenant is shared with the tenant package.
+	tenantID, ok := tenant.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenantID)
+}
+
+func TestNoCollisions(t *testing.T) {
+	type otherKey string
+
+	ctx := context.WithValue(context.Background(), otherKey("request_id"), "other")
+	ctx = context.WithValue(ctx, "user_id", "other") //nolint:staticcheck // deliberately an untyped key
+	assert.Empty(t, reqmeta.RequestID(ctx))
+	assert.Empty(t, reqmeta.UserID(ctx))
+
+	ctx = reqmeta.WithRequestID(ctx, "req-1")
+	ctx = reqmeta.WithUserID(ctx, "req-1-user")
+	assert.Equal(t, "other", ctx.Value(otherKey("request_id")))
+	assert.Equal(t, "other", ctx.Value("user_id"))
+	assert.Equal(t, "req-1", reqmeta.RequestID(ctx))
+	assert.Equal(t, "req-1-user", reqmeta.UserID(ctx))
+}
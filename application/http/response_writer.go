@@ -1,6 +1,9 @@
 package http
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // ResponseWriter defines the abstract interface (PORT) for encoding (serializing)
 // data and writing it as a standardized HTTP response.
@@ -10,4 +13,33 @@ type ResponseWriter interface {
 
 	// Error sends a standard structured error response.
 	Error(w http.ResponseWriter, r *http.Request, err error)
+
+	// ErrorWithStatus sends a standard structured error response with an explicit
+	// status code, overriding the status that would be inferred from err.
+	ErrorWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, err error)
+
+	// NoContent sends only the status code, with neither a body nor a
+	// Content-Type, e.g. for 204 No Content and 304 Not Modified.
+	NoContent(w http.ResponseWriter, r *http.Request, statusCode int)
+}
+
+type responderKey struct{}
+
+// ContextWithResponder returns a copy of ctx carrying rw. Middleware uses it
+// so handlers can render with the configured ResponseWriter via Responder.
+func ContextWithResponder(ctx context.Context, rw ResponseWriter) context.Context {
+	return context.WithValue(ctx, responderKey{}, rw)
+}
+
+// Responder returns the ResponseWriter stored with ContextWithResponder, or
+// nil if there is none.
+func Responder(ctx context.Context) ResponseWriter {
+	rw, _ := ctx.Value(responderKey{}).(ResponseWriter)
+	return rw
 }
+
+// ErrorHandler is an HTTP handler that returns its failure instead of
+// rendering it. An adapter turns it into an http.Handler that renders a
+// returned error with a ResponseWriter's Error, so the handler does not have
+// to; a handler must therefore not write a response when it returns an error.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request) error
@@ -2,27 +2,88 @@ package http
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 	"time"
 
 	applogger "github.com/next-trace/scg-service-api/application/logger"
 )
 
+// DefaultShutdownTimeout is the grace period Run gives in-flight requests
+// once shutdown begins.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// ErrShutdownTimeout is returned by Run when in-flight requests did not finish
+// within the grace period and their connections were closed forcibly. The
+// error returned wraps both ErrShutdownTimeout and the cause.
+var ErrShutdownTimeout = errors.New("http server shutdown timed out")
+
+// RunOption customizes Run and RunListener.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	shutdownTimeout time.Duration
+	middleware      []func(http.Handler) http.Handler
+}
+
+// WithShutdownTimeout sets the grace period for in-flight requests, replacing
+// DefaultShutdownTimeout.
+func WithShutdownTimeout(d time.Duration) RunOption {
+	return func(o *runOptions) { o.shutdownTimeout = d }
+}
+
+// WithMiddleware wraps the server's handler (http.DefaultServeMux if nil)
+// while serving, the first middleware outermost. Run then serves a copy of
+// the server carrying the wrapped handler and leaves the caller's server
+// untouched, so it can be run again without being wrapped twice; stop it by
+// cancelling the context rather than calling its Shutdown. Use it for
+// wrappers that take over part of the traffic, such as a gRPC-Web wrapper.
+func WithMiddleware(middleware ...func(http.Handler) http.Handler) RunOption {
+	return func(o *runOptions) { o.middleware = append(o.middleware, middleware...) }
+}
+
+func newRunOptions(opts []RunOption) runOptions {
+	o := runOptions{shutdownTimeout: DefaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
 // Run starts the given http.Server and performs a graceful shutdown on SIGINT/SIGTERM.
 //
 // Behavior:
-// - Starts srv.ListenAndServe() in a goroutine.
-// - Listens for OS signals (os.Interrupt, syscall.SIGTERM) and context cancellation.
-// - When a shutdown trigger occurs, logs a message and calls srv.Shutdown with a 30s timeout.
-// - Returns the first non-nil error from ListenAndServe (other than http.ErrServerClosed) or from Shutdown.
-func Run(ctx context.Context, srv *http.Server, log applogger.Logger) error {
+//   - Starts srv.ListenAndServe() in a goroutine.
+//   - Listens for OS signals (os.Interrupt, syscall.SIGTERM) and context cancellation.
+//   - When a shutdown trigger occurs, logs a message and calls srv.Shutdown with a 30s timeout
+//     (see WithShutdownTimeout).
+//   - Returns the first non-nil error from ListenAndServe (other than http.ErrServerClosed) or from Shutdown.
+//   - If requests are still running when the timeout expires, their connections are closed and
+//     an error wrapping ErrShutdownTimeout is returned.
+//
+// A failure to start, such as the address already being in use, is always returned;
+// http.ErrServerClosed caused by the graceful shutdown is reported as success.
+// The logger is flushed before Run returns, whether shutdown completed or the
+// server failed to start, so buffered entries are not lost.
+// If ctx is already done, Run returns nil at once without starting the server.
+func Run(ctx context.Context, srv *http.Server, log applogger.Logger, opts ...RunOption) error {
+	log = applogger.OrNop(log)
 	if srv == nil {
 		return nil
 	}
 
+	// Starting only to shut down again would race the listener against Shutdown
+	if ctx.Err() != nil {
+		log.Info(ctx, "context already done, not starting HTTP server")
+		return nil
+	}
+
 	// Log server start if address is known
 	if srv.Addr != "" {
 		log.InfoKV(ctx, "starting HTTP server", map[string]interface{}{"address": srv.Addr})
@@ -30,11 +91,58 @@ func Run(ctx context.Context, srv *http.Server, log applogger.Logger) error {
 		log.Info(ctx, "starting HTTP server")
 	}
 
+	return run(ctx, srv, log, (*http.Server).ListenAndServe, nil, newRunOptions(opts))
+}
+
+// RunListener is Run for a server that accepts connections on ln rather than
+// listening on srv.Addr itself. If ln is a DrainingListener it is drained as
+// soon as shutdown begins, so new connections are refused while in-flight
+// requests finish.
+func RunListener(ctx context.Context, srv *http.Server, ln net.Listener, log applogger.Logger, opts ...RunOption) error {
+	log = applogger.OrNop(log)
+	if srv == nil || ln == nil {
+		return nil
+	}
+
+	// Starting only to shut down again would race Serve against Shutdown
+	if ctx.Err() != nil {
+		log.Info(ctx, "context already done, not starting HTTP server")
+		_ = ln.Close()
+		return nil
+	}
+
+	log.InfoKV(ctx, "starting HTTP server", map[string]interface{}{"address": ln.Addr().String()})
+
+	var drain func()
+	if dl, ok := ln.(*DrainingListener); ok {
+		drain = dl.Drain
+	}
+	return run(ctx, srv, log, func(s *http.Server) error { return s.Serve(ln) }, drain, newRunOptions(opts))
+}
+
+// run serves srv with serve until ctx is done or a termination signal arrives,
+// then calls drain, if set, and shuts srv down gracefully.
+func run(ctx context.Context, srv *http.Server, log applogger.Logger, serve func(*http.Server) error, drain func(), opts runOptions) error {
+	// Flush buffered log entries last, including after a failure to start; a
+	// failed flush has nowhere left to be reported
+	defer func() { _ = log.Flush() }()
+
+	if len(opts.middleware) > 0 {
+		handler := srv.Handler
+		if handler == nil {
+			handler = http.DefaultServeMux
+		}
+		for i := len(opts.middleware) - 1; i >= 0; i-- {
+			handler = opts.middleware[i](handler)
+		}
+		srv = withHandler(srv, handler)
+	}
+
 	errCh := make(chan error, 1)
 
 	// Start the HTTP server
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := serveError(serve(srv)); err != nil {
 			errCh <- err
 		}
 		close(errCh)
@@ -61,16 +169,58 @@ func Run(ctx context.Context, srv *http.Server, log applogger.Logger) error {
 		return nil
 	}
 
+	// Refuse new connections before waiting for in-flight requests
+	if drain != nil {
+		drain()
+	}
+
 	// Perform graceful shutdown with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.shutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
+	err := srv.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		// Requests outlived the grace period; cut them off so Run can return
+		log.Error(ctx, err, "http server shutdown timed out, closing remaining connections")
+		_ = srv.Close()
+		<-errCh
+		return fmt.Errorf("%w: %w", ErrShutdownTimeout, err)
+	}
+	if err != nil {
 		// Log and return shutdown error
 		log.Error(ctx, err, "http server shutdown error")
 		return err
 	}
 
+	// ListenAndServe returns promptly once Shutdown has closed the listeners
+	if err := <-errCh; err != nil {
+		log.Error(ctx, err, "http server error")
+		return err
+	}
+
 	log.Info(ctx, "http server shutdown complete")
 	return nil
 }
+
+// withHandler returns a server configured like srv that serves handler.
+// Only the exported configuration is copied, so the two share no state.
+func withHandler(srv *http.Server, handler http.Handler) *http.Server {
+	clone := &http.Server{}
+	src, dst := reflect.ValueOf(srv).Elem(), reflect.ValueOf(clone).Elem()
+	for i := range src.NumField() {
+		if src.Type().Field(i).IsExported() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+	clone.Handler = handler
+	return clone
+}
+
+// serveError classifies the error returned by ListenAndServe. http.ErrServerClosed
+// only signals that Shutdown or Close was called and is not a failure.
+func serveError(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+// Defaults applied by NewServer. ReadHeaderTimeout in particular guards
+// against Slowloris-style clients that hold connections by trickling headers.
+const (
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultMaxHeaderBytes    = 1 << 20
+)
+
+// ServerOption customizes the server built by NewServer.
+type ServerOption func(*http.Server)
+
+// WithReadHeaderTimeout sets the time allowed to read request headers.
+func WithReadHeaderTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.ReadHeaderTimeout = d }
+}
+
+// WithReadTimeout sets the time allowed to read the entire request.
+func WithReadTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.ReadTimeout = d }
+}
+
+// WithWriteTimeout sets the time allowed to write the response. Use 0 for
+// servers with long-lived streaming responses.
+func WithWriteTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.WriteTimeout = d }
+}
+
+// WithIdleTimeout sets how long keep-alive connections may stay idle.
+func WithIdleTimeout(d time.Duration) ServerOption {
+	return func(s *http.Server) { s.IdleTimeout = d }
+}
+
+// WithMaxHeaderBytes sets the maximum size of request headers.
+func WithMaxHeaderBytes(n int) ServerOption {
+	return func(s *http.Server) { s.MaxHeaderBytes = n }
+}
+
+// WithH2C enables HTTP/2 over cleartext TCP (h2c) alongside HTTP/1.1, for
+// services behind a load balancer that terminates TLS but speaks HTTP/2 to
+// the backend. Clients must use prior knowledge; the HTTP/1.1 Upgrade
+// handshake is not supported.
+func WithH2C() ServerOption {
+	return func(s *http.Server) {
+		// Start from the configured protocols, or the default HTTP/1.1 plus
+		// HTTP/2 over TLS, and add cleartext HTTP/2.
+		protocols := new(http.Protocols)
+		if s.Protocols != nil {
+			*protocols = *s.Protocols
+		} else {
+			protocols.SetHTTP1(true)
+			protocols.SetHTTP2(true)
+		}
+		protocols.SetUnencryptedHTTP2(true)
+		s.Protocols = protocols
+	}
+}
+
+// WithServerConfig applies arbitrary changes, e.g. TLSConfig or ErrorLog, for
+// settings without a dedicated option.
+func WithServerConfig(fn func(*http.Server)) ServerOption {
+	return fn
+}
+
+// NewServer builds an *http.Server for Run with safe defaults: every timeout
+// and the header size limit are set (see the Default constants) unless an
+// option overrides them.
+func NewServer(addr string, handler http.Handler, opts ...ServerOption) *http.Server {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		ReadTimeout:       DefaultReadTimeout,
+		WriteTimeout:      DefaultWriteTimeout,
+		IdleTimeout:       DefaultIdleTimeout,
+		MaxHeaderBytes:    DefaultMaxHeaderBytes,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(srv)
+		}
+	}
+	return srv
+}
@@ -0,0 +1,117 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+)
+
+func TestNewServer_Defaults(t *testing.T) {
+	handler := http.NotFoundHandler()
+	srv := apphttp.NewServer(":8080", handler)
+
+	if srv.Addr != ":8080" || srv.Handler == nil {
+		t.Fatalf("unexpected addr/handler: %q, %v", srv.Addr, srv.Handler)
+	}
+	if srv.ReadHeaderTimeout != apphttp.DefaultReadHeaderTimeout {
+		t.Fatalf("unexpected ReadHeaderTimeout: %v", srv.ReadHeaderTimeout)
+	}
+	if srv.ReadTimeout != apphttp.DefaultReadTimeout {
+		t.Fatalf("unexpected ReadTimeout: %v", srv.ReadTimeout)
+	}
+	if srv.WriteTimeout != apphttp.DefaultWriteTimeout {
+		t.Fatalf("unexpected WriteTimeout: %v", srv.WriteTimeout)
+	}
+	if srv.IdleTimeout != apphttp.DefaultIdleTimeout {
+		t.Fatalf("unexpected IdleTimeout: %v", srv.IdleTimeout)
+	}
+	if srv.MaxHeaderBytes != apphttp.DefaultMaxHeaderBytes {
+		t.Fatalf("unexpected MaxHeaderBytes: %d", srv.MaxHeaderBytes)
+	}
+}
+
+func TestNewServer_Overrides(t *testing.T) {
+	srv := apphttp.NewServer(":8080", http.NotFoundHandler(),
+		apphttp.WithReadHeaderTimeout(2*time.Second),
+		apphttp.WithWriteTimeout(0),
+		apphttp.WithMaxHeaderBytes(4096),
+		apphttp.WithServerConfig(func(s *http.Server) { s.DisableGeneralOptionsHandler = true }),
+	)
+
+	if srv.ReadHeaderTimeout != 2*time.Second {
+		t.Fatalf("unexpected ReadHeaderTimeout: %v", srv.ReadHeaderTimeout)
+	}
+	if srv.WriteTimeout != 0 {
+		t.Fatalf("unexpected WriteTimeout: %v", srv.WriteTimeout)
+	}
+	if srv.MaxHeaderBytes != 4096 {
+		t.Fatalf("unexpected MaxHeaderBytes: %d", srv.MaxHeaderBytes)
+	}
+	if !srv.DisableGeneralOptionsHandler {
+		t.Fatalf("expected WithServerConfig to be applied")
+	}
+	if srv.IdleTimeout != apphttp.DefaultIdleTimeout {
+		t.Fatalf("expected untouched defaults to remain, got IdleTimeout %v", srv.IdleTimeout)
+	}
+}
+
+func TestNewServer_H2C(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+	srv := apphttp.NewServer(addr, handler, apphttp.WithH2C())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- apphttp.Run(ctx, srv, simpleLogger{}) }()
+
+	get := func(client *http.Client) string {
+		t.Helper()
+		var resp *http.Response
+		var err error
+		for i := 0; i < 50; i++ {
+			if resp, err = client.Get("http://" + addr + "/"); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	h2c := &http.Transport{Protocols: new(http.Protocols)}
+	h2c.Protocols.SetUnencryptedHTTP2(true)
+	if proto := get(&http.Client{Transport: h2c}); proto != "HTTP/2.0" {
+		t.Fatalf("expected HTTP/2.0 with an h2c client, got %q", proto)
+	}
+	if proto := get(&http.Client{Transport: &http.Transport{}}); proto != "HTTP/1.1" {
+		t.Fatalf("expected HTTP/1.1 fallback, got %q", proto)
+	}
+	h2c.CloseIdleConnections()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected graceful shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for shutdown")
+	}
+}
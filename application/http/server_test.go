@@ -2,13 +2,16 @@ package http_test
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	apphttp "github.com/next-trace/scg-service-api/application/http"
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"github.com/next-trace/scg-service-api/testsupport"
 )
 
 // simpleLogger is a minimal test logger implementing applogger.Logger.
@@ -25,6 +28,18 @@ func (simpleLogger) WarnKV(context.Context, string, map[string]interface{})
 func (simpleLogger) ErrorKV(context.Context, error, string, map[string]interface{}) {}
 func (simpleLogger) FatalKV(context.Context, error, string, map[string]interface{}) {}
 func (simpleLogger) WithField(string, interface{}) applogger.Logger                 { return simpleLogger{} }
+func (simpleLogger) Flush() error                                                   { return nil }
+
+// flushRecordingLogger records whether Flush was called.
+type flushRecordingLogger struct {
+	simpleLogger
+	flushed atomic.Bool
+}
+
+func (l *flushRecordingLogger) Flush() error {
+	l.flushed.Store(true)
+	return nil
+}
 
 // TestRun_NilServer ensures nil server is a no-op.
 func TestRun_NilServer(t *testing.T) {
@@ -85,5 +100,250 @@ func TestRun_ContextCancel(t *testing.T) {
 	}
 }
 
+// TestRun_AddressInUse ensures a bind failure is reported as an error rather than
+// being mistaken for a normal close.
+func TestRun_AddressInUse(t *testing.T) {
+	t.Parallel()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: http.NewServeMux()}
+	log := &flushRecordingLogger{}
+	err = apphttp.Run(ctx, srv, log)
+	if err == nil {
+		t.Fatalf("expected bind error, got nil")
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("expected bind error, got ErrServerClosed")
+	}
+	if !log.flushed.Load() {
+		t.Fatalf("expected logger to be flushed after a failure to start")
+	}
+}
+
+// TestRun_ShutdownReturnsNil ensures ErrServerClosed from a graceful shutdown is reported as success.
+func TestRun_ShutdownReturnsNil(t *testing.T) {
+	t.Parallel()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{Addr: addr, Handler: http.NewServeMux()}
+	log := &flushRecordingLogger{}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- apphttp.Run(ctx, srv, log) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("expected nil error after graceful shutdown, got %v", err)
+		}
+		if !log.flushed.Load() {
+			t.Fatalf("expected logger to be flushed on shutdown")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timeout waiting for shutdown")
+	}
+}
+
 // Note: OS signal path is implicitly covered by context path since sending real signals in tests can be flaky.
 // The graceful shutdown logic is identical across both paths. We avoid manipulating process signals to keep tests stable and fast.
+
+// TestRun_CancelledContext ensures Run does not start the server when ctx is
+// already done.
+func TestRun_CancelledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The address is taken, so any attempt to bind it would fail with an error.
+	lc := net.ListenConfig{}
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	log := testsupport.NewLogger()
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: http.NewServeMux()}
+
+	done := make(chan error, 1)
+	go func() { done <- apphttp.Run(ctx, srv, log) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Run did not return promptly")
+	}
+	if _, started := log.Find("starting HTTP server"); started {
+		t.Fatalf("expected the server not to be started")
+	}
+}
+
+// TestRun_ShutdownTimeout ensures a request outliving the grace period makes
+// Run return ErrShutdownTimeout once its connection has been closed.
+func TestRun_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+	lc := net.ListenConfig{}
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	entered := make(chan struct{})
+	never := make(chan struct{})
+	t.Cleanup(func() { close(never) })
+	srv := &http.Server{
+		ReadHeaderTimeout: time.Second,
+		Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			close(entered)
+			<-never
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- apphttp.RunListener(ctx, srv, ln, simpleLogger{}, apphttp.WithShutdownTimeout(50*time.Millisecond))
+	}()
+
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("request never reached the handler")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, apphttp.ErrShutdownTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected ErrShutdownTimeout wrapping the deadline, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Run did not return after the grace period")
+	}
+}
+
+// TestRunListener_WithMiddleware ensures the middleware wraps the handler,
+// first outermost.
+func TestRunListener_WithMiddleware(t *testing.T) {
+	t.Parallel()
+	lc := net.ListenConfig{}
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	tag := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Order", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- apphttp.RunListener(ctx, srv, ln, simpleLogger{}, apphttp.WithMiddleware(tag("outer"), tag("inner")))
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := resp.Header.Values("X-Order"); len(got) != 2 || got[0] != "outer" || got[1] != "inner" {
+		t.Fatalf("expected middleware order [outer inner], got %v", got)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected the wrapped handler's status, got %d", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-runDone; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+// TestRun_WithMiddlewareDoesNotStack ensures that a server whose Run failed
+// to start is left unwrapped, so running it again wraps its handler once.
+func TestRun_WithMiddlewareDoesNotStack(t *testing.T) {
+	t.Parallel()
+	tag := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Wrapped", "1")
+			next.ServeHTTP(w, r)
+		})
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// The first attempt fails because the address is taken.
+	lc := net.ListenConfig{}
+	taken, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = taken.Close() })
+	srv := &http.Server{Addr: taken.Addr().String(), Handler: mux}
+	if err := apphttp.Run(context.Background(), srv, simpleLogger{}, apphttp.WithMiddleware(tag)); err == nil {
+		t.Fatalf("expected an error for an address in use")
+	}
+	if srv.Handler != mux {
+		t.Fatalf("expected the server's handler to be left untouched")
+	}
+
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- apphttp.RunListener(ctx, srv, ln, simpleLogger{}, apphttp.WithMiddleware(tag))
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := resp.Header.Values("X-Wrapped"); len(got) != 1 {
+		t.Fatalf("expected the handler to be wrapped once, got %v", got)
+	}
+
+	cancel()
+	if err := <-runDone; err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
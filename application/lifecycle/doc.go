@@ -0,0 +1,4 @@
+// Package lifecycle runs a service's long-lived components, such as servers,
+// background workers and telemetry exporters, as a group and shuts them down
+// in a controlled order.
+package lifecycle
@@ -0,0 +1,152 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// Shutdown priorities for the usual kinds of components. Lower priorities are
+// shut down first: servers stop accepting work, then workers drain, and
+// telemetry exporters go last so the shutdown of everything else is still
+// logged, measured and traced.
+const (
+	PriorityServer    = 0
+	PriorityWorker    = 100
+	PriorityTelemetry = 200
+)
+
+// DefaultShutdownTimeout bounds the whole shutdown sequence of a RunGroup.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Runnable is a long-lived component managed by a RunGroup.
+type Runnable interface {
+	// Run blocks until the component stops, either because Shutdown was
+	// called or because it failed.
+	Run(ctx context.Context) error
+
+	// Shutdown stops the component gracefully, making Run return.
+	Shutdown(ctx context.Context) error
+}
+
+// Prioritized is implemented by Runnables that declare their shutdown
+// priority. Runnables that do not implement it get PriorityWorker.
+type Prioritized interface {
+	ShutdownPriority() int
+}
+
+// WithShutdownPriority returns r with the given shutdown priority.
+func WithShutdownPriority(r Runnable, priority int) Runnable {
+	return prioritized{Runnable: r, priority: priority}
+}
+
+type prioritized struct {
+	Runnable
+	priority int
+}
+
+func (p prioritized) ShutdownPriority() int {
+	return p.priority
+}
+
+// RunGroup runs Runnables together. When the context is cancelled or any
+// member's Run returns, every member is shut down in ascending priority
+// order; members with the same priority are shut down in reverse order of
+// Add.
+type RunGroup struct {
+	// ShutdownTimeout bounds the shutdown sequence. Zero means
+	// DefaultShutdownTimeout.
+	ShutdownTimeout time.Duration
+
+	log     applogger.Logger
+	members []Runnable
+}
+
+// NewRunGroup creates an empty RunGroup.
+func NewRunGroup(log applogger.Logger) *RunGroup {
+	return &RunGroup{log: applogger.OrNop(log)}
+}
+
+// Add registers r with the group. It must be called before Run.
+func (g *RunGroup) Add(r Runnable) {
+	g.members = append(g.members, r)
+}
+
+// Run starts every member and blocks until all of them have returned. It
+// returns the first Run error, joined with any Shutdown errors; context
+// cancellation is a normal stop and is not reported.
+func (g *RunGroup) Run(ctx context.Context) error {
+	if len(g.members) == 0 {
+		return nil
+	}
+
+	runErrs := make(chan error, len(g.members))
+	var wg sync.WaitGroup
+	for _, m := range g.members {
+		wg.Add(1)
+		go func(m Runnable) {
+			defer wg.Done()
+			runErrs <- m.Run(ctx)
+		}(m)
+	}
+
+	var firstErr error
+	select {
+	case <-ctx.Done():
+		g.log.Info(ctx, "run group stopping (context canceled)")
+	case firstErr = <-runErrs:
+		if firstErr != nil {
+			g.log.Error(ctx, firstErr, "run group member failed, stopping")
+		} else {
+			g.log.Info(ctx, "run group member stopped, stopping")
+		}
+	}
+
+	errs := []error{firstErr}
+	errs = append(errs, g.shutdown(ctx)...)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// shutdown stops the members in shutdown order and returns their failures.
+func (g *RunGroup) shutdown(ctx context.Context) []error {
+	timeout := g.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, m := range g.shutdownOrder() {
+		if err := m.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down %T: %w", m, err))
+		}
+	}
+	return errs
+}
+
+// shutdownOrder returns the members sorted by ascending priority, latest
+// added first within a priority.
+func (g *RunGroup) shutdownOrder() []Runnable {
+	order := make([]Runnable, len(g.members))
+	for i, m := range g.members {
+		order[len(order)-1-i] = m
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return shutdownPriority(order[i]) < shutdownPriority(order[j])
+	})
+	return order
+}
+
+func shutdownPriority(r Runnable) int {
+	if p, ok := r.(Prioritized); ok {
+		return p.ShutdownPriority()
+	}
+	return PriorityWorker
+}
@@ -0,0 +1,88 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-service-api/application/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder collects the names of shut down components in order.
+type recorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *recorder) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+}
+
+// component runs until it is shut down, or fails with runErr right away.
+type component struct {
+	name   string
+	rec    *recorder
+	runErr error
+	stop   chan struct{}
+	once   sync.Once
+}
+
+func newComponent(name string, rec *recorder) *component {
+	return &component{name: name, rec: rec, stop: make(chan struct{})}
+}
+
+func (c *component) Run(context.Context) error {
+	if c.runErr != nil {
+		return c.runErr
+	}
+	<-c.stop
+	return nil
+}
+
+func (c *component) Shutdown(context.Context) error {
+	c.rec.add(c.name)
+	c.once.Do(func() { close(c.stop) })
+	return nil
+}
+
+func TestRunGroup_ShutdownByPriority(t *testing.T) {
+	rec := &recorder{}
+	g := lifecycle.NewRunGroup(nil)
+	g.Add(lifecycle.WithShutdownPriority(newComponent("metrics", rec), lifecycle.PriorityTelemetry))
+	g.Add(lifecycle.WithShutdownPriority(newComponent("http", rec), lifecycle.PriorityServer))
+	g.Add(lifecycle.WithShutdownPriority(newComponent("worker", rec), lifecycle.PriorityWorker))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- g.Run(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("run group did not stop")
+	}
+	assert.Equal(t, []string{"http", "worker", "metrics"}, rec.names)
+}
+
+func TestRunGroup_SamePriorityInReverseOrder(t *testing.T) {
+	rec := &recorder{}
+	g := lifecycle.NewRunGroup(nil)
+	g.Add(newComponent("first", rec))
+	g.Add(newComponent("second", rec))
+	g.Add(lifecycle.WithShutdownPriority(newComponent("exporter", rec), lifecycle.PriorityTelemetry))
+
+	failing := newComponent("failing", rec)
+	failing.runErr = errors.New("listen failed")
+	g.Add(failing)
+
+	err := g.Run(context.Background())
+	assert.ErrorIs(t, err, failing.runErr)
+	assert.Equal(t, []string{"failing", "second", "first", "exporter"}, rec.names)
+}
@@ -0,0 +1,46 @@
+package logger
+
+import "context"
+
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying fields that logger
+// implementations attach to every entry logged with that context. Fields
+// already on ctx are kept; new values win on key collisions.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	merged := make(map[string]interface{}, len(fields))
+	for k, v := range FieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the fields stored with ContextWithFields, or nil.
+// The returned map must not be modified.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+type contextLoggerKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying log, typically a
+// request-scoped logger derived with WithField.
+func ContextWithLogger(ctx context.Context, log Logger) context.Context {
+	return context.WithValue(ctx, contextLoggerKey{}, log)
+}
+
+// LoggerFromContext returns the logger stored with ContextWithLogger, or
+// Nop() if there is none.
+func LoggerFromContext(ctx context.Context) Logger {
+	if log, ok := ctx.Value(contextLoggerKey{}).(Logger); ok && log != nil {
+		return log
+	}
+	return Nop()
+}
@@ -21,4 +21,9 @@ type Logger interface {
 
 	// WithField returns a new logger with the field added to the logger's context
 	WithField(key string, value interface{}) Logger
+
+	// Flush writes out any buffered log entries. Backends that write synchronously
+	// return nil; buffered backends (e.g. zap's Sync) must be flushed on shutdown
+	// or the last entries are lost.
+	Flush() error
 }
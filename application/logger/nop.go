@@ -0,0 +1,33 @@
+package logger
+
+import "context"
+
+// nopLogger discards every entry.
+type nopLogger struct{}
+
+// Nop returns a Logger that discards everything, including Fatal entries
+// (it does not exit). Adapters use it when constructed with a nil logger.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+// OrNop returns log, or Nop() when log is nil.
+func OrNop(log Logger) Logger {
+	if log == nil {
+		return Nop()
+	}
+	return log
+}
+
+func (nopLogger) Debug(context.Context, string)                                  {}
+func (nopLogger) Info(context.Context, string)                                   {}
+func (nopLogger) Warn(context.Context, string)                                   {}
+func (nopLogger) Error(context.Context, error, string)                           {}
+func (nopLogger) Fatal(context.Context, error, string)                           {}
+func (nopLogger) DebugKV(context.Context, string, map[string]interface{})        {}
+func (nopLogger) InfoKV(context.Context, string, map[string]interface{})         {}
+func (nopLogger) WarnKV(context.Context, string, map[string]interface{})         {}
+func (nopLogger) ErrorKV(context.Context, error, string, map[string]interface{}) {}
+func (nopLogger) FatalKV(context.Context, error, string, map[string]interface{}) {}
+func (n nopLogger) WithField(string, interface{}) Logger                         { return n }
+func (nopLogger) Flush() error                                                   { return nil }
@@ -0,0 +1,29 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+func TestNop(t *testing.T) {
+	ctx := context.Background()
+	log := applogger.Nop()
+
+	log.Info(ctx, "discarded")
+	log.ErrorKV(ctx, errors.New("boom"), "discarded", map[string]interface{}{"k": "v"})
+	log.Fatal(ctx, errors.New("boom"), "does not exit")
+	log.WithField("k", "v").Debug(ctx, "discarded")
+	if err := log.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	if applogger.OrNop(nil) == nil {
+		t.Fatalf("expected OrNop(nil) to return a logger")
+	}
+	if got := applogger.OrNop(log); got != log {
+		t.Fatalf("expected OrNop to keep a non-nil logger")
+	}
+}
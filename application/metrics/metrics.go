@@ -3,9 +3,14 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrServerRunning is returned by Serve when the metrics server is already
+// serving; call Shutdown before serving again.
+var ErrServerRunning = errors.New("metrics server already running")
+
 // Metrics defines the interface for collecting metrics.
 type Metrics interface {
 	// Counter operations
@@ -49,13 +54,33 @@ type Metrics interface {
 	// WithLabels returns a new Metrics instance with the given labels.
 	WithLabels(labels map[string]string) Metrics
 
-	// Serve starts the metrics server on the given address.
+	// Serve starts the metrics server on the given address. It returns
+	// ErrServerRunning if the server is already serving.
 	Serve(ctx context.Context, addr string) error
 
-	// Shutdown gracefully shuts down the metrics server.
+	// Shutdown gracefully shuts down the metrics server and waits for it
+	// to stop, or for ctx to be done.
 	Shutdown(ctx context.Context) error
 }
 
+// ExemplarObserver is implemented by Metrics backends that can attach an
+// exemplar, such as the trace and request IDs of the observed request, to a
+// histogram observation. Callers check for it with a type assertion and fall
+// back to HistogramObserve.
+type ExemplarObserver interface {
+	// HistogramObserveWithExemplar adds an observation labeled with exemplar.
+	HistogramObserveWithExemplar(name string, value float64, exemplar map[string]string)
+}
+
+// ReadySignaler is implemented by Metrics backends that can hold back the
+// metrics endpoint until the application has registered its metrics. With
+// Config.DeferReady set, scrapes are answered 503 Service Unavailable until
+// MarkReady is called, so they never see a partial set of series.
+type ReadySignaler interface {
+	// MarkReady opens the metrics endpoint. Calling it again has no effect.
+	MarkReady()
+}
+
 // Since we've simplified the interface, we no longer need the TimerInstance struct.
 // Instead, we use the TimerStart method which returns a function to stop the timer.
 
@@ -75,6 +100,25 @@ type Config struct {
 
 	// EnableProcessMetrics enables process metrics.
 	EnableProcessMetrics bool
+
+	// MetricsAuthToken, when set, requires scrapes to present it as a bearer
+	// token (Authorization: Bearer <token>). Empty disables authentication.
+	MetricsAuthToken string
+
+	// DeferReady holds the metrics endpoint at 503 until MarkReady is called
+	// on the ReadySignaler. When false, the endpoint is ready once Serve
+	// returns, so metrics should be registered before calling Serve.
+	DeferReady bool
+
+	// OTLPEndpoint is the URL of an OTLP/HTTP collector, e.g.
+	// "http://otel-collector:4318", that the OpenTelemetry adapter's meter
+	// provider pushes to. An "https" scheme enables TLS and an empty path
+	// defaults to /v1/metrics.
+	OTLPEndpoint string
+
+	// ExportInterval is how often metrics are pushed to OTLPEndpoint. Zero
+	// uses the SDK default of one minute.
+	ExportInterval time.Duration
 }
 
 // DefaultConfig returns the default configuration for metrics.
@@ -85,5 +129,9 @@ func DefaultConfig() Config {
 		Labels:               make(map[string]string),
 		EnableGoMetrics:      true,
 		EnableProcessMetrics: true,
+		MetricsAuthToken:     "",
+		DeferReady:           false,
+		OTLPEndpoint:         "",
+		ExportInterval:       0,
 	}
 }
@@ -20,4 +20,7 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Labels == nil {
 		t.Fatalf("expected Labels map to be initialized")
 	}
+	if cfg.DeferReady {
+		t.Fatalf("expected DeferReady disabled by default")
+	}
 }
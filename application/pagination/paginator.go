@@ -34,6 +34,9 @@ type PaginationOptions struct {
 // DefaultPageSize is the default number of items per page if not specified.
 const DefaultPageSize = 20
 
+// MaxPageSize is the largest page size a client may request.
+const MaxPageSize = 100
+
 // DefaultPaginationOptions returns default pagination options.
 func DefaultPaginationOptions() PaginationOptions {
 	return PaginationOptions{
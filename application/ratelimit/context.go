@@ -0,0 +1,19 @@
+package ratelimit
+
+import "context"
+
+type bypassKey struct{}
+
+// WithRateLimitBypass marks ctx so that rate limiting middlewares let the
+// request through without consuming tokens. It is meant for an upstream
+// middleware that has authenticated a privileged caller, such as another
+// internal service; never set it from unauthenticated request data.
+func WithRateLimitBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// BypassFromContext reports whether ctx was marked with WithRateLimitBypass.
+func BypassFromContext(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
@@ -30,7 +30,60 @@ type Limiter interface {
 
 	// ReserveN reserves n tokens and returns the time to wait before the tokens are available.
 	// If the tokens cannot be reserved, it returns a negative wait time.
+	// The tokens stay reserved even if the caller never acts on them; limiters
+	// that implement Reserver can give them back.
 	ReserveN(ctx context.Context, key string, n int) time.Duration
+
+	// Peek returns the time to wait before a token is available without consuming it.
+	// It returns zero if a token is available now.
+	Peek(ctx context.Context, key string) time.Duration
+
+	// PeekN returns the time to wait before n tokens are available without consuming them.
+	// It returns zero if the tokens are available now.
+	PeekN(ctx context.Context, key string, n int) time.Duration
+
+	// Stats returns a snapshot of the limiter state for key without consuming tokens.
+	// It returns false if no requests have been seen for the key yet.
+	Stats(ctx context.Context, key string) (LimiterStats, bool)
+}
+
+// KeyCounter is implemented by limiters that keep per-key state in memory.
+// Len reports how many keys are currently tracked, so runaway key growth can
+// be monitored and alerted on.
+type KeyCounter interface {
+	Len() int
+}
+
+// Reservation is a claim on tokens made through a Reserver.
+type Reservation struct {
+	// Delay is the time to wait before acting on the reservation. It is
+	// negative when nothing could be reserved.
+	Delay time.Duration
+
+	// Cancel gives the reserved tokens back, for a caller that decides not to
+	// act, e.g. because Delay is longer than its deadline allows. It is never
+	// nil, and only the first call has an effect.
+	Cancel func()
+}
+
+// Reserver is implemented by limiters whose reservations can be cancelled.
+// ReserveCancelable reserves n tokens for key like Limiter.ReserveN and
+// returns the reservation with its cancel function.
+type Reserver interface {
+	ReserveCancelable(ctx context.Context, key string, n int) Reservation
+}
+
+// LimiterStats is a point-in-time view of the limiter state for a single key.
+type LimiterStats struct {
+	// Tokens is the number of tokens currently available; it may be fractional.
+	Tokens float64
+
+	// Burst is the maximum number of tokens the key can accumulate.
+	Burst int
+
+	// NextToken is the time until the next whole token is available.
+	// It is zero if at least one token is available now.
+	NextToken time.Duration
 }
 
 // Strategy defines the rate limiting strategy.
@@ -70,8 +123,16 @@ type Config struct {
 	// WaitTimeout is the maximum time to wait for a token.
 	WaitTimeout time.Duration
 
+	// IdleTimeout evicts the state of keys that have not been seen for this
+	// long and whose bucket has refilled, so one-off keys do not accumulate.
+	// An evicted key starts again with a full bucket. Zero keeps keys forever.
+	IdleTimeout time.Duration
+
 	// KeyFunc is a function that generates a key from a context.
 	// If nil, a default key function will be used.
+	//
+	// Deprecated: HTTP middleware should derive keys from the request with
+	// middleware.WithKeyFunc, which can read headers and the route.
 	KeyFunc func(ctx context.Context) string
 }
 
@@ -84,5 +145,6 @@ func DefaultConfig() Config {
 		Period:      time.Minute,
 		Burst:       10,
 		WaitTimeout: time.Second,
+		IdleTimeout: 10 * time.Minute,
 	}
 }
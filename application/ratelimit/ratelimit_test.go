@@ -1,6 +1,7 @@
 package ratelimit_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -18,4 +19,17 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.WaitTimeout != time.Second {
 		t.Fatalf("unexpected WaitTimeout: %v", cfg.WaitTimeout)
 	}
+	if cfg.IdleTimeout != 10*time.Minute {
+		t.Fatalf("unexpected IdleTimeout: %v", cfg.IdleTimeout)
+	}
+}
+
+func TestWithRateLimitBypass(t *testing.T) {
+	ctx := context.Background()
+	if ratelimit.BypassFromContext(ctx) {
+		t.Fatalf("expected no bypass on a plain context")
+	}
+	if !ratelimit.BypassFromContext(ratelimit.WithRateLimitBypass(ctx)) {
+		t.Fatalf("expected bypass after WithRateLimitBypass")
+	}
 }
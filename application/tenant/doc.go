@@ -0,0 +1,4 @@
+// Package tenant carries the tenant ID of a request in its context and derives
+// tenant-scoped keys from it, so caches and rate limits shared by all tenants
+// keep each tenant's entries apart.
+package tenant
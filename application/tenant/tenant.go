@@ -0,0 +1,35 @@
+package tenant
+
+import (
+	"context"
+	"strings"
+)
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant ID. Authentication
+// middleware calls it once the caller's tenant is known.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant ID stored in ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(contextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// idEscaper escapes the tenant ID separator, and the escape character
+// itself, so that an ID cannot end early inside a scoped key.
+var idEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
+// Key scopes key to the tenant in ctx as "tenant:<id>:<key>", with any ":"
+// in the ID escaped as "%3A" and "%" as "%25". Without a tenant the key is
+// "global:<key>", so it cannot collide with a tenant's key either.
+func Key(ctx context.Context, key string) string {
+	tenantID, ok := FromContext(ctx)
+	if !ok {
+		return "global:" + key
+	}
+	return "tenant:" + idEscaper.Replace(tenantID) + ":" + key
+}
@@ -0,0 +1,43 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/application/tenant"
+)
+
+func TestKey(t *testing.T) {
+	ctx := context.Background()
+	if got := tenant.Key(ctx, "item:1"); got != "global:item:1" {
+		t.Fatalf("expected a global key without tenant, got %q", got)
+	}
+
+	ctx = tenant.WithTenant(ctx, "acme")
+	if id, ok := tenant.FromContext(ctx); !ok || id != "acme" {
+		t.Fatalf("unexpected tenant: %q, %v", id, ok)
+	}
+	if got := tenant.Key(ctx, "item:1"); got != "tenant:acme:item:1" {
+		t.Fatalf("unexpected scoped key: %q", got)
+	}
+}
+
+func TestKey_NoCollisions(t *testing.T) {
+	ctx := context.Background()
+	keys := map[string]string{
+		"global":            tenant.Key(ctx, "tenant:a:b"),
+		"tenant a, key b:c": tenant.Key(tenant.WithTenant(ctx, "a"), "b:c"),
+		"tenant a:b, key c": tenant.Key(tenant.WithTenant(ctx, "a:b"), "c"),
+		"tenant a%3Ab":      tenant.Key(tenant.WithTenant(ctx, "a%3Ab"), "c"),
+	}
+	seen := make(map[string]string, len(keys))
+	for name, key := range keys {
+		if other, ok := seen[key]; ok {
+			t.Fatalf("%s and %s both map to %q", name, other, key)
+		}
+		seen[key] = name
+	}
+	if got := keys["tenant a:b, key c"]; got != "tenant:a%3Ab:c" {
+		t.Fatalf("unexpected escaped key: %q", got)
+	}
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import "context"
+
+type tracerKey struct{}
+
+// ContextWithTracer returns a copy of ctx carrying t. Tracing middleware
+// stores the tracer that started the request span so handlers can annotate
+// it through AddSpanAttributes and RecordSpanError.
+func ContextWithTracer(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// TracerFromContext returns the tracer stored with ContextWithTracer, or nil.
+func TracerFromContext(ctx context.Context) Tracer {
+	t, _ := ctx.Value(tracerKey{}).(Tracer)
+	return t
+}
+
+// AddSpanAttributes sets attributes on the current span in ctx. It is a no-op
+// when ctx carries no tracer or no span.
+func AddSpanAttributes(ctx context.Context, attributes map[string]string) {
+	if t := TracerFromContext(ctx); t != nil && len(attributes) > 0 {
+		t.SetAttributes(ctx, attributes)
+	}
+}
+
+// RecordSpanError records err on the current span in ctx and marks the span
+// as failed. It is a no-op when err is nil or ctx carries no tracer or span.
+func RecordSpanError(ctx context.Context, err error) {
+	if t := TracerFromContext(ctx); t != nil && err != nil {
+		t.RecordError(ctx, err)
+	}
+}
+
+type forceSampleKey struct{}
+
+// ContextWithForceSample marks ctx so that spans started from it are sampled
+// regardless of the configured sampling rate. Tracer implementations that
+// support it honor the mark when the span's sampling decision is made.
+func ContextWithForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+// ForceSampleFromContext reports whether ctx was marked with ContextWithForceSample.
+func ForceSampleFromContext(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceSampleKey{}).(bool)
+	return forced
+}
+
+// SpanKind describes a span's role in a trace, e.g. the server side of a call.
+type SpanKind int
+
+const (
+	// SpanKindInternal is an operation within the service; the default.
+	SpanKindInternal SpanKind = iota
+	// SpanKindServer handles an incoming call from a remote client.
+	SpanKindServer
+	// SpanKindClient makes an outgoing call to a remote server.
+	SpanKindClient
+)
+
+type spanKindKey struct{}
+
+// ContextWithSpanKind marks ctx so that the next span started from it has
+// kind. Tracer implementations that support kinds apply it to that span
+// only; spans started below it are internal again.
+func ContextWithSpanKind(ctx context.Context, kind SpanKind) context.Context {
+	return context.WithValue(ctx, spanKindKey{}, kind)
+}
+
+// SpanKindFromContext returns the kind set with ContextWithSpanKind, or
+// SpanKindInternal.
+func SpanKindFromContext(ctx context.Context) SpanKind {
+	kind, _ := ctx.Value(spanKindKey{}).(SpanKind)
+	return kind
+}
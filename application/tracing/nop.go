@@ -0,0 +1,30 @@
+package tracing
+
+import "context"
+
+// nopTracer records nothing.
+type nopTracer struct{}
+
+// Nop returns a Tracer whose methods do nothing: Start returns the given
+// context unchanged and an empty end function. Services that do not
+// configure tracing can pass it wherever a Tracer is required.
+func Nop() Tracer {
+	return nopTracer{}
+}
+
+// OrNop returns t, or Nop() when t is nil.
+func OrNop(t Tracer) Tracer {
+	if t == nil {
+		return Nop()
+	}
+	return t
+}
+
+func (nopTracer) Start(ctx context.Context, _ string) (context.Context, func()) {
+	return ctx, func() {}
+}
+
+func (nopTracer) AddEvent(context.Context, string, map[string]string) {}
+func (nopTracer) SetAttributes(context.Context, map[string]string)    {}
+func (nopTracer) RecordError(context.Context, error)                  {}
+func (nopTracer) Shutdown(context.Context) error                      { return nil }
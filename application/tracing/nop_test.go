@@ -0,0 +1,35 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apptracing "github.com/next-trace/scg-service-api/application/tracing"
+)
+
+type ctxKey struct{}
+
+func TestNop(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	tracer := apptracing.Nop()
+
+	spanCtx, end := tracer.Start(ctx, "op")
+	if spanCtx != ctx {
+		t.Fatalf("expected Start to return the input context")
+	}
+	tracer.AddEvent(spanCtx, "evt", map[string]string{"k": "v"})
+	tracer.SetAttributes(spanCtx, map[string]string{"k": "v"})
+	tracer.RecordError(spanCtx, errors.New("boom"))
+	end()
+	if err := tracer.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	if apptracing.OrNop(nil) == nil {
+		t.Fatalf("expected OrNop(nil) to return a tracer")
+	}
+	if got := apptracing.OrNop(tracer); got != tracer {
+		t.Fatalf("expected OrNop to keep a non-nil tracer")
+	}
+}
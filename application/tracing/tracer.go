@@ -3,6 +3,7 @@ package tracing
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // Tracer defines the abstract tracing interface (PORT) for all services.
@@ -33,4 +34,21 @@ type Config struct {
 	ExporterEndpoint string
 	SamplingRate     float64
 	Output           io.Writer // For stdout exporter
+
+	// ResourceAttributes are extra attributes merged into the trace resource,
+	// e.g. deployment region, instance ID or k8s pod name.
+	ResourceAttributes map[string]string
+
+	// Span limits protect the collector from oversized spans. Attributes and
+	// events beyond the limits are dropped and values longer than the length
+	// limit are truncated. Zero selects the adapter's default.
+	AttributeCountLimit       int
+	AttributeValueLengthLimit int
+	EventCountLimit           int
+
+	// Batch span processor settings. Zero keeps the SDK default.
+	BatchTimeout       time.Duration // Maximum delay before a batch is exported
+	ExportTimeout      time.Duration // Maximum duration of a single export
+	MaxQueueSize       int           // Spans buffered before new ones are dropped
+	MaxExportBatchSize int           // Maximum spans per export
 }
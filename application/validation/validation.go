@@ -3,6 +3,7 @@ package validation
 
 import (
 	"context"
+	"errors"
 	"reflect"
 )
 
@@ -34,11 +35,59 @@ type ValidationResult struct {
 
 	// Errors contains validation errors, if any.
 	Errors ValidationErrors
+
+	// Fields holds the same errors in structured form, with a machine-readable
+	// code per error, when the validator provides them.
+	Fields FieldErrors
 }
 
 // ValidationErrors is a map of field names to validation error messages.
 type ValidationErrors map[string][]string
 
+// FieldError is a single validation failure as rendered for API clients.
+// Code identifies the failed rule (the validation tag, e.g. "required" or
+// "min") so clients can show localized messages; Param is the rule's
+// parameter, if any, e.g. "3" for min=3.
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+}
+
+// FieldErrors is a map of field names to structured validation errors.
+type FieldErrors map[string][]FieldError
+
+// ErrValidationFailed is matched (via errors.Is) by the error returned from ValidationResult.Err.
+var ErrValidationFailed = errors.New("validation failed")
+
+// ResultError is the error form of a failed ValidationResult.
+// Responders use it to render the per-field errors in the standard error envelope.
+type ResultError struct {
+	// Errors contains the per-field validation errors.
+	Errors ValidationErrors
+
+	// Fields contains the per-field errors with their codes, if known.
+	Fields FieldErrors
+}
+
+// Error returns the error message.
+func (e *ResultError) Error() string {
+	return ErrValidationFailed.Error()
+}
+
+// Unwrap returns ErrValidationFailed.
+func (e *ResultError) Unwrap() error {
+	return ErrValidationFailed
+}
+
+// Err returns nil if the result is valid, otherwise a *ResultError carrying the field errors.
+func (r ValidationResult) Err() error {
+	if r.Valid {
+		return nil
+	}
+	return &ResultError{Errors: r.Errors, Fields: r.Fields}
+}
+
 // CustomRule defines a custom validation rule.
 type CustomRule func(ctx context.Context, value interface{}, params ...string) bool
 
@@ -52,14 +101,34 @@ type Config struct {
 
 	// CustomRules is a map of custom validation rules.
 	CustomRules map[string]CustomRule
+
+	// SkipMethods lists the HTTP methods the validation middleware does not validate.
+	// A nil slice uses DefaultSkipMethods; an empty, non-nil slice validates every method.
+	SkipMethods []string
+
+	// MaxBodyBytes caps the request body the validation middleware reads;
+	// larger bodies are rejected with 413. Zero or negative means no limit.
+	MaxBodyBytes int64
+}
+
+// DefaultMaxBodyBytes is the default request body limit of the validation
+// middleware (1 MiB).
+const DefaultMaxBodyBytes = 1 << 20
+
+// DefaultSkipMethods returns the HTTP methods skipped by the validation
+// middleware by default. Each call returns a new slice the caller may modify.
+func DefaultSkipMethods() []string {
+	return []string{"GET", "HEAD", "OPTIONS"}
 }
 
 // DefaultConfig returns the default configuration for validation.
 func DefaultConfig() Config {
 	return Config{
-		Enabled:     true,
-		TagName:     "validate",
-		CustomRules: map[string]CustomRule{},
+		Enabled:      true,
+		TagName:      "validate",
+		CustomRules:  map[string]CustomRule{},
+		SkipMethods:  DefaultSkipMethods(),
+		MaxBodyBytes: DefaultMaxBodyBytes,
 	}
 }
 
@@ -85,3 +154,9 @@ type ValidationError struct {
 func (e ValidationError) Error() string {
 	return e.Message
 }
+
+// FieldError returns e in the form rendered for API clients, using the
+// validation tag as the code.
+func (e ValidationError) FieldError() FieldError {
+	return FieldError{Code: e.Tag, Message: e.Message, Param: e.Param}
+}
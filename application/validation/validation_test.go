@@ -10,3 +10,15 @@ import (
 func TestInterfacesExist(t *testing.T) {
 	var _ appval.Validator
 }
+
+func TestDefaultSkipMethods_ReturnsFreshCopy(t *testing.T) {
+	methods := appval.DefaultSkipMethods()
+	methods[0] = "POST"
+
+	if got := appval.DefaultSkipMethods(); got[0] != "GET" {
+		t.Fatalf("expected modifying the result to leave the defaults alone, got %v", got)
+	}
+	if got := appval.DefaultConfig().SkipMethods; len(got) != 3 || got[0] != "GET" {
+		t.Fatalf("unexpected default SkipMethods: %v", got)
+	}
+}
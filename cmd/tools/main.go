@@ -1,19 +1,47 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 )
 
 const (
 	protoDir = `proto`
 	genDir   = `gen`
 	goOutDir = genDir + `/go/` + protoDir + `/scg`
+
+	// defaultPlugins are the protoc plugins used when -plugins is not given.
+	defaultPlugins = `go,go-grpc`
+)
+
+// pluginOutputSuffixes maps the plugins whose output names are known to the
+// suffix of the file each writes for a proto with paths=source_relative, e.g.
+// "_grpc.pb.go" for gen/v1/example_grpc.pb.go from proto/v1/example.proto.
+var pluginOutputSuffixes = map[string]string{
+	"go":           ".pb.go",
+	"go-grpc":      "_grpc.pb.go",
+	"grpc-gateway": ".pb.gw.go",
+	"validate":     ".pb.validate.go",
+}
+
+// Patterns used to validate -plugins values before they are passed to protoc.
+var (
+	pluginNamePattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	pluginOptionPattern = regexp.MustCompile(`^[A-Za-z0-9_./=-]+$`)
 )
 
 func main() {
@@ -23,6 +51,10 @@ func main() {
 	installCmd := flag.Bool("install-tools", false, "Install required tools")
 	buildCmd := flag.Bool("build", false, "Build the SCG tools")
 	helpCmd := flag.Bool("help", false, "Show help message")
+	jobs := flag.Int("jobs", runtime.NumCPU(), "Maximum number of concurrent protoc invocations")
+	continueOnError := flag.Bool("continue-on-error", false, "Keep generating after a failure and report all failures at the end")
+	force := flag.Bool("force", false, "Regenerate proto files even if their output is up to date")
+	pluginsFlag := flag.String("plugins", defaultPlugins, "Comma-separated protoc plugins, each optionally followed by :opt1+opt2")
 
 	// Parse command line flags
 	flag.Parse()
@@ -39,7 +71,15 @@ func main() {
 	}
 
 	if *protoCmd {
-		generateProto()
+		plugins, err := parsePlugins(*pluginsFlag)
+		if err != nil {
+			fmt.Printf("Error parsing -plugins: %v\n", err)
+			os.Exit(1)
+		}
+		// Stop remaining protoc invocations on Ctrl-C or SIGTERM
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		generateProto(ctx, protoOptions{jobs: *jobs, continueOnError: *continueOnError, plugins: plugins, force: *force})
+		stop()
 	}
 
 	if *cleanCmd {
@@ -64,22 +104,179 @@ func showHelp() {
 	fmt.Println("  -install-tools  Install required tools")
 	fmt.Println("  -build          Build the SCG tools")
 	fmt.Println("  -help           Show this help message")
+	fmt.Println("\nOptions for -proto:")
+	fmt.Println("  -jobs N              Run up to N protoc invocations concurrently (default: number of CPUs)")
+	fmt.Println("  -continue-on-error   Report all failures at the end instead of stopping at the first")
+	fmt.Println("  -force               Regenerate files even if their output is newer than the proto source")
+	fmt.Println("  -plugins LIST        Protoc plugins to run (default: go,go-grpc), e.g.")
+	fmt.Println("                       go,go-grpc,grpc-gateway:generate_unbound_methods=true,validate:lang=go")
+}
+
+// protoOptions controls how generateProto runs protoc.
+type protoOptions struct {
+	// jobs is the maximum number of concurrent protoc invocations.
+	jobs int
+
+	// continueOnError keeps generating the remaining files after a failure
+	// and reports every failure at the end.
+	continueOnError bool
+
+	// plugins are the protoc plugins to run for every file.
+	plugins []protocPlugin
+
+	// force regenerates files whose output is newer than the proto source.
+	force bool
+}
+
+// protocPlugin is a protoc output plugin, e.g. "go" for --go_out.
+type protocPlugin struct {
+	name    string
+	options []string
+}
+
+// parsePlugins parses a -plugins value such as "go,go-grpc,validate:lang=go".
+// Options for one plugin are separated by "+". Names and options are restricted
+// to a safe character set so that they cannot inject additional protoc flags.
+func parsePlugins(spec string) ([]protocPlugin, error) {
+	var plugins []protocPlugin
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(entry, ":")
+		if !pluginNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid plugin name %q", name)
+		}
+
+		plugin := protocPlugin{name: name}
+		if opts != "" {
+			for _, opt := range strings.Split(opts, "+") {
+				if !pluginOptionPattern.MatchString(opt) {
+					return nil, fmt.Errorf("invalid option %q for plugin %q", opt, name)
+				}
+				plugin.options = append(plugin.options, opt)
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	if len(plugins) == 0 {
+		return nil, errors.New("no plugins specified")
+	}
+	return plugins, nil
 }
 
 // generateProto generates Go code from protobuf definitions
-func generateProto() {
+func generateProto(ctx context.Context, opts protoOptions) {
 	fmt.Println("Generating Go code from protobuf definitions...")
 
+	if err := runProtoGeneration(ctx, opts); err != nil {
+		fmt.Printf("Error generating code:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Done.")
+}
+
+// runProtoGeneration runs protoc for every proto file using up to opts.jobs workers.
+// Without continueOnError no new invocations are started after the first failure.
+// Cancelling ctx stops running invocations and skips the remaining files.
+func runProtoGeneration(ctx context.Context, opts protoOptions) error {
 	// Ensure output directory exists
 	err := os.MkdirAll(goOutDir, 0o750)
 	if err != nil {
-		fmt.Printf("Error creating directory %s: %v\n", goOutDir, err)
-		os.Exit(1)
+		return fmt.Errorf("creating directory %s: %w", goOutDir, err)
+	}
+
+	protoFiles, err := findProtoFiles()
+	if err != nil {
+		return err
+	}
+
+	// Use a fixed set of arguments with a hardcoded binary path to prevent command injection
+	protoBinary, err := exec.LookPath("protoc")
+	if err != nil {
+		return fmt.Errorf("finding protoc binary: %w", err)
+	}
+
+	plugins := opts.plugins
+	if len(plugins) == 0 {
+		if plugins, err = parsePlugins(defaultPlugins); err != nil {
+			return err
+		}
+	}
+
+	// Regenerate when protoc or a plugin binary changed since the last run
+	tools, toolsFound := toolBinaries(protoBinary, plugins)
+
+	jobs := opts.jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	var (
+		mu     sync.Mutex
+		errs   []error
+		failed atomic.Bool
+		wg     sync.WaitGroup
+	)
+	stopped := func() bool {
+		return ctx.Err() != nil || (failed.Load() && !opts.continueOnError)
+	}
+	files := make(chan string)
+
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for protoFile := range files {
+				if stopped() {
+					continue
+				}
+				if err := runProtoc(ctx, protoBinary, protoFile, plugins); err != nil {
+					failed.Store(true)
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	// Process each proto file
+	for _, protoFile := range protoFiles {
+		if stopped() {
+			break
+		}
+
+		// Validate proto file path for security
+		if !isValidProtoFile(protoFile) {
+			fmt.Printf("Skipping invalid proto file path: %s\n", protoFile)
+			continue
+		}
+
+		if !opts.force && toolsFound && isUpToDate(protoFile, plugins, tools) {
+			fmt.Printf("Skipping up-to-date proto file: %s\n", protoFile)
+			continue
+		}
+
+		files <- protoFile
+	}
+	close(files)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
 	}
+	return errors.Join(errs...)
+}
 
-	// Find all .proto files
+// findProtoFiles returns all .proto files below protoDir.
+func findProtoFiles() ([]string, error) {
 	var protoFiles []string
-	err = filepath.WalkDir(protoDir, func(path string, d fs.DirEntry, err error) error {
+	err := filepath.WalkDir(protoDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -89,53 +286,118 @@ func generateProto() {
 		return nil
 	})
 	if err != nil {
-		fmt.Printf("Error finding proto files: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("finding proto files: %w", err)
 	}
+	return protoFiles, nil
+}
 
-	// Process each proto file
-	for _, protoFile := range protoFiles {
-		// Validate proto file path for security
-		if !isValidProtoFile(protoFile) {
-			fmt.Printf("Skipping invalid proto file path: %s\n", protoFile)
-			continue
+// toolBinaries returns the paths of protoBinary and of every plugin's
+// protoc-gen-<name> binary. It reports false if a plugin is not on PATH.
+func toolBinaries(protoBinary string, plugins []protocPlugin) ([]string, bool) {
+	tools := []string{protoBinary}
+	for _, plugin := range plugins {
+		path, err := exec.LookPath("protoc-gen-" + plugin.name)
+		if err != nil {
+			return nil, false
 		}
+		tools = append(tools, path)
+	}
+	return tools, true
+}
 
-		// Clean and sanitize paths to prevent command injection
-		cleanProtoDir := filepath.Clean(protoDir)
-		cleanGenDir := filepath.Clean(genDir)
-		cleanProtoFile := filepath.Clean(protoFile)
+// isUpToDate reports whether protoFile has already been generated: every plugin
+// has written its expected output file (e.g. gen/v1/example.pb.go for
+// proto/v1/example.proto and the go plugin), and every output is newer than
+// the proto source and than each of the tools binaries. Plugins whose output
+// names are unknown are never considered up to date.
+func isUpToDate(protoFile string, plugins []protocPlugin, tools []string) bool {
+	rel, err := filepath.Rel(protoDir, protoFile)
+	if err != nil {
+		return false
+	}
+	base := filepath.Join(genDir, strings.TrimSuffix(rel, ".proto"))
 
-		// Use a fixed set of arguments with a hardcoded binary path to prevent command injection
-		protoBinary, err := exec.LookPath("protoc")
+	source, err := os.Stat(protoFile)
+	if err != nil {
+		return false
+	}
+	newestInput := source.ModTime()
+	for _, tool := range tools {
+		info, err := os.Stat(tool)
 		if err != nil {
-			fmt.Printf("Error finding protoc binary: %v\n", err)
-			os.Exit(1)
+			return false
 		}
+		if info.ModTime().After(newestInput) {
+			newestInput = info.ModTime()
+		}
+	}
 
-		// Create a fixed set of arguments
-		args := []string{
-			"--proto_path", cleanProtoDir,
-			"--go_out", cleanGenDir,
-			"--go_opt", "paths=source_relative",
-			"--go-grpc_out", cleanGenDir,
-			"--go-grpc_opt", "paths=source_relative",
-			cleanProtoFile,
+	for _, plugin := range plugins {
+		suffix, ok := pluginOutputSuffixes[plugin.name]
+		if !ok {
+			return false
+		}
+		info, err := os.Stat(base + suffix)
+		if err != nil || !info.ModTime().After(newestInput) {
+			return false
 		}
+	}
+	return true
+}
+
+// runProtoc generates code for a single proto file. The protoc process is
+// killed if ctx is cancelled before it finishes.
+func runProtoc(ctx context.Context, protoBinary, protoFile string, plugins []protocPlugin) error {
+	args := protocArgs(plugins, protoFile)
+
+	var output bytes.Buffer
+	cmd := &exec.Cmd{
+		Path:   protoBinary,
+		Args:   append([]string{protoBinary}, args...),
+		Stdout: &output,
+		Stderr: &output,
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", protoFile, err)
+	}
 
-		cmd := &exec.Cmd{
-			Path: protoBinary,
-			Args: append([]string{protoBinary}, args...),
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+		case <-done:
 		}
+	}()
 
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			fmt.Printf("Error generating code for %s: %v\n%s\n", protoFile, err, output)
-			os.Exit(1)
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s: %w", protoFile, ctx.Err())
 		}
+		return fmt.Errorf("%s: %w\n%s", protoFile, err, output.Bytes())
 	}
+	return nil
+}
 
-	fmt.Println("Done.")
+// protocArgs builds the protoc arguments for protoFile. Every plugin writes to
+// genDir with paths=source_relative followed by its own options.
+func protocArgs(plugins []protocPlugin, protoFile string) []string {
+	// Clean and sanitize paths to prevent command injection
+	cleanProtoDir := filepath.Clean(protoDir)
+	cleanGenDir := filepath.Clean(genDir)
+	cleanProtoFile := filepath.Clean(protoFile)
+
+	args := []string{"--proto_path", cleanProtoDir}
+	for _, plugin := range plugins {
+		opts := append([]string{"paths=source_relative"}, plugin.options...)
+		args = append(args,
+			"--"+plugin.name+"_out", cleanGenDir,
+			"--"+plugin.name+"_opt", strings.Join(opts, ","),
+		)
+	}
+	return append(args, cleanProtoFile)
 }
 
 // cleanGenerated removes generated files
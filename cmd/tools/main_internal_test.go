@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProtoc records how many invocations run at once in $FAKE_PROTOC_LOG and
+// fails for proto files whose name starts with "bad".
+const fakeProtoc = `#!/bin/sh
+for last; do :; done
+touch "$FAKE_PROTOC_RUNNING/$$"
+ls "$FAKE_PROTOC_RUNNING" | wc -l >> "$FAKE_PROTOC_LOG"
+sleep 0.2
+rm -f "$FAKE_PROTOC_RUNNING/$$"
+case "$(basename "$last")" in
+bad*) echo "syntax error in $last"; exit 1 ;;
+esac
+exit 0
+`
+
+// setupProtoWorkspace creates a workspace with the given proto files and a fake
+// protoc on PATH, and changes into it. It returns the path of the concurrency log.
+func setupProtoWorkspace(t *testing.T, names ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	running := filepath.Join(dir, "running")
+	for _, d := range []string{binDir, running, filepath.Join(dir, protoDir), filepath.Join(dir, genDir)} {
+		if err := os.MkdirAll(d, 0o750); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "protoc"), []byte(fakeProtoc), 0o700); err != nil { //nolint:gosec // test script must be executable
+		t.Fatalf("write fake protoc: %v", err)
+	}
+	// The default plugins must be on PATH for outputs to count as up to date.
+	for _, plugin := range []string{"protoc-gen-go", "protoc-gen-go-grpc"} {
+		if err := os.WriteFile(filepath.Join(binDir, plugin), []byte("#!/bin/sh\n"), 0o700); err != nil { //nolint:gosec // test script must be executable
+			t.Fatalf("write fake plugin: %v", err)
+		}
+	}
+	// Backdate the tools so that outputs written by the tests are newer.
+	installed := time.Now().Add(-2 * time.Hour)
+	for _, tool := range []string{"protoc", "protoc-gen-go", "protoc-gen-go-grpc"} {
+		if err := os.Chtimes(filepath.Join(binDir, tool), installed, installed); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, protoDir, name), []byte(`syntax = "proto3";`), 0o600); err != nil {
+			t.Fatalf("write proto: %v", err)
+		}
+	}
+
+	logFile := filepath.Join(dir, "protoc.log")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_PROTOC_RUNNING", running)
+	t.Setenv("FAKE_PROTOC_LOG", logFile)
+	t.Chdir(dir)
+
+	return logFile
+}
+
+// maxConcurrency returns the highest concurrency recorded by the fake protoc.
+func maxConcurrency(t *testing.T, logFile string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	highest := 0
+	for _, line := range strings.Fields(string(data)) {
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			t.Fatalf("parse log line %q: %v", line, err)
+		}
+		highest = max(highest, n)
+	}
+	return highest
+}
+
+func TestRunProtoGeneration_Concurrent(t *testing.T) {
+	logFile := setupProtoWorkspace(t, "a.proto", "b.proto", "c.proto", "d.proto")
+
+	if err := runProtoGeneration(context.Background(), protoOptions{jobs: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := maxConcurrency(t, logFile); got < 2 || got > 4 {
+		t.Fatalf("expected between 2 and 4 concurrent protoc runs, got %d", got)
+	}
+}
+
+func TestRunProtoGeneration_JobsBound(t *testing.T) {
+	logFile := setupProtoWorkspace(t, "a.proto", "b.proto", "c.proto")
+
+	if err := runProtoGeneration(context.Background(), protoOptions{jobs: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := maxConcurrency(t, logFile); got != 1 {
+		t.Fatalf("expected serial protoc runs with -jobs 1, got %d concurrent", got)
+	}
+}
+
+func TestRunProtoGeneration_ContinueOnError(t *testing.T) {
+	setupProtoWorkspace(t, "bad1.proto", "bad2.proto", "good.proto")
+
+	err := runProtoGeneration(context.Background(), protoOptions{jobs: 2, continueOnError: true})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	for _, name := range []string{"bad1.proto", "bad2.proto"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error to report %s, got: %v", name, err)
+		}
+	}
+	if strings.Contains(err.Error(), "good.proto") {
+		t.Fatalf("did not expect good.proto in errors: %v", err)
+	}
+}
+
+func TestRunProtoGeneration_StopsOnFirstError(t *testing.T) {
+	setupProtoWorkspace(t, "bad1.proto", "bad2.proto", "bad3.proto")
+
+	err := runProtoGeneration(context.Background(), protoOptions{jobs: 1})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "bad1.proto") {
+		t.Fatalf("expected first failure to be reported, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "bad2.proto") || strings.Contains(err.Error(), "bad3.proto") {
+		t.Fatalf("expected generation to stop after the first failure, got: %v", err)
+	}
+}
+
+func TestProtocArgs_Plugins(t *testing.T) {
+	plugins, err := parsePlugins("go, go-grpc,grpc-gateway:generate_unbound_methods=true,validate:lang=go+module=example.com/x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	args := strings.Join(protocArgs(plugins, "proto/v1/../v1/example.proto"), " ")
+
+	for _, want := range []string{
+		"--proto_path proto",
+		"--go_out gen --go_opt paths=source_relative",
+		"--go-grpc_out gen --go-grpc_opt paths=source_relative",
+		"--grpc-gateway_out gen --grpc-gateway_opt paths=source_relative,generate_unbound_methods=true",
+		"--validate_out gen --validate_opt paths=source_relative,lang=go,module=example.com/x",
+	} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected args to contain %q, got: %s", want, args)
+		}
+	}
+	if !strings.HasSuffix(args, " proto/v1/example.proto") {
+		t.Fatalf("expected cleaned proto file as last argument, got: %s", args)
+	}
+}
+
+func TestParsePlugins_RejectsUnsafeValues(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"go;rm",
+		"--go_out",
+		"go:paths=source_relative --plugin=evil",
+		"Go",
+	} {
+		if _, err := parsePlugins(spec); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestRunProtoGeneration_SkipsUpToDate(t *testing.T) {
+	logFile := setupProtoWorkspace(t, "fresh.proto", "stale.proto")
+
+	// fresh.proto has output newer than its source; stale.proto's output is older.
+	past := time.Now().Add(-time.Hour)
+	for _, name := range []string{"fresh.pb.go", "fresh_grpc.pb.go", "stale.pb.go"} {
+		if err := os.WriteFile(filepath.Join(genDir, name), []byte("package x"), 0o600); err != nil {
+			t.Fatalf("write output: %v", err)
+		}
+	}
+	if err := os.Chtimes(filepath.Join(protoDir, "fresh.proto"), past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := os.Chtimes(filepath.Join(genDir, "stale.pb.go"), past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	if err := runProtoGeneration(context.Background(), protoOptions{jobs: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := invocations(t, logFile); got != 1 {
+		t.Fatalf("expected only the stale proto to be generated, got %d protoc runs", got)
+	}
+
+	// -force regenerates everything.
+	if err := runProtoGeneration(context.Background(), protoOptions{jobs: 1, force: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := invocations(t, logFile); got != 3 {
+		t.Fatalf("expected both protos to be regenerated with force, got %d protoc runs in total", got)
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	setupProtoWorkspace(t, "item.proto", "item_history.proto")
+	plugins, err := parsePlugins(defaultPlugins)
+	if err != nil {
+		t.Fatalf("parse plugins: %v", err)
+	}
+	protoBinary, err := exec.LookPath("protoc")
+	if err != nil {
+		t.Fatalf("find protoc: %v", err)
+	}
+	tools, ok := toolBinaries(protoBinary, plugins)
+	if !ok {
+		t.Fatalf("expected the fake plugins to be found")
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(protoDir, "item.proto"), past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(genDir, name), []byte("package x"), 0o600); err != nil {
+			t.Fatalf("write output: %v", err)
+		}
+	}
+	itemProto := filepath.Join(protoDir, "item.proto")
+
+	// A sibling proto's output does not count as item.proto's.
+	write("item_history.pb.go")
+	write("item_history_grpc.pb.go")
+	if isUpToDate(itemProto, plugins, tools) {
+		t.Fatalf("expected item.proto without its own output to be stale")
+	}
+
+	// Every plugin's output is required.
+	write("item.pb.go")
+	if isUpToDate(itemProto, plugins, tools) {
+		t.Fatalf("expected item.proto without its gRPC output to be stale")
+	}
+	write("item_grpc.pb.go")
+	if !isUpToDate(itemProto, plugins, tools) {
+		t.Fatalf("expected item.proto with all outputs to be up to date")
+	}
+
+	// Plugins with unknown output names always regenerate.
+	if isUpToDate(itemProto, append(plugins, protocPlugin{name: "custom"}), tools) {
+		t.Fatalf("expected an unknown plugin to force regeneration")
+	}
+
+	// An upgraded plugin binary invalidates existing output.
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(tools[1], future, future); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if isUpToDate(itemProto, plugins, tools) {
+		t.Fatalf("expected output older than a plugin binary to be stale")
+	}
+}
+
+func TestRunProtoGeneration_Cancelled(t *testing.T) {
+	logFile := setupProtoWorkspace(t, "a.proto", "b.proto")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runProtoGeneration(ctx, protoOptions{jobs: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if got := invocations(t, logFile); got != 0 {
+		t.Fatalf("expected no protoc runs after cancellation, got %d", got)
+	}
+}
+
+// invocations returns how many times the fake protoc has run.
+func invocations(t *testing.T, logFile string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(logFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	return len(strings.Fields(string(data)))
+}
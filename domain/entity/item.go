@@ -2,7 +2,11 @@
 package entity
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +26,18 @@ const (
 	ItemStatusDeleted ItemStatus = "deleted"
 )
 
+// ErrInvalidStatusTransition is returned when an item is moved to a status that
+// its lifecycle does not allow, such as reactivating a deleted item.
+var ErrInvalidStatusTransition = errors.New("invalid item status transition")
+
+// allowedTransitions lists the statuses reachable from each status.
+// Remaining in the current status is always allowed.
+var allowedTransitions = map[ItemStatus][]ItemStatus{
+	ItemStatusActive:   {ItemStatusInactive, ItemStatusDeleted},
+	ItemStatusInactive: {ItemStatusActive, ItemStatusDeleted},
+	ItemStatusDeleted:  {},
+}
+
 // Item represents a domain entity in the system.
 type Item struct {
 	// ID is the unique identifier for the item.
@@ -46,16 +62,61 @@ type Item struct {
 	UpdatedAt time.Time
 }
 
+// Version identifies the item's current state for optimistic concurrency.
+// It changes whenever an update changes the item, and is suitable as an
+// HTTP ETag. It has microsecond precision, the most that stores such as
+// PostgreSQL keep, so it is the same before and after a save and reload.
+func (i *Item) Version() string {
+	return strconv.FormatInt(i.UpdatedAt.UTC().UnixMicro(), 10)
+}
+
+// touch records a change by moving UpdatedAt to now, and at least one
+// microsecond past its previous value so that the change yields a new Version.
+func (i *Item) touch() {
+	now := time.Now().UTC()
+	if last := i.UpdatedAt.UTC().Truncate(time.Microsecond); !now.Truncate(time.Microsecond).After(last) {
+		now = last.Add(time.Microsecond)
+	}
+	i.UpdatedAt = now
+}
+
+// IDGenerator returns a new unique item ID.
+type IDGenerator func() string
+
+// NewUUID is the default IDGenerator: it returns a random (version 4) UUID.
+func NewUUID() string {
+	return uuid.New().String()
+}
+
+// ItemOption customizes NewItem.
+type ItemOption func(*itemOptions)
+
+// itemOptions holds the settings NewItem builds an item with.
+type itemOptions struct {
+	newID IDGenerator
+}
+
+// WithIDGenerator makes NewItem take the item's ID from gen instead of
+// NewUUID, e.g. so tests can assert on deterministic IDs.
+func WithIDGenerator(gen IDGenerator) ItemOption {
+	return func(o *itemOptions) { o.newID = gen }
+}
+
 // NewItem creates a new item with the given name and description.
 // It generates a new UUID for the ID and sets the status to active.
-func NewItem(name, description string, tags []string) (*Item, error) {
+func NewItem(name, description string, tags []string, opts ...ItemOption) (*Item, error) {
 	if name == "" {
 		return nil, fmt.Errorf("item name cannot be empty")
 	}
 
+	o := itemOptions{newID: NewUUID}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	now := time.Now().UTC()
 	return &Item{
-		ID:          uuid.New().String(),
+		ID:          o.newID(),
 		Name:        name,
 		Description: description,
 		Tags:        tags,
@@ -83,8 +144,13 @@ func (i *Item) Validate() error {
 }
 
 // Update updates the item's fields with the provided values.
-// Only non-empty values are used for the update.
-func (i *Item) Update(name, description string, tags []string, status ItemStatus) {
+// Only non-empty values are used for the update. If status is set and the
+// transition is not allowed, the item is left unchanged and an error is returned.
+func (i *Item) Update(name, description string, tags []string, status ItemStatus) error {
+	if status != "" && !i.CanTransitionTo(status) {
+		return i.transitionError(status)
+	}
+
 	if name != "" {
 		i.Name = name
 	}
@@ -101,25 +167,55 @@ func (i *Item) Update(name, description string, tags []string, status ItemStatus
 		i.Status = status
 	}
 
-	i.UpdatedAt = time.Now().UTC()
+	i.touch()
+	return nil
+}
+
+// CanTransitionTo reports whether the item may move from its current status to the given status.
+func (i *Item) CanTransitionTo(status ItemStatus) bool {
+	if status == i.Status {
+		return true
+	}
+
+	for _, allowed := range allowedTransitions[i.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the item to the given status if the lifecycle allows it.
+func (i *Item) Transition(to ItemStatus) error {
+	if !i.CanTransitionTo(to) {
+		return i.transitionError(to)
+	}
+
+	i.Status = to
+	i.touch()
+	return nil
 }
 
 // Activate sets the item's status to active.
-func (i *Item) Activate() {
-	i.Status = ItemStatusActive
-	i.UpdatedAt = time.Now().UTC()
+// Deleted items cannot be reactivated.
+func (i *Item) Activate() error {
+	return i.Transition(ItemStatusActive)
 }
 
 // Deactivate sets the item's status to inactive.
-func (i *Item) Deactivate() {
-	i.Status = ItemStatusInactive
-	i.UpdatedAt = time.Now().UTC()
+// Deleted items cannot be deactivated.
+func (i *Item) Deactivate() error {
+	return i.Transition(ItemStatusInactive)
 }
 
 // Delete sets the item's status to deleted.
-func (i *Item) Delete() {
-	i.Status = ItemStatusDeleted
-	i.UpdatedAt = time.Now().UTC()
+func (i *Item) Delete() error {
+	return i.Transition(ItemStatusDeleted)
+}
+
+// transitionError builds the error returned for a rejected status change.
+func (i *Item) transitionError(to ItemStatus) error {
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, i.Status, to)
 }
 
 // IsActive returns true if the item is active.
@@ -127,6 +223,44 @@ func (i *Item) IsActive() bool {
 	return i.Status == ItemStatusActive
 }
 
+// Equal reports whether the item and other hold the same data. UpdatedAt is
+// ignored, so touching an item without changing it keeps it equal.
+func (i *Item) Equal(other *Item) bool {
+	if i == nil || other == nil {
+		return i == other
+	}
+	return len(i.Diff(other)) == 0
+}
+
+// Diff returns the fields whose values differ between the item and other,
+// keyed by field name and holding other's value. UpdatedAt is ignored.
+func (i *Item) Diff(other *Item) map[string]interface{} {
+	changes := make(map[string]interface{})
+	if i == nil || other == nil {
+		return changes
+	}
+
+	if i.ID != other.ID {
+		changes["id"] = other.ID
+	}
+	if i.Name != other.Name {
+		changes["name"] = other.Name
+	}
+	if i.Description != other.Description {
+		changes["description"] = other.Description
+	}
+	if !slices.Equal(i.Tags, other.Tags) {
+		changes["tags"] = slices.Clone(other.Tags)
+	}
+	if i.Status != other.Status {
+		changes["status"] = other.Status
+	}
+	if !i.CreatedAt.Equal(other.CreatedAt) {
+		changes["created_at"] = other.CreatedAt
+	}
+	return changes
+}
+
 // HasTag returns true if the item has the specified tag.
 func (i *Item) HasTag(tag string) bool {
 	for _, t := range i.Tags {
@@ -143,7 +277,29 @@ func (i *Item) AddTag(tag string) {
 		return
 	}
 	i.Tags = append(i.Tags, tag)
-	i.UpdatedAt = time.Now().UTC()
+	i.touch()
+}
+
+// SetTags replaces the item's tags with the given set. Tags are trimmed of
+// surrounding whitespace, empty tags are dropped and duplicates are removed
+// while keeping the first occurrence's position.
+func (i *Item) SetTags(tags []string) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	i.Tags = normalized
+	i.touch()
 }
 
 // RemoveTag removes a tag from the item if it exists.
@@ -155,7 +311,7 @@ func (i *Item) RemoveTag(tag string) {
 	for j, t := range i.Tags {
 		if t == tag {
 			i.Tags = append(i.Tags[:j], i.Tags[j+1:]...)
-			i.UpdatedAt = time.Now().UTC()
+			i.touch()
 			return
 		}
 	}
@@ -1,6 +1,8 @@
 package entity_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -34,6 +36,24 @@ func TestNewItemAndValidate(t *testing.T) {
 	}
 }
 
+func TestNewItem_IDGenerator(t *testing.T) {
+	n := 0
+	gen := func() string {
+		n++
+		return fmt.Sprintf("item-%d", n)
+	}
+
+	for _, want := range []string{"item-1", "item-2"} {
+		item, err := entity.NewItem("Widget", "", nil, entity.WithIDGenerator(gen))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if item.ID != want {
+			t.Fatalf("expected ID %q, got %q", want, item.ID)
+		}
+	}
+}
+
 func TestNewItem_EmptyName(t *testing.T) {
 	if _, err := entity.NewItem("", "desc", nil); err == nil {
 		t.Fatalf("expected error for empty name")
@@ -110,3 +130,139 @@ func TestTagsHelpers(t *testing.T) {
 		t.Fatalf("expected UpdatedAt to move forward after tag changes")
 	}
 }
+
+func TestStatusTransitionGuard(t *testing.T) {
+	item, err := entity.NewItem("A", "B", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Legal transitions.
+	if err := item.Deactivate(); err != nil {
+		t.Fatalf("active -> inactive should succeed: %v", err)
+	}
+	if err := item.Activate(); err != nil {
+		t.Fatalf("inactive -> active should succeed: %v", err)
+	}
+	if err := item.Activate(); err != nil {
+		t.Fatalf("active -> active should be a no-op: %v", err)
+	}
+	if err := item.Delete(); err != nil {
+		t.Fatalf("active -> deleted should succeed: %v", err)
+	}
+
+	// Deleted is terminal.
+	for _, to := range []entity.ItemStatus{entity.ItemStatusActive, entity.ItemStatusInactive} {
+		if item.CanTransitionTo(to) {
+			t.Fatalf("deleted -> %s should not be allowed", to)
+		}
+		if err := item.Transition(to); !errors.Is(err, entity.ErrInvalidStatusTransition) {
+			t.Fatalf("expected ErrInvalidStatusTransition for deleted -> %s, got %v", to, err)
+		}
+	}
+	if err := item.Update("renamed", "", nil, entity.ItemStatusActive); !errors.Is(err, entity.ErrInvalidStatusTransition) {
+		t.Fatalf("expected Update to reject deleted -> active, got %v", err)
+	}
+	if item.Status != entity.ItemStatusDeleted || item.Name != "A" {
+		t.Fatalf("rejected transition must leave item unchanged: %#v", item)
+	}
+	if !item.CanTransitionTo(entity.ItemStatusDeleted) {
+		t.Fatalf("deleted -> deleted should be allowed")
+	}
+}
+
+func TestSetTags_NormalizesAndDedupes(t *testing.T) {
+	item, err := entity.NewItem("T", "D", []string{"old"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	item.SetTags([]string{" a ", "b", "", "a", "  ", "c", "b"})
+
+	want := []string{"a", "b", "c"}
+	if len(item.Tags) != len(want) {
+		t.Fatalf("expected tags %v, got %v", want, item.Tags)
+	}
+	for i, tag := range want {
+		if item.Tags[i] != tag {
+			t.Fatalf("expected tags %v, got %v", want, item.Tags)
+		}
+	}
+	if item.HasTag("old") {
+		t.Fatalf("expected previous tags to be replaced")
+	}
+
+	item.SetTags(nil)
+	if len(item.Tags) != 0 {
+		t.Fatalf("expected tags to be cleared, got %v", item.Tags)
+	}
+}
+
+func TestItemEqualAndDiff(t *testing.T) {
+	a, err := entity.NewItem("Widget", "desc", []string{"x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := *a
+	b.Tags = []string{"x"}
+	b.UpdatedAt = a.UpdatedAt.Add(time.Hour)
+
+	if !a.Equal(&b) {
+		t.Fatalf("expected items differing only in UpdatedAt to be equal")
+	}
+	if d := a.Diff(&b); len(d) != 0 {
+		t.Fatalf("expected empty diff, got %v", d)
+	}
+
+	b.Name = "Gadget"
+	if a.Equal(&b) {
+		t.Fatalf("expected renamed item to differ")
+	}
+	d := a.Diff(&b)
+	if len(d) != 1 || d["name"] != "Gadget" {
+		t.Fatalf("expected only a name change, got %v", d)
+	}
+
+	b.Tags = []string{"x", "y"}
+	if _, ok := a.Diff(&b)["tags"]; !ok {
+		t.Fatalf("expected tags change in diff")
+	}
+
+	var nilItem *entity.Item
+	if a.Equal(nil) || !nilItem.Equal(nil) {
+		t.Fatalf("unexpected nil equality")
+	}
+}
+
+func TestItemVersion_SurvivesMicrosecondStorage(t *testing.T) {
+	item, err := entity.NewItem("Widget", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item.UpdatedAt = time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+
+	// A store with microsecond precision, read back in another time zone.
+	stored := *item
+	stored.UpdatedAt = item.UpdatedAt.Truncate(time.Microsecond).In(time.FixedZone("CET", 3600))
+	if item.Version() != stored.Version() {
+		t.Fatalf("expected version %q after storage, got %q", item.Version(), stored.Version())
+	}
+}
+
+func TestItemVersion_ChangesOnEveryUpdate(t *testing.T) {
+	item, err := entity.NewItem("Widget", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// An UpdatedAt ahead of the clock stands in for updates within one microsecond.
+	item.UpdatedAt = time.Now().UTC().Add(time.Hour)
+
+	seen := map[string]bool{item.Version(): true}
+	for n := range 3 {
+		item.AddTag(fmt.Sprintf("tag-%d", n))
+		if seen[item.Version()] {
+			t.Fatalf("update %d reused version %q", n, item.Version())
+		}
+		seen[item.Version()] = true
+	}
+}
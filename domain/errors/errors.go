@@ -4,6 +4,8 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"math"
+	"time"
 )
 
 // Standard error types that can be used for error handling.
@@ -31,6 +33,18 @@ var (
 
 	// ErrUnavailable indicates that a service is unavailable.
 	ErrUnavailable = errors.New("service unavailable")
+
+	// ErrConflict indicates that a request conflicts with the current state
+	// of a resource, e.g. a stale version in an optimistic update.
+	ErrConflict = errors.New("conflict")
+
+	// ErrTooManyRequests indicates that the caller has exceeded a rate limit.
+	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrPreconditionFailed indicates that a conditional request's
+	// precondition does not hold, e.g. the version the caller expected to
+	// update is no longer the current one.
+	ErrPreconditionFailed = errors.New("precondition failed")
 )
 
 // DomainError represents a domain-specific error.
@@ -153,6 +167,49 @@ func NewInternal(err error) *DomainError {
 	return domainErr.WithMessage("internal error: %v", err)
 }
 
+// NewConflict creates a new conflict error.
+func NewConflict(entity string, id interface{}, reason string) *DomainError {
+	err := &DomainError{
+		Err:  ErrConflict,
+		Code: "conflict",
+		Details: map[string]interface{}{
+			"entity": entity,
+			"id":     id,
+			"reason": reason,
+		},
+	}
+	return err.WithMessage("%s with ID %v conflicts: %s", entity, id, reason)
+}
+
+// NewVersionMismatch creates a precondition failed error for an update that
+// expected a version of the entity other than the current one.
+func NewVersionMismatch(entity string, id interface{}, expected, actual string) *DomainError {
+	err := &DomainError{
+		Err:  ErrPreconditionFailed,
+		Code: "version_mismatch",
+		Details: map[string]interface{}{
+			"entity":           entity,
+			"id":               id,
+			"expected_version": expected,
+			"current_version":  actual,
+		},
+	}
+	return err.WithMessage("%s with ID %v is at version %s, not %s", entity, id, actual, expected)
+}
+
+// NewTooManyRequests creates a new rate limit error. retryAfter is the time
+// until the caller may retry; it is omitted from the details when zero.
+func NewTooManyRequests(retryAfter time.Duration) *DomainError {
+	err := &DomainError{
+		Err:  ErrTooManyRequests,
+		Code: "too_many_requests",
+	}
+	if retryAfter > 0 {
+		err.WithDetail("retry_after_seconds", int64(math.Ceil(retryAfter.Seconds())))
+	}
+	return err.WithMessage("too many requests")
+}
+
 // IsNotFound returns true if the error is a not found error.
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
@@ -192,3 +249,18 @@ func IsTimeout(err error) bool {
 func IsUnavailable(err error) bool {
 	return errors.Is(err, ErrUnavailable)
 }
+
+// IsConflict returns true if the error is a conflict error.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsPreconditionFailed returns true if the error is a precondition failed error.
+func IsPreconditionFailed(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+// IsTooManyRequests returns true if the error is a rate limit error.
+func IsTooManyRequests(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
@@ -0,0 +1,46 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConflict(t *testing.T) {
+	err := domainerrors.NewConflict("item", "42", "stale version")
+
+	assert.True(t, domainerrors.IsConflict(err))
+	assert.True(t, domainerrors.IsConflict(fmt.Errorf("update: %w", err)))
+	assert.False(t, domainerrors.IsTooManyRequests(err))
+	assert.Equal(t, "conflict", err.Code)
+	assert.Equal(t, "item with ID 42 conflicts: stale version", err.Error())
+	assert.Equal(t, map[string]interface{}{"entity": "item", "id": "42", "reason": "stale version"}, err.Details)
+}
+
+func TestNewVersionMismatch(t *testing.T) {
+	err := domainerrors.NewVersionMismatch("item", "42", "1", "2")
+
+	assert.True(t, domainerrors.IsPreconditionFailed(err))
+	assert.True(t, domainerrors.IsPreconditionFailed(fmt.Errorf("update: %w", err)))
+	assert.False(t, domainerrors.IsConflict(err))
+	assert.Equal(t, "version_mismatch", err.Code)
+	assert.Equal(t, "item with ID 42 is at version 2, not 1", err.Error())
+	assert.Equal(t, "1", err.Details["expected_version"])
+	assert.Equal(t, "2", err.Details["current_version"])
+}
+
+func TestNewTooManyRequests(t *testing.T) {
+	err := domainerrors.NewTooManyRequests(1500 * time.Millisecond)
+
+	assert.True(t, domainerrors.IsTooManyRequests(err))
+	assert.False(t, domainerrors.IsConflict(err))
+	assert.Equal(t, "too_many_requests", err.Code)
+	assert.Equal(t, int64(2), err.Details["retry_after_seconds"])
+
+	assert.Nil(t, domainerrors.NewTooManyRequests(0).Details)
+	assert.False(t, domainerrors.IsTooManyRequests(errors.New("too many requests")))
+}
@@ -36,6 +36,16 @@ type ItemFilter struct {
 	// SearchTerm searches in item name and description.
 	SearchTerm string
 
+	// IncludeDeleted makes the filter match soft-deleted items
+	// (entity.ItemStatusDeleted), which are excluded by default. Filtering on
+	// Status ItemStatusDeleted includes them as well.
+	IncludeDeleted bool
+
+	// Sort names the field to order results by, prefixed with "-" for
+	// descending order, e.g. "-created_at". Empty keeps the repository's
+	// default order.
+	Sort string
+
 	// Pagination parameters
 	Offset int
 	Limit  int
@@ -66,9 +76,24 @@ func (f ItemFilter) WithSearch(term string) ItemFilter {
 	return f
 }
 
-// WithPagination adds pagination to the filter.
+// WithSort orders results by a field; prefix it with "-" for descending order.
+func (f ItemFilter) WithSort(sort string) ItemFilter {
+	f.Sort = sort
+	return f
+}
+
+// WithDeleted makes the filter include soft-deleted items.
+func (f ItemFilter) WithDeleted() ItemFilter {
+	f.IncludeDeleted = true
+	return f
+}
+
+// WithPagination adds pagination to the filter. A negative offset or a
+// non-positive limit leaves the current value unchanged.
 func (f ItemFilter) WithPagination(offset, limit int) ItemFilter {
-	f.Offset = offset
+	if offset >= 0 {
+		f.Offset = offset
+	}
 	if limit > 0 {
 		f.Limit = limit
 	}
@@ -21,6 +21,19 @@ func TestItemFilterBuilders(t *testing.T) {
 		t.Fatalf("unexpected filter: %#v", f)
 	}
 
+	if f.Sort != "" || f.WithSort("-name").Sort != "-name" {
+		t.Fatalf("expected WithSort to set Sort")
+	}
+
+	if f.IncludeDeleted || !f.WithDeleted().IncludeDeleted {
+		t.Fatalf("expected WithDeleted to set IncludeDeleted")
+	}
+
+	// offset should not change when negative
+	if f3 := f.WithPagination(-1, 5); f3.Offset != f.Offset {
+		t.Fatalf("expected offset unchanged when negative, got %d", f3.Offset)
+	}
+
 	// limit should not change when non-positive
 	f2 := f.WithPagination(0, 0)
 	if f2.Limit != f.Limit {
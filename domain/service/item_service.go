@@ -3,13 +3,24 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
 	"github.com/next-trace/scg-service-api/domain/repository"
 )
 
 // ItemService provides business operations for items.
+//
+// Its errors are DomainErrors, so callers can classify them with
+// domainerrors.IsInvalidInput, IsNotFound and IsInternal:
+//   - empty IDs or tags and rejected entity changes are invalid input; entity
+//     errors such as entity.ErrInvalidStatusTransition stay in the chain,
+//   - repository not-found errors become NewNotFound("item", id),
+//   - any other repository failure is wrapped with NewInternal.
 type ItemService struct {
 	repo repository.ItemRepository
 }
@@ -24,12 +35,12 @@ func NewItemService(repo repository.ItemRepository) *ItemService {
 // GetItem retrieves an item by ID.
 func (s *ItemService) GetItem(ctx context.Context, id string) (*entity.Item, error) {
 	if id == "" {
-		return nil, fmt.Errorf("item ID cannot be empty")
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item: %w", err)
+		return nil, repositoryError(err, id, "failed to get item")
 	}
 
 	return item, nil
@@ -39,46 +50,124 @@ func (s *ItemService) GetItem(ctx context.Context, id string) (*entity.Item, err
 func (s *ItemService) ListItems(ctx context.Context, filter repository.ItemFilter) ([]*entity.Item, int64, error) {
 	items, err := s.repo.FindAll(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list items: %w", err)
+		return nil, 0, repositoryError(err, "", "failed to list items")
 	}
 
 	count, err := s.repo.Count(ctx, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count items: %w", err)
+		return nil, 0, repositoryError(err, "", "failed to count items")
 	}
 
 	return items, count, nil
 }
 
+// ItemPage is one page of items with its position in the whole result set.
+// Navigation links belong to the transport; the HTTP layer adds them with
+// pagination.NewItemPage.
+type ItemPage struct {
+	Items      []*entity.Item
+	Page       int
+	PageSize   int
+	TotalItems int64
+	TotalPages int
+}
+
+// SearchItems runs the query described by filter and returns the matching
+// page of items with the total count. Page numbers are derived from the
+// filter's Offset and Limit, so the offset must fall on a page boundary.
+func (s *ItemService) SearchItems(ctx context.Context, filter repository.ItemFilter) (*ItemPage, error) {
+	if filter.Limit <= 0 {
+		return nil, domainerrors.NewInvalidInput("page size must be positive")
+	}
+	if filter.Offset < 0 {
+		return nil, domainerrors.NewInvalidInput("offset cannot be negative")
+	}
+	if filter.Offset%filter.Limit != 0 {
+		return nil, domainerrors.NewInvalidInput("offset must be a multiple of the page size")
+	}
+
+	items, total, err := s.ListItems(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := filter.Limit
+	return &ItemPage{
+		Items:      items,
+		Page:       filter.Offset/pageSize + 1,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// ListItemsByTag retrieves items carrying tag, in addition to any criteria
+// already set on filter, along with their total count.
+func (s *ItemService) ListItemsByTag(ctx context.Context, tag string, filter repository.ItemFilter) ([]*entity.Item, int64, error) {
+	if tag == "" {
+		return nil, 0, domainerrors.NewInvalidInput("tag cannot be empty")
+	}
+
+	tags := make([]string, 0, len(filter.Tags)+1)
+	tags = append(tags, filter.Tags...)
+	if !slices.Contains(tags, tag) {
+		tags = append(tags, tag)
+	}
+
+	return s.ListItems(ctx, filter.WithTags(tags))
+}
+
 // CreateItem creates a new item.
 func (s *ItemService) CreateItem(ctx context.Context, name, description string, tags []string) (*entity.Item, error) {
 	item, err := entity.NewItem(name, description, tags)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create item: %w", err)
+		return nil, invalidInput(err)
 	}
 
 	if err := s.repo.Save(ctx, item); err != nil {
-		return nil, fmt.Errorf("failed to save item: %w", err)
+		return nil, repositoryError(err, item.ID, "failed to save item")
 	}
 
 	return item, nil
 }
 
-// UpdateItem updates an existing item.
+// UpdateItem updates an existing item. The item is only saved if the update
+// changed it.
 func (s *ItemService) UpdateItem(ctx context.Context, id, name, description string, tags []string, status entity.ItemStatus) (*entity.Item, error) {
+	return s.UpdateItemIfVersion(ctx, id, nil, name, description, tags, status)
+}
+
+// UpdateItemIfVersion is UpdateItem with optimistic concurrency: unless
+// expectedVersions is empty, the update fails with a version mismatch
+// (domainerrors.ErrPreconditionFailed) when the item's current Version is
+// none of expectedVersions.
+func (s *ItemService) UpdateItemIfVersion(ctx context.Context, id string, expectedVersions []string, name, description string, tags []string, status entity.ItemStatus) (*entity.Item, error) {
 	if id == "" {
-		return nil, fmt.Errorf("item ID cannot be empty")
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item for update: %w", err)
+		return nil, repositoryError(err, id, "failed to get item for update")
+	}
+	if len(expectedVersions) > 0 && !slices.Contains(expectedVersions, item.Version()) {
+		return nil, domainerrors.NewVersionMismatch("item", id, strings.Join(expectedVersions, ", "), item.Version())
+	}
+
+	before := *item
+	before.Tags = slices.Clone(item.Tags)
+
+	if err := item.Update(name, description, tags, status); err != nil {
+		return nil, invalidInput(err)
 	}
 
-	item.Update(name, description, tags, status)
+	if item.Equal(&before) {
+		item.UpdatedAt = before.UpdatedAt
+		return item, nil // Nothing changed
+	}
 
 	if err := s.repo.Save(ctx, item); err != nil {
-		return nil, fmt.Errorf("failed to save updated item: %w", err)
+		return nil, repositoryError(err, id, "failed to save updated item")
 	}
 
 	return item, nil
@@ -87,24 +176,26 @@ func (s *ItemService) UpdateItem(ctx context.Context, id, name, description stri
 // DeleteItem deletes an item by ID.
 func (s *ItemService) DeleteItem(ctx context.Context, id string) error {
 	if id == "" {
-		return fmt.Errorf("item ID cannot be empty")
+		return domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	// Option 1: Hard delete - remove from repository
 	if err := s.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete item: %w", err)
+		return repositoryError(err, id, "failed to delete item")
 	}
 
 	// Option 2: Soft delete - mark as deleted
 	// item, err := s.repo.GetByID(ctx, id)
 	// if err != nil {
-	//     return fmt.Errorf("failed to get item for deletion: %w", err)
+	//     return repositoryError(err, id, "failed to get item for deletion")
 	// }
 	//
-	// item.Delete()
+	// if err := item.Delete(); err != nil {
+	//     return repositoryError(err, id, "failed to delete item")
+	// }
 	//
 	// if err := s.repo.Save(ctx, item); err != nil {
-	//     return fmt.Errorf("failed to save deleted item: %w", err)
+	//     return repositoryError(err, id, "failed to save deleted item")
 	// }
 
 	return nil
@@ -113,22 +204,24 @@ func (s *ItemService) DeleteItem(ctx context.Context, id string) error {
 // ActivateItem activates an inactive item.
 func (s *ItemService) ActivateItem(ctx context.Context, id string) (*entity.Item, error) {
 	if id == "" {
-		return nil, fmt.Errorf("item ID cannot be empty")
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item for activation: %w", err)
+		return nil, repositoryError(err, id, "failed to get item for activation")
 	}
 
 	if item.IsActive() {
 		return item, nil // Already active
 	}
 
-	item.Activate()
+	if err := item.Activate(); err != nil {
+		return nil, invalidInput(err)
+	}
 
 	if err := s.repo.Save(ctx, item); err != nil {
-		return nil, fmt.Errorf("failed to save activated item: %w", err)
+		return nil, repositoryError(err, id, "failed to save activated item")
 	}
 
 	return item, nil
@@ -137,22 +230,24 @@ func (s *ItemService) ActivateItem(ctx context.Context, id string) (*entity.Item
 // DeactivateItem deactivates an active item.
 func (s *ItemService) DeactivateItem(ctx context.Context, id string) (*entity.Item, error) {
 	if id == "" {
-		return nil, fmt.Errorf("item ID cannot be empty")
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item for deactivation: %w", err)
+		return nil, repositoryError(err, id, "failed to get item for deactivation")
 	}
 
 	if !item.IsActive() {
 		return item, nil // Already inactive
 	}
 
-	item.Deactivate()
+	if err := item.Deactivate(); err != nil {
+		return nil, invalidInput(err)
+	}
 
 	if err := s.repo.Save(ctx, item); err != nil {
-		return nil, fmt.Errorf("failed to save deactivated item: %w", err)
+		return nil, repositoryError(err, id, "failed to save deactivated item")
 	}
 
 	return item, nil
@@ -161,22 +256,42 @@ func (s *ItemService) DeactivateItem(ctx context.Context, id string) (*entity.It
 // AddTagToItem adds a tag to an item.
 func (s *ItemService) AddTagToItem(ctx context.Context, id, tag string) (*entity.Item, error) {
 	if id == "" {
-		return nil, fmt.Errorf("item ID cannot be empty")
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	if tag == "" {
-		return nil, fmt.Errorf("tag cannot be empty")
+		return nil, domainerrors.NewInvalidInput("tag cannot be empty")
 	}
 
 	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item for adding tag: %w", err)
+		return nil, repositoryError(err, id, "failed to get item for adding tag")
 	}
 
 	item.AddTag(tag)
 
 	if err := s.repo.Save(ctx, item); err != nil {
-		return nil, fmt.Errorf("failed to save item with new tag: %w", err)
+		return nil, repositoryError(err, id, "failed to save item with new tag")
+	}
+
+	return item, nil
+}
+
+// SetTagsOnItem replaces all tags on an item and saves it once.
+func (s *ItemService) SetTagsOnItem(ctx context.Context, id string, tags []string) (*entity.Item, error) {
+	if id == "" {
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
+	}
+
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, repositoryError(err, id, "failed to get item for setting tags")
+	}
+
+	item.SetTags(tags)
+
+	if err := s.repo.Save(ctx, item); err != nil {
+		return nil, repositoryError(err, id, "failed to save item with new tags")
 	}
 
 	return item, nil
@@ -185,23 +300,47 @@ func (s *ItemService) AddTagToItem(ctx context.Context, id, tag string) (*entity
 // RemoveTagFromItem removes a tag from an item.
 func (s *ItemService) RemoveTagFromItem(ctx context.Context, id, tag string) (*entity.Item, error) {
 	if id == "" {
-		return nil, fmt.Errorf("item ID cannot be empty")
+		return nil, domainerrors.NewInvalidInput("item ID cannot be empty")
 	}
 
 	if tag == "" {
-		return nil, fmt.Errorf("tag cannot be empty")
+		return nil, domainerrors.NewInvalidInput("tag cannot be empty")
 	}
 
 	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get item for removing tag: %w", err)
+		return nil, repositoryError(err, id, "failed to get item for removing tag")
 	}
 
 	item.RemoveTag(tag)
 
 	if err := s.repo.Save(ctx, item); err != nil {
-		return nil, fmt.Errorf("failed to save item after removing tag: %w", err)
+		return nil, repositoryError(err, id, "failed to save item after removing tag")
 	}
 
 	return item, nil
 }
+
+// invalidInput classifies an entity error as invalid input while keeping it
+// in the error chain.
+func invalidInput(err error) error {
+	return &domainerrors.DomainError{
+		Err:  fmt.Errorf("%w: %w", domainerrors.ErrInvalidInput, err),
+		Code: "invalid_input",
+	}
+}
+
+// repositoryError translates a repository failure for the item id: not-found
+// errors become a NotFound DomainError, DomainErrors are returned unchanged
+// and anything else is wrapped as an internal error.
+func repositoryError(err error, id, action string) error {
+	var domainErr *domainerrors.DomainError
+	switch {
+	case domainerrors.IsNotFound(err):
+		return domainerrors.NewNotFound("item", id)
+	case errors.As(err, &domainErr):
+		return err
+	default:
+		return domainerrors.NewInternal(fmt.Errorf("%s: %w", action, err))
+	}
+}
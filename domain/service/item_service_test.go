@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
 	"github.com/next-trace/scg-service-api/domain/repository"
 	servicepkg "github.com/next-trace/scg-service-api/domain/service"
+	"github.com/next-trace/scg-service-api/testsupport"
 )
 
 type fakeRepo struct {
@@ -179,3 +181,231 @@ func TestItemService_ErrorsOnEmptyID(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestItemService_ActivateDeletedItemRejected(t *testing.T) {
+	repo := newFakeRepo()
+	s := servicepkg.NewItemService(repo)
+	ctx := context.Background()
+
+	it, err := entity.NewItem("n", "d", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := it.Delete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.items[it.ID] = it
+
+	_, err = s.ActivateItem(ctx, it.ID)
+	if !errors.Is(err, entity.ErrInvalidStatusTransition) {
+		t.Fatalf("expected ErrInvalidStatusTransition, got %v", err)
+	}
+	if !domainerrors.IsInvalidInput(err) {
+		t.Fatalf("expected rejected transition to be invalid input, got %v", err)
+	}
+	if repo.saveN != 0 {
+		t.Fatalf("expected no Save for rejected transition, got %d", repo.saveN)
+	}
+}
+
+func TestItemService_SetTagsOnItemSavesOnce(t *testing.T) {
+	repo := newFakeRepo()
+	s := servicepkg.NewItemService(repo)
+	ctx := context.Background()
+
+	it, err := entity.NewItem("n", "d", []string{"x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.items[it.ID] = it
+
+	updated, err := s.SetTagsOnItem(ctx, it.ID, []string{"a", "b", "a", "c"})
+	if err != nil {
+		t.Fatalf("set tags error: %v", err)
+	}
+	if repo.saveN != 1 {
+		t.Fatalf("expected exactly one Save, got %d", repo.saveN)
+	}
+	if len(updated.Tags) != 3 || !updated.HasTag("a") || !updated.HasTag("b") || !updated.HasTag("c") || updated.HasTag("x") {
+		t.Fatalf("unexpected final tags: %v", updated.Tags)
+	}
+
+	if _, err := s.SetTagsOnItem(ctx, "", []string{"a"}); err == nil {
+		t.Fatalf("expected error for empty ID")
+	}
+}
+
+func TestItemService_ListItemsByTag(t *testing.T) {
+	repo := testsupport.NewItemRepository(
+		&entity.Item{ID: "1", Name: "a", Tags: []string{"red", "big"}, Status: entity.ItemStatusActive},
+		&entity.Item{ID: "2", Name: "b", Tags: []string{"red"}, Status: entity.ItemStatusActive},
+		&entity.Item{ID: "3", Name: "c", Tags: []string{"blue"}, Status: entity.ItemStatusActive},
+		&entity.Item{ID: "4", Name: "d", Tags: []string{"red"}, Status: entity.ItemStatusInactive},
+	)
+	s := servicepkg.NewItemService(repo)
+	ctx := context.Background()
+
+	filter := repository.NewItemFilter().WithStatus(entity.ItemStatusActive)
+	items, count, err := s.ListItemsByTag(ctx, "red", filter)
+	if err != nil {
+		t.Fatalf("list by tag error: %v", err)
+	}
+	if len(items) != 2 || count != 2 {
+		t.Fatalf("expected 2 active red items, got items=%d count=%d", len(items), count)
+	}
+	for _, it := range items {
+		if !it.HasTag("red") || !it.IsActive() {
+			t.Fatalf("unexpected item returned: %#v", it)
+		}
+	}
+
+	// Tags already on the filter still apply.
+	items, count, err = s.ListItemsByTag(ctx, "red", filter.WithTags([]string{"big"}))
+	if err != nil || len(items) != 1 || count != 1 || items[0].ID != "1" {
+		t.Fatalf("expected only item 1, got items=%v count=%d err=%v", items, count, err)
+	}
+	if len(filter.Tags) != 0 {
+		t.Fatalf("expected caller's filter to be left untouched, got %v", filter.Tags)
+	}
+
+	if _, _, err := s.ListItemsByTag(ctx, "", filter); err == nil {
+		t.Fatalf("expected error for empty tag")
+	}
+}
+
+func TestItemService_UpdateItemSkipsUnchangedSave(t *testing.T) {
+	repo := newFakeRepo()
+	s := servicepkg.NewItemService(repo)
+	ctx := context.Background()
+
+	it, err := entity.NewItem("n", "d", []string{"x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.items[it.ID] = it
+	updatedAt := it.UpdatedAt
+
+	got, err := s.UpdateItem(ctx, it.ID, "n", "d", []string{"x"}, entity.ItemStatusActive)
+	if err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if repo.saveN != 0 {
+		t.Fatalf("expected no Save for a no-op update, got %d", repo.saveN)
+	}
+	if !got.UpdatedAt.Equal(updatedAt) {
+		t.Fatalf("expected UpdatedAt untouched by a no-op update")
+	}
+
+	if _, err := s.UpdateItem(ctx, it.ID, "n2", "", nil, ""); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if repo.saveN != 1 {
+		t.Fatalf("expected one Save for a real change, got %d", repo.saveN)
+	}
+}
+
+func TestItemService_UpdateItemIfVersion(t *testing.T) {
+	ctx := context.Background()
+	it, err := entity.NewItem("n", "d", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := servicepkg.NewItemService(testsupport.NewItemRepository(it))
+
+	if _, err := s.UpdateItemIfVersion(ctx, it.ID, []string{"stale"}, "n2", "", nil, ""); !domainerrors.IsPreconditionFailed(err) {
+		t.Fatalf("expected a version mismatch, got %v", err)
+	}
+	updated, err := s.UpdateItemIfVersion(ctx, it.ID, []string{"stale", it.Version()}, "n2", "", nil, "")
+	if err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if updated.Version() == it.Version() {
+		t.Fatalf("expected the version to change with the update")
+	}
+	if _, err := s.UpdateItemIfVersion(ctx, it.ID, []string{it.Version()}, "n3", "", nil, ""); !domainerrors.IsPreconditionFailed(err) {
+		t.Fatalf("expected the old version to be stale, got %v", err)
+	}
+}
+
+func TestItemService_TypedErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("empty ID is invalid input", func(t *testing.T) {
+		s := servicepkg.NewItemService(newFakeRepo())
+		if _, err := s.GetItem(ctx, ""); !domainerrors.IsInvalidInput(err) {
+			t.Fatalf("expected invalid input, got %v", err)
+		}
+		if _, err := s.AddTagToItem(ctx, "id", ""); !domainerrors.IsInvalidInput(err) {
+			t.Fatalf("expected invalid input for empty tag, got %v", err)
+		}
+	})
+
+	t.Run("missing item is not found", func(t *testing.T) {
+		s := servicepkg.NewItemService(testsupport.NewItemRepository())
+		_, err := s.GetItem(ctx, "nope")
+		if !domainerrors.IsNotFound(err) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		var domainErr *domainerrors.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Details["id"] != "nope" {
+			t.Fatalf("expected DomainError for item nope, got %#v", err)
+		}
+		if _, err := s.ActivateItem(ctx, "nope"); !domainerrors.IsNotFound(err) {
+			t.Fatalf("expected not found from ActivateItem, got %v", err)
+		}
+	})
+
+	t.Run("rejected entity change is invalid input", func(t *testing.T) {
+		s := servicepkg.NewItemService(newFakeRepo())
+		_, err := s.CreateItem(ctx, "", "d", nil)
+		if !domainerrors.IsInvalidInput(err) {
+			t.Fatalf("expected invalid input, got %v", err)
+		}
+	})
+
+	t.Run("unexpected repository error is internal", func(t *testing.T) {
+		repo := newFakeRepo()
+		repo.findErr = errors.New("connection reset")
+		s := servicepkg.NewItemService(repo)
+		_, _, err := s.ListItems(ctx, repository.NewItemFilter())
+		if !domainerrors.IsInternal(err) {
+			t.Fatalf("expected internal error, got %v", err)
+		}
+	})
+}
+
+func TestItemService_SearchItems(t *testing.T) {
+	var items []*entity.Item
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		items = append(items, &entity.Item{ID: id, Name: "item " + id, Status: entity.ItemStatusActive})
+	}
+	s := servicepkg.NewItemService(testsupport.NewItemRepository(items...))
+	ctx := context.Background()
+
+	page, err := s.SearchItems(ctx, repository.NewItemFilter().WithPagination(2, 2))
+	if err != nil {
+		t.Fatalf("search error: %v", err)
+	}
+	if page.Page != 2 || page.PageSize != 2 || page.TotalItems != 5 || page.TotalPages != 3 {
+		t.Fatalf("unexpected page metadata: %+v", page)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != "3" || page.Items[1].ID != "4" {
+		t.Fatalf("unexpected page items: %v", page.Items)
+	}
+
+	page, err = s.SearchItems(ctx, repository.NewItemFilter().WithPagination(4, 2))
+	if err != nil {
+		t.Fatalf("search error: %v", err)
+	}
+	if page.Page != 3 || len(page.Items) != 1 {
+		t.Fatalf("unexpected last page: %+v", page)
+	}
+
+	// An offset between page boundaries has no page number.
+	if _, err := s.SearchItems(ctx, repository.NewItemFilter().WithPagination(3, 2)); !domainerrors.IsInvalidInput(err) {
+		t.Fatalf("expected invalid input for an unaligned offset, got %v", err)
+	}
+	if _, err := s.SearchItems(ctx, repository.ItemFilter{}); !domainerrors.IsInvalidInput(err) {
+		t.Fatalf("expected invalid input for a zero page size, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	appcircuitbreaker "github.com/next-trace/scg-service-api/application/circuitbreaker"
+)
+
+// CircuitBreakerName is the breaker name NewCircuitBreakingCache executes
+// cache operations under.
+const CircuitBreakerName = "cache"
+
+// breakingCache guards the operations of a remote cache with a circuit
+// breaker so that an unavailable store fails fast instead of timing out.
+type breakingCache struct {
+	appcache.Cache
+	cb         appcircuitbreaker.CircuitBreaker
+	dropWrites bool
+
+	// pinger is the wrapped store if it implements appcache.Pinger, else nil.
+	pinger appcache.Pinger
+}
+
+// NewCircuitBreakingCache wraps a remote cache store so that its operations
+// run through cb under CircuitBreakerName. Once the breaker opens, lookups
+// report a miss and writes return the breaker's error without touching the
+// store; with config.Redis.DropWritesWhenOpen set, rejected writes report
+// success instead. Errors from the store itself are always returned.
+//
+// Lookups report only hit or miss, so a miss is checked with Ping when next
+// implements appcache.Pinger, and counts as a failure if the ping fails. A
+// store without Ping cannot report failed lookups: its lookups are not
+// recorded and run only while the breaker is closed, leaving the half-open
+// trial calls to writes. A nil cb returns next unchanged. Optional
+// capabilities such as KeyLister are not forwarded.
+func NewCircuitBreakingCache(config appcache.Config, next appcache.Cache, cb appcircuitbreaker.CircuitBreaker) appcache.Cache {
+	if cb == nil {
+		return next
+	}
+	pinger, _ := next.(appcache.Pinger)
+	return &breakingCache{
+		Cache:      next,
+		cb:         cb,
+		dropWrites: config.Redis.DropWritesWhenOpen,
+		pinger:     pinger,
+	}
+}
+
+// Get runs the wrapped Get, reporting a miss while the breaker is open.
+func (c *breakingCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	var value interface{}
+	found := false
+	c.read(ctx, func(ctx context.Context) bool {
+		value, found = c.Cache.Get(ctx, key)
+		return found
+	})
+	return value, found
+}
+
+// GetWithType runs the wrapped GetWithType, reporting a miss while the
+// breaker is open.
+func (c *breakingCache) GetWithType(ctx context.Context, key string, value interface{}) bool {
+	found := false
+	c.read(ctx, func(ctx context.Context) bool {
+		found = c.Cache.GetWithType(ctx, key, value)
+		return found
+	})
+	return found
+}
+
+// Has runs the wrapped Has, reporting false while the breaker is open.
+func (c *breakingCache) Has(ctx context.Context, key string) bool {
+	found := false
+	c.read(ctx, func(ctx context.Context) bool {
+		found = c.Cache.Has(ctx, key)
+		return found
+	})
+	return found
+}
+
+// GetMulti runs the wrapped GetMulti, reporting every key missing while the
+// breaker is open.
+func (c *breakingCache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, []string) {
+	var (
+		found   map[string]interface{}
+		missing []string
+	)
+	if !c.read(ctx, func(ctx context.Context) bool {
+		found, missing = c.Cache.GetMulti(ctx, keys)
+		return len(missing) == 0
+	}) {
+		return map[string]interface{}{}, append([]string(nil), keys...)
+	}
+	return found, missing
+}
+
+// Set runs the wrapped Set through the breaker.
+func (c *breakingCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.Set(ctx, key, value, ttl) })
+}
+
+// SetDefault runs the wrapped SetDefault through the breaker.
+func (c *breakingCache) SetDefault(ctx context.Context, key string, value interface{}) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.SetDefault(ctx, key, value) })
+}
+
+// SetMulti runs the wrapped SetMulti through the breaker.
+func (c *breakingCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.SetMulti(ctx, items, ttl) })
+}
+
+// SetMultiTTL runs the wrapped SetMultiTTL through the breaker.
+func (c *breakingCache) SetMultiTTL(ctx context.Context, items map[string]appcache.CacheItem) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.SetMultiTTL(ctx, items) })
+}
+
+// Delete runs the wrapped Delete through the breaker.
+func (c *breakingCache) Delete(ctx context.Context, key string) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.Delete(ctx, key) })
+}
+
+// DeleteMulti runs the wrapped DeleteMulti through the breaker.
+func (c *breakingCache) DeleteMulti(ctx context.Context, keys []string) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.DeleteMulti(ctx, keys) })
+}
+
+// Clear runs the wrapped Clear through the breaker.
+func (c *breakingCache) Clear(ctx context.Context) error {
+	return c.write(ctx, func(ctx context.Context) error { return c.Cache.Clear(ctx) })
+}
+
+// SetNX runs the wrapped SetNX through the breaker. Its result depends on
+// the store, so a rejected call always returns the breaker's error.
+func (c *breakingCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return appcircuitbreaker.ExecuteTyped(ctx, c.cb, CircuitBreakerName, func(ctx context.Context) (bool, error) {
+		return c.Cache.SetNX(ctx, key, value, ttl)
+	})
+}
+
+// CompareAndSwap runs the wrapped CompareAndSwap through the breaker. A
+// rejected call always returns the breaker's error.
+func (c *breakingCache) CompareAndSwap(ctx context.Context, key string, old, newValue interface{}, ttl time.Duration) (bool, error) {
+	return appcircuitbreaker.ExecuteTyped(ctx, c.cb, CircuitBreakerName, func(ctx context.Context) (bool, error) {
+		return c.Cache.CompareAndSwap(ctx, key, old, newValue, ttl)
+	})
+}
+
+// Increment runs the wrapped Increment through the breaker. A rejected call
+// always returns the breaker's error.
+func (c *breakingCache) Increment(ctx context.Context, key string, amount int64) (int64, error) {
+	return appcircuitbreaker.ExecuteTyped(ctx, c.cb, CircuitBreakerName, func(ctx context.Context) (int64, error) {
+		return c.Cache.Increment(ctx, key, amount)
+	})
+}
+
+// IncrementWithTTL runs the wrapped IncrementWithTTL through the breaker. A
+// rejected call always returns the breaker's error.
+func (c *breakingCache) IncrementWithTTL(ctx context.Context, key string, amount int64, ttl time.Duration) (int64, error) {
+	return appcircuitbreaker.ExecuteTyped(ctx, c.cb, CircuitBreakerName, func(ctx context.Context) (int64, error) {
+		return c.Cache.IncrementWithTTL(ctx, key, amount, ttl)
+	})
+}
+
+// Decrement runs the wrapped Decrement through the breaker. A rejected call
+// always returns the breaker's error.
+func (c *breakingCache) Decrement(ctx context.Context, key string, amount int64) (int64, error) {
+	return appcircuitbreaker.ExecuteTyped(ctx, c.cb, CircuitBreakerName, func(ctx context.Context) (int64, error) {
+		return c.Cache.Decrement(ctx, key, amount)
+	})
+}
+
+// read runs a lookup through the breaker and reports whether it ran. op
+// reports whether every key was found; otherwise the store is pinged, so a
+// miss caused by an unreachable store counts as a failure. Without a pinger,
+// see NewCircuitBreakingCache, the lookup runs outside the breaker while it
+// is closed: counting unverifiable misses as successes would keep the failure
+// ratio of a failing store below the threshold.
+func (c *breakingCache) read(ctx context.Context, op func(ctx context.Context) bool) bool {
+	if c.pinger == nil {
+		if c.cb.GetState(CircuitBreakerName) != appcircuitbreaker.StateClosed {
+			return false
+		}
+		op(ctx)
+		return true
+	}
+
+	ran := false
+	_, _ = c.cb.Execute(ctx, CircuitBreakerName, func(ctx context.Context) (interface{}, error) {
+		ran = true
+		if op(ctx) {
+			return nil, nil
+		}
+		return nil, c.pinger.Ping(ctx)
+	})
+	return ran
+}
+
+// write runs a write through the breaker. A write the breaker rejected
+// without running reports success when dropWrites is set.
+func (c *breakingCache) write(ctx context.Context, op func(ctx context.Context) error) error {
+	ran := false
+	_, err := c.cb.Execute(ctx, CircuitBreakerName, func(ctx context.Context) (interface{}, error) {
+		ran = true
+		return nil, op(ctx)
+	})
+	if err != nil && !ran && c.dropWrites {
+		return nil
+	}
+	return err
+}
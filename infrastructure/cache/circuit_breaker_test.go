@@ -0,0 +1,192 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	appcircuitbreaker "github.com/next-trace/scg-service-api/application/circuitbreaker"
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+	cbimpl "github.com/next-trace/scg-service-api/infrastructure/circuitbreaker"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+// downCache simulates an unreachable store: writes fail with a connection
+// error and every call to the store is counted.
+type downCache struct {
+	*testsupport.Cache
+	calls int
+}
+
+func (d *downCache) Get(context.Context, string) (interface{}, bool) {
+	d.calls++
+	return nil, false
+}
+
+func (d *downCache) Set(context.Context, string, interface{}, time.Duration) error {
+	d.calls++
+	return connReset()
+}
+
+func newCacheBreaker() appcircuitbreaker.CircuitBreaker {
+	cfg := appcircuitbreaker.DefaultConfig()
+	cfg.Enabled = true
+	cfg.RequestVolumeThreshold = 3
+	cfg.ErrorThresholdPercentage = 50
+	cfg.SleepWindow = time.Minute
+	return cbimpl.NewGoBreakerAdapter(cfg, nil)
+}
+
+func TestCircuitBreakingCache_FailsFastWhenOpen(t *testing.T) {
+	ctx := context.Background()
+	cb := newCacheBreaker()
+	store := &downCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.NewCircuitBreakingCache(appcache.DefaultConfig(), store, cb)
+
+	for i := 0; i < 3; i++ {
+		if err := c.Set(ctx, "k", "v", 0); err == nil {
+			t.Fatalf("expected the store's error")
+		}
+	}
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateOpen {
+		t.Fatalf("expected the breaker to open, got %s", state)
+	}
+
+	calls := store.calls
+	if err := c.Set(ctx, "k", "v", 0); err == nil {
+		t.Fatalf("expected Set to fail while the breaker is open")
+	}
+	if v, ok := c.Get(ctx, "k"); ok || v != nil {
+		t.Fatalf("expected a miss while the breaker is open, got %v %v", v, ok)
+	}
+	if found, missing := c.GetMulti(ctx, []string{"a", "b"}); len(found) != 0 || len(missing) != 2 {
+		t.Fatalf("expected every key missing, got %v %v", found, missing)
+	}
+	if store.calls != calls {
+		t.Fatalf("expected no calls to reach the store, got %d more", store.calls-calls)
+	}
+}
+
+func TestCircuitBreakingCache_DropWritesWhenOpen(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.Redis.DropWritesWhenOpen = true
+	store := &downCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.NewCircuitBreakingCache(cfg, store, newCacheBreaker())
+
+	for i := 0; i < 3; i++ {
+		if err := c.Set(ctx, "k", "v", 0); err == nil {
+			t.Fatalf("expected the store's error to be returned while closed")
+		}
+	}
+	if err := c.Set(ctx, "k", "v", 0); err != nil {
+		t.Fatalf("expected the rejected write to be dropped, got %v", err)
+	}
+}
+
+func TestCircuitBreakingCache_ReadsDoNotMaskFailingWrites(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcircuitbreaker.DefaultConfig()
+	cfg.Enabled = true
+	cfg.RequestVolumeThreshold = 3
+	cfg.ErrorThresholdPercentage = 50
+	cfg.SleepWindow = 20 * time.Millisecond
+	cb := cbimpl.NewGoBreakerAdapter(cfg, nil)
+	store := &downCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.NewCircuitBreakingCache(appcache.DefaultConfig(), store, cb)
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 10; j++ {
+			c.Get(ctx, "k")
+		}
+		_ = c.Set(ctx, "k", "v", 0)
+	}
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateOpen {
+		t.Fatalf("expected failing writes to open the breaker despite the reads, got %s", state)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateHalfOpen {
+		t.Fatalf("expected the breaker to be half-open, got %s", state)
+	}
+	calls := store.calls
+	c.Get(ctx, "k")
+	if store.calls != calls {
+		t.Fatalf("expected a read that cannot report errors to stay off the half-open store")
+	}
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateHalfOpen {
+		t.Fatalf("expected a read not to close the half-open breaker, got %s", state)
+	}
+}
+
+// unreachableReadsCache simulates a store that accepts writes but whose
+// lookups fail: every Get misses and Ping reports the outage.
+type unreachableReadsCache struct {
+	*testsupport.Cache
+	gets int
+}
+
+func (u *unreachableReadsCache) Get(context.Context, string) (interface{}, bool) {
+	u.gets++
+	return nil, false
+}
+
+func (u *unreachableReadsCache) Ping(context.Context) error {
+	return connReset()
+}
+
+func TestCircuitBreakingCache_FailingReadsOpenBreaker(t *testing.T) {
+	ctx := context.Background()
+	cb := newCacheBreaker()
+	store := &unreachableReadsCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.NewCircuitBreakingCache(appcache.DefaultConfig(), store, cb)
+
+	for i := 0; i < 3; i++ {
+		if _, ok := c.Get(ctx, "k"); ok {
+			t.Fatalf("expected a miss")
+		}
+	}
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateOpen {
+		t.Fatalf("expected failing reads to open the breaker, got %s", state)
+	}
+
+	gets := store.gets
+	for i := 0; i < 5; i++ {
+		c.Get(ctx, "k")
+	}
+	if store.gets != gets {
+		t.Fatalf("expected reads to fail fast while open, got %d store calls", store.gets-gets)
+	}
+}
+
+func TestCircuitBreakingCache_HalfOpenLimitsReads(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcircuitbreaker.DefaultConfig()
+	cfg.Enabled = true
+	cfg.RequestVolumeThreshold = 3
+	cfg.ErrorThresholdPercentage = 50
+	cfg.SleepWindow = 20 * time.Millisecond
+	cb := cbimpl.NewGoBreakerAdapter(cfg, nil)
+	store := &unreachableReadsCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.NewCircuitBreakingCache(appcache.DefaultConfig(), store, cb)
+
+	for i := 0; i < 3; i++ {
+		c.Get(ctx, "k")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateHalfOpen {
+		t.Fatalf("expected the breaker to be half-open, got %s", state)
+	}
+
+	gets := store.gets
+	for i := 0; i < 5; i++ {
+		c.Get(ctx, "k")
+	}
+	if store.gets != gets+1 {
+		t.Fatalf("expected one trial read while half-open, got %d", store.gets-gets)
+	}
+	if state := cb.GetState(cacheimpl.CircuitBreakerName); state != appcircuitbreaker.StateOpen {
+		t.Fatalf("expected the failed trial read to reopen the breaker, got %s", state)
+	}
+}
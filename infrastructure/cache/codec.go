@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// jsonCodec encodes values with encoding/json.
+type jsonCodec struct{}
+
+// NewJSONCodec returns the default codec. JSON is portable and readable but
+// loses type information: numbers in interface{} fields decode as float64 and
+// unexported fields are dropped.
+func NewJSONCodec() appcache.Codec {
+	return jsonCodec{}
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// gobCodec encodes values with encoding/gob.
+type gobCodec struct{}
+
+// NewGobCodec returns a codec using encoding/gob, which keeps Go types such
+// as integer widths and time.Time exactly and is faster than JSON for
+// structs. Concrete types stored behind interface{} fields must be
+// registered with gob.Register.
+func NewGobCodec() appcache.Codec {
+	return gobCodec{}
+}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// msgpackCodec encodes values with MessagePack.
+type msgpackCodec struct{}
+
+// NewMsgpackCodec returns a codec using MessagePack
+// (github.com/vmihailenco/msgpack/v5), a compact binary format that, unlike
+// gob, other languages can read. It keeps integer widths and time.Time, and
+// needs no type registration; numbers in interface{} fields decode as the
+// smallest fitting Go type, e.g. int8.
+func NewMsgpackCodec() appcache.Codec {
+	return msgpackCodec{}
+}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
@@ -0,0 +1,63 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	ID       string
+	Quantity int64
+	Placed   time.Time
+	Tags     []string
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	placed := time.Date(2024, 5, 1, 12, 30, 0, 123, time.UTC)
+	want := order{ID: "o-1", Quantity: 1 << 60, Placed: placed, Tags: []string{"a", "b"}}
+
+	codecs := map[string]appcache.Codec{
+		"json":    cacheimpl.NewJSONCodec(),
+		"gob":     cacheimpl.NewGobCodec(),
+		"msgpack": cacheimpl.NewMsgpackCodec(),
+	}
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.Marshal(want)
+			assert.NoError(t, err)
+
+			var got order
+			assert.NoError(t, codec.Unmarshal(data, &got))
+			assert.Equal(t, want.ID, got.ID)
+			assert.Equal(t, want.Quantity, got.Quantity)
+			assert.True(t, want.Placed.Equal(got.Placed))
+			assert.Equal(t, want.Tags, got.Tags)
+		})
+	}
+}
+
+func TestMemoryAdapter_GetWithTypeUsesCodec(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	cfg.Codec = cacheimpl.NewGobCodec()
+	c := cacheimpl.NewMemoryAdapter(cfg, nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	encoded, err := cfg.Codec.Marshal(order{ID: "o-2", Quantity: 3})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Set(ctx, "raw", encoded, 0))
+	assert.NoError(t, c.Set(ctx, "value", order{ID: "o-3", Quantity: 4}, 0))
+
+	var got order
+	assert.True(t, c.GetWithType(ctx, "raw", &got))
+	assert.Equal(t, "o-2", got.ID)
+
+	assert.True(t, c.GetWithType(ctx, "value", &got))
+	assert.Equal(t, int64(4), got.Quantity)
+}
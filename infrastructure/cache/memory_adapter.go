@@ -3,27 +3,39 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"reflect"
 	"sync"
 	"time"
 
 	appcache "github.com/next-trace/scg-service-api/application/cache"
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
 )
 
+// Metrics recorded by the memory adapter when it is given WithMetrics.
+const (
+	entriesMetric   = "cache_entries"
+	evictionsMetric = "cache_evictions_total"
+)
+
+// errCacheDisabled is returned by operations that cannot be silently skipped
+// when the cache is disabled.
+var errCacheDisabled = errors.New("cache is disabled")
+
 // cacheEntry represents an entry in the memory cache.
 type cacheEntry struct {
 	value      interface{}
 	expiration time.Time
+	ttl        time.Duration // TTL the entry was stored with, for sliding expiration
 }
 
-// isExpired returns true if the entry has expired.
-func (e *cacheEntry) isExpired() bool {
+// isExpired returns true if the entry has expired at now.
+func (e *cacheEntry) isExpired(now time.Time) bool {
 	if e.expiration.IsZero() {
 		return false
 	}
-	return time.Now().After(e.expiration)
+	return now.After(e.expiration)
 }
 
 // memoryAdapter implements the cache.Cache interface using an in-memory map.
@@ -32,26 +44,66 @@ type memoryAdapter struct {
 	items     map[string]cacheEntry
 	mu        sync.RWMutex
 	log       applogger.Logger
+	metrics   appmetrics.Metrics
+	now       func() time.Time
 	stopClean chan bool
 }
 
-// NewMemoryAdapter creates a new in-memory cache adapter.
-func NewMemoryAdapter(config appcache.Config, log applogger.Logger) appcache.Cache {
+// MemoryOption customizes the memory adapter.
+type MemoryOption func(*memoryAdapter)
+
+// WithMetrics makes the adapter report its entry count as the cache_entries
+// gauge and count entries removed because they expired or were evicted to
+// respect MaxEntries as cache_evictions_total.
+func WithMetrics(metrics appmetrics.Metrics) MemoryOption {
+	return func(m *memoryAdapter) { m.metrics = metrics }
+}
+
+// WithConfig sets the cache configuration. The default is
+// appcache.DefaultConfig().
+func WithConfig(config appcache.Config) MemoryOption {
+	return func(m *memoryAdapter) { m.config = config }
+}
+
+// WithLogger sets the logger. Nil, the default, discards log output.
+func WithLogger(log applogger.Logger) MemoryOption {
+	return func(m *memoryAdapter) { m.log = applogger.OrNop(log) }
+}
+
+// WithClock replaces the clock used to expire entries, e.g. with a fake clock
+// in tests.
+func WithClock(now func() time.Time) MemoryOption {
+	return func(m *memoryAdapter) { m.now = now }
+}
+
+// NewMemory creates a new in-memory cache adapter configured by opts.
+// Without options it uses appcache.DefaultConfig() and discards log output.
+func NewMemory(opts ...MemoryOption) appcache.Cache {
 	adapter := &memoryAdapter{
-		config:    config,
+		config:    appcache.DefaultConfig(),
 		items:     make(map[string]cacheEntry),
-		log:       log,
+		log:       applogger.Nop(),
+		now:       time.Now,
 		stopClean: make(chan bool),
 	}
+	for _, opt := range opts {
+		opt(adapter)
+	}
 
 	// Start the cleanup goroutine if cleanup interval is set
-	if config.CleanupInterval > 0 {
+	if adapter.config.CleanupInterval > 0 {
 		go adapter.startCleanup()
 	}
 
 	return adapter
 }
 
+// NewMemoryAdapter provides backward compatibility with the old API.
+// Deprecated: Use NewMemory with WithConfig and WithLogger instead.
+func NewMemoryAdapter(config appcache.Config, log applogger.Logger, opts ...MemoryOption) appcache.Cache {
+	return NewMemory(append([]MemoryOption{WithConfig(config), WithLogger(log)}, opts...)...)
+}
+
 // startCleanup starts a goroutine to periodically clean up expired entries.
 func (m *memoryAdapter) startCleanup() {
 	ticker := time.NewTicker(m.config.CleanupInterval)
@@ -72,19 +124,46 @@ func (m *memoryAdapter) cleanup() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	now := m.now()
 	for key, entry := range m.items {
-		if entry.isExpired() {
-			delete(m.items, key)
+		if entry.isExpired(now) {
+			m.evictLocked(key)
 		}
 	}
+	m.recordSizeLocked()
+}
+
+// evictLocked removes an expired or evicted entry and counts the eviction.
+// The caller must hold the write lock.
+func (m *memoryAdapter) evictLocked(key string) {
+	delete(m.items, key)
+	if m.metrics != nil {
+		m.metrics.CounterInc(evictionsMetric)
+	}
+}
+
+// recordSizeLocked reports the current number of entries. The caller must
+// hold the lock.
+func (m *memoryAdapter) recordSizeLocked() {
+	if m.metrics != nil {
+		m.metrics.GaugeSet(entriesMetric, float64(len(m.items)))
+	}
 }
 
 // Get retrieves a value from the cache.
 func (m *memoryAdapter) Get(ctx context.Context, key string) (interface{}, bool) {
+	if ctx.Err() != nil {
+		return nil, false
+	}
+
 	if !m.config.Enabled {
 		return nil, false
 	}
 
+	if m.config.SlidingExpiration {
+		return m.getSliding(key)
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -93,12 +172,16 @@ func (m *memoryAdapter) Get(ctx context.Context, key string) (interface{}, bool)
 		return nil, false
 	}
 
-	if entry.isExpired() {
+	if entry.isExpired(m.now()) {
 		// Remove expired entry
 		go func() {
 			m.mu.Lock()
 			defer m.mu.Unlock()
-			delete(m.items, key)
+			// The key may have been set again in the meantime
+			if entry, found := m.items[key]; found && entry.isExpired(m.now()) {
+				m.evictLocked(key)
+				m.recordSizeLocked()
+			}
 		}()
 		return nil, false
 	}
@@ -106,8 +189,35 @@ func (m *memoryAdapter) Get(ctx context.Context, key string) (interface{}, bool)
 	return entry.value, true
 }
 
+// getSliding looks up key and restarts its TTL, so entries stay cached for as
+// long as they keep being read.
+func (m *memoryAdapter) getSliding(key string) (interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, found := m.items[key]
+	if !found {
+		return nil, false
+	}
+	if entry.isExpired(m.now()) {
+		m.evictLocked(key)
+		m.recordSizeLocked()
+		return nil, false
+	}
+
+	if entry.ttl > 0 {
+		entry.expiration = m.now().Add(entry.ttl)
+		m.items[key] = entry
+	}
+	return entry.value, true
+}
+
 // GetWithType retrieves a value from the cache and unmarshals it into the provided type.
 func (m *memoryAdapter) GetWithType(ctx context.Context, key string, value interface{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
 	if !m.config.Enabled {
 		return false
 	}
@@ -117,23 +227,25 @@ func (m *memoryAdapter) GetWithType(ctx context.Context, key string, value inter
 		return false
 	}
 
+	codec := m.codec()
+
 	// Try to assign directly
 	switch v := data.(type) {
 	case []byte:
 		// If it's a byte slice, try to unmarshal it
-		if err := json.Unmarshal(v, value); err != nil {
+		if err := codec.Unmarshal(v, value); err != nil {
 			m.log.Error(ctx, err, "failed to unmarshal cache value")
 			return false
 		}
 		return true
 	default:
 		// Try to marshal and unmarshal to convert between types
-		bytes, err := json.Marshal(v)
+		bytes, err := codec.Marshal(v)
 		if err != nil {
 			m.log.Error(ctx, err, "failed to marshal cache value")
 			return false
 		}
-		if err := json.Unmarshal(bytes, value); err != nil {
+		if err := codec.Unmarshal(bytes, value); err != nil {
 			m.log.Error(ctx, err, "failed to unmarshal cache value")
 			return false
 		}
@@ -141,8 +253,20 @@ func (m *memoryAdapter) GetWithType(ctx context.Context, key string, value inter
 	}
 }
 
+// codec returns the configured codec, defaulting to JSON.
+func (m *memoryAdapter) codec() appcache.Codec {
+	if m.config.Codec != nil {
+		return m.config.Codec
+	}
+	return NewJSONCodec()
+}
+
 // Set stores a value in the cache with the given key and TTL.
 func (m *memoryAdapter) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !m.config.Enabled {
 		return nil
 	}
@@ -150,30 +274,91 @@ func (m *memoryAdapter) Set(ctx context.Context, key string, value interface{},
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check if we've reached the maximum number of entries
-	if m.config.MaxEntries > 0 && len(m.items) >= m.config.MaxEntries {
+	m.setLocked(key, value, ttl)
+	return nil
+}
+
+// SetDefault stores a value in the cache using the configured default TTL.
+func (m *memoryAdapter) SetDefault(ctx context.Context, key string, value interface{}) error {
+	return m.Set(ctx, key, value, m.config.DefaultTTL)
+}
+
+// SetNX stores a value only if the key does not exist or has expired.
+func (m *memoryAdapter) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if !m.config.Enabled {
+		return false, errCacheDisabled
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry, found := m.items[key]; found && !entry.isExpired(m.now()) {
+		return false, nil
+	}
+
+	m.setLocked(key, value, ttl)
+	return true, nil
+}
+
+// CompareAndSwap replaces the value of key with newValue if the current value deeply equals old.
+func (m *memoryAdapter) CompareAndSwap(ctx context.Context, key string, old, newValue interface{}, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if !m.config.Enabled {
+		return false, errCacheDisabled
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, found := m.items[key]
+	if !found || entry.isExpired(m.now()) || !reflect.DeepEqual(entry.value, old) {
+		return false, nil
+	}
+
+	m.setLocked(key, newValue, ttl)
+	return true, nil
+}
+
+// setLocked stores a value, evicting an entry if the cache is full.
+// The caller must hold the write lock.
+func (m *memoryAdapter) setLocked(key string, value interface{}, ttl time.Duration) {
+	// Check if we've reached the maximum number of entries; overwriting an
+	// existing key does not grow the cache
+	_, exists := m.items[key]
+	if !exists && m.config.MaxEntries > 0 && len(m.items) >= m.config.MaxEntries {
 		// Remove a random entry
 		for k := range m.items {
-			delete(m.items, k)
+			m.evictLocked(k)
 			break
 		}
 	}
 
 	var expiration time.Time
 	if ttl > 0 {
-		expiration = time.Now().Add(ttl)
+		expiration = m.now().Add(ttl)
 	}
 
 	m.items[key] = cacheEntry{
 		value:      value,
 		expiration: expiration,
+		ttl:        ttl,
 	}
-
-	return nil
+	m.recordSizeLocked()
 }
 
 // Delete removes a value from the cache.
 func (m *memoryAdapter) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !m.config.Enabled {
 		return nil
 	}
@@ -182,11 +367,16 @@ func (m *memoryAdapter) Delete(ctx context.Context, key string) error {
 	defer m.mu.Unlock()
 
 	delete(m.items, key)
+	m.recordSizeLocked()
 	return nil
 }
 
 // Clear removes all values from the cache.
 func (m *memoryAdapter) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !m.config.Enabled {
 		return nil
 	}
@@ -195,11 +385,16 @@ func (m *memoryAdapter) Clear(ctx context.Context) error {
 	defer m.mu.Unlock()
 
 	m.items = make(map[string]cacheEntry)
+	m.recordSizeLocked()
 	return nil
 }
 
 // Has checks if a key exists in the cache.
 func (m *memoryAdapter) Has(ctx context.Context, key string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
 	if !m.config.Enabled {
 		return false
 	}
@@ -210,27 +405,90 @@ func (m *memoryAdapter) Has(ctx context.Context, key string) bool {
 
 // GetMulti retrieves multiple values from the cache.
 func (m *memoryAdapter) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, []string) {
+	if ctx.Err() != nil {
+		return nil, keys
+	}
+
 	if !m.config.Enabled {
 		return nil, keys
 	}
 
-	result := make(map[string]interface{})
+	if m.config.SlidingExpiration {
+		return m.getMultiSliding(keys)
+	}
+
+	result := make(map[string]interface{}, len(keys))
+	var missing, expired []string
+
+	// Read every key under one lock so the result is a consistent snapshot
+	m.mu.RLock()
+	for _, key := range keys {
+		entry, found := m.items[key]
+		switch {
+		case !found:
+			missing = append(missing, key)
+		case entry.isExpired(m.now()):
+			missing = append(missing, key)
+			expired = append(expired, key)
+		default:
+			result[key] = entry.value
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(expired) > 0 {
+		m.mu.Lock()
+		for _, key := range expired {
+			// The key may have been set again since it was read
+			if entry, found := m.items[key]; found && entry.isExpired(m.now()) {
+				m.evictLocked(key)
+			}
+		}
+		m.recordSizeLocked()
+		m.mu.Unlock()
+	}
+
+	return result, missing
+}
+
+// getMultiSliding is GetMulti with sliding expiration: it takes the write
+// lock once, drops expired entries and restarts the TTL of every hit.
+func (m *memoryAdapter) getMultiSliding(keys []string) (map[string]interface{}, []string) {
+	result := make(map[string]interface{}, len(keys))
 	var missing []string
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
 	for _, key := range keys {
-		value, found := m.Get(ctx, key)
-		if found {
-			result[key] = value
-		} else {
+		entry, found := m.items[key]
+		if !found {
 			missing = append(missing, key)
+			continue
 		}
+		if entry.isExpired(now) {
+			m.evictLocked(key)
+			missing = append(missing, key)
+			continue
+		}
+		if entry.ttl > 0 {
+			entry.expiration = now.Add(entry.ttl)
+			m.items[key] = entry
+		}
+		result[key] = entry.value
 	}
+	m.recordSizeLocked()
 
 	return result, missing
 }
 
 // SetMulti stores multiple values in the cache with the given TTL.
 func (m *memoryAdapter) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !m.config.Enabled {
 		return nil
 	}
@@ -244,8 +502,32 @@ func (m *memoryAdapter) SetMulti(ctx context.Context, items map[string]interface
 	return nil
 }
 
+// SetMultiTTL stores multiple values in the cache, each with its own TTL.
+func (m *memoryAdapter) SetMultiTTL(ctx context.Context, items map[string]appcache.CacheItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !m.config.Enabled {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, item := range items {
+		m.setLocked(key, item.Value, item.TTL)
+	}
+
+	return nil
+}
+
 // DeleteMulti removes multiple values from the cache.
 func (m *memoryAdapter) DeleteMulti(ctx context.Context, keys []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !m.config.Enabled {
 		return nil
 	}
@@ -261,32 +543,44 @@ func (m *memoryAdapter) DeleteMulti(ctx context.Context, keys []string) error {
 
 // Increment increments a counter by the given amount.
 func (m *memoryAdapter) Increment(ctx context.Context, key string, amount int64) (int64, error) {
+	return m.IncrementWithTTL(ctx, key, amount, 0)
+}
+
+// IncrementWithTTL increments a counter by the given amount. A new counter
+// expires after ttl; an existing one keeps its expiration.
+func (m *memoryAdapter) IncrementWithTTL(ctx context.Context, key string, amount int64, ttl time.Duration) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	if !m.config.Enabled {
-		return 0, errors.New("cache is disabled")
+		return 0, errCacheDisabled
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	entry, found := m.items[key]
-	var value int64
+	if !found || entry.isExpired(m.now()) {
+		m.setLocked(key, amount, ttl)
+		return amount, nil
+	}
 
-	if found && !entry.isExpired() {
-		// Try to convert the existing value to int64
-		switch v := entry.value.(type) {
-		case int:
-			value = int64(v)
-		case int32:
-			value = int64(v)
-		case int64:
-			value = v
-		case float32:
-			value = int64(v)
-		case float64:
-			value = int64(v)
-		default:
-			return 0, errors.New("value is not a number")
-		}
+	// Try to convert the existing value to int64
+	var value int64
+	switch v := entry.value.(type) {
+	case int:
+		value = int64(v)
+	case int32:
+		value = int64(v)
+	case int64:
+		value = v
+	case float32:
+		value = int64(v)
+	case float64:
+		value = int64(v)
+	default:
+		return 0, errors.New("value is not a number")
 	}
 
 	value += amount
@@ -295,7 +589,9 @@ func (m *memoryAdapter) Increment(ctx context.Context, key string, amount int64)
 	m.items[key] = cacheEntry{
 		value:      value,
 		expiration: entry.expiration,
+		ttl:        entry.ttl,
 	}
+	m.recordSizeLocked()
 
 	return value, nil
 }
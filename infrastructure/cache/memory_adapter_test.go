@@ -3,12 +3,16 @@ package cache_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	appcache "github.com/next-trace/scg-service-api/application/cache"
 	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
 	infraLogger "github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/next-trace/scg-service-api/testsupport"
 )
 
 func TestMemoryAdapter_BasicOpsAndTTL(t *testing.T) {
@@ -76,3 +80,401 @@ func TestMemoryAdapter_BasicOpsAndTTL(t *testing.T) {
 		t.Fatalf("clear error: %v", err)
 	}
 }
+
+func TestMemoryAdapter_SetNX(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "debug")
+
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, log)
+	t.Cleanup(func() { _ = c.Close() })
+
+	ok, err := c.SetNX(ctx, "lock", "owner-1", 20*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("expected first SetNX to succeed: ok=%v err=%v", ok, err)
+	}
+
+	ok, err = c.SetNX(ctx, "lock", "owner-2", 20*time.Millisecond)
+	if err != nil || ok {
+		t.Fatalf("expected second SetNX to fail: ok=%v err=%v", ok, err)
+	}
+	if v, _ := c.Get(ctx, "lock"); v != "owner-1" {
+		t.Fatalf("expected value to be unchanged, got %v", v)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	ok, err = c.SetNX(ctx, "lock", "owner-2", 20*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("expected SetNX after expiry to succeed: ok=%v err=%v", ok, err)
+	}
+	if v, _ := c.Get(ctx, "lock"); v != "owner-2" {
+		t.Fatalf("expected new owner, got %v", v)
+	}
+}
+
+func TestMemoryAdapter_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "debug")
+
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, log)
+	t.Cleanup(func() { _ = c.Close() })
+
+	if ok, err := c.CompareAndSwap(ctx, "missing", nil, "v", 0); err != nil || ok {
+		t.Fatalf("expected CAS on missing key to fail: ok=%v err=%v", ok, err)
+	}
+
+	if err := c.Set(ctx, "version", 1, 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	if ok, err := c.CompareAndSwap(ctx, "version", 2, 3, 0); err != nil || ok {
+		t.Fatalf("expected CAS with stale value to fail: ok=%v err=%v", ok, err)
+	}
+	if v, _ := c.Get(ctx, "version"); v != 1 {
+		t.Fatalf("expected value to be unchanged, got %v", v)
+	}
+
+	if ok, err := c.CompareAndSwap(ctx, "version", 1, 2, 0); err != nil || !ok {
+		t.Fatalf("expected CAS with current value to succeed: ok=%v err=%v", ok, err)
+	}
+	if v, _ := c.Get(ctx, "version"); v != 2 {
+		t.Fatalf("expected swapped value, got %v", v)
+	}
+}
+
+func TestMemoryAdapter_CancelledContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "debug")
+
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, log)
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.Set(context.Background(), "k", "v", 0); err != nil {
+		t.Fatalf("set error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.Set(ctx, "k", "other", 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from Set, got %v", err)
+	}
+	if _, ok := c.Get(ctx, "k"); ok {
+		t.Fatalf("expected Get to miss with a cancelled context")
+	}
+	if _, err := c.Increment(ctx, "n", 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled from Increment, got %v", err)
+	}
+
+	// The cancelled Set must not have changed the stored value.
+	if v, _ := c.Get(context.Background(), "k"); v != "v" {
+		t.Fatalf("expected value to be unchanged, got %v", v)
+	}
+}
+
+func TestMemoryAdapter_SetDefault(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "debug")
+
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	cfg.DefaultTTL = 20 * time.Millisecond
+	c := cacheimpl.NewMemoryAdapter(cfg, log)
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := c.SetDefault(ctx, "default", "v"); err != nil {
+		t.Fatalf("set default error: %v", err)
+	}
+	if err := c.Set(ctx, "forever", "v", 0); err != nil {
+		t.Fatalf("set error: %v", err)
+	}
+	if !c.Has(ctx, "default") {
+		t.Fatalf("expected key set with default TTL to exist")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if c.Has(ctx, "default") {
+		t.Fatalf("expected key set with default TTL to expire")
+	}
+	if !c.Has(ctx, "forever") {
+		t.Fatalf("expected Set with zero TTL to never expire")
+	}
+}
+
+func TestMemoryAdapter_SetMultiTTL(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	err := c.SetMultiTTL(ctx, map[string]appcache.CacheItem{
+		"short":   {Value: "a", TTL: 20 * time.Millisecond},
+		"long":    {Value: "b", TTL: time.Minute},
+		"forever": {Value: "c"},
+	})
+	if err != nil {
+		t.Fatalf("set multi ttl error: %v", err)
+	}
+	if found, missing := c.GetMulti(ctx, []string{"short", "long", "forever"}); len(found) != 3 || len(missing) != 0 {
+		t.Fatalf("expected all keys to be stored, found=%v missing=%v", found, missing)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if c.Has(ctx, "short") {
+		t.Fatalf("expected the short TTL key to expire")
+	}
+	if !c.Has(ctx, "long") || !c.Has(ctx, "forever") {
+		t.Fatalf("expected the long TTL and zero TTL keys to remain")
+	}
+}
+
+func TestMemoryAdapter_IncrementWithTTL(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	if v, err := c.IncrementWithTTL(ctx, "window", 1, 20*time.Millisecond); err != nil || v != 1 {
+		t.Fatalf("increment with ttl: v=%d err=%v", v, err)
+	}
+	// Incrementing an existing counter neither extends nor clears its TTL
+	if v, err := c.IncrementWithTTL(ctx, "window", 2, time.Minute); err != nil || v != 3 {
+		t.Fatalf("increment existing counter: v=%d err=%v", v, err)
+	}
+	if v, err := c.Increment(ctx, "total", 1); err != nil || v != 1 {
+		t.Fatalf("increment: v=%d err=%v", v, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if c.Has(ctx, "window") {
+		t.Fatalf("expected the counter to expire after its TTL")
+	}
+	if !c.Has(ctx, "total") {
+		t.Fatalf("expected a counter created by Increment not to expire")
+	}
+	if v, err := c.IncrementWithTTL(ctx, "window", 1, time.Minute); err != nil || v != 1 {
+		t.Fatalf("expected an expired counter to restart, v=%d err=%v", v, err)
+	}
+}
+
+func TestMemoryAdapter_NilLogger(t *testing.T) {
+	ctx := context.Background()
+	c := cacheimpl.NewMemoryAdapter(appcache.DefaultConfig(), nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	// Converting an unmarshalable value logs an error.
+	if err := c.Set(ctx, "k", []byte("not json"), 0); err != nil {
+		t.Fatalf("set error: %v", err)
+	}
+	var out map[string]string
+	if c.GetWithType(ctx, "k", &out) {
+		t.Fatalf("expected conversion to fail")
+	}
+}
+
+func TestMemoryAdapter_SlidingExpiration(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	cfg.SlidingExpiration = true
+
+	c := cacheimpl.NewMemoryAdapter(cfg, nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	ttl := 60 * time.Millisecond
+	if err := c.Set(ctx, "session", "active", ttl); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := c.Set(ctx, "idle", "untouched", ttl); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	// Keep reading "session" well past its original expiration.
+	deadline := time.Now().Add(3 * ttl)
+	for time.Now().Before(deadline) {
+		if _, ok := c.Get(ctx, "session"); !ok {
+			t.Fatalf("expected repeatedly read key to stay cached")
+		}
+		time.Sleep(ttl / 4)
+	}
+
+	var v string
+	if !c.GetWithType(ctx, "session", &v) || v != "active" {
+		t.Fatalf("expected session to survive, got %q", v)
+	}
+	if _, ok := c.Get(ctx, "idle"); ok {
+		t.Fatalf("expected untouched key to expire")
+	}
+
+	// Once reads stop, the entry expires after its TTL.
+	time.Sleep(2 * ttl)
+	if _, ok := c.Get(ctx, "session"); ok {
+		t.Fatalf("expected session to expire once no longer read")
+	}
+}
+
+func TestMemoryAdapter_GetMultiConsistentSnapshot(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("k%03d", i)
+		_ = c.Set(ctx, keys[i], 0, 0)
+	}
+
+	// The writer bumps every key to the next generation in key order, so a
+	// consistent snapshot never shows a later key ahead of an earlier one.
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for gen := 1; ; gen++ {
+			for _, key := range keys {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_ = c.Set(ctx, key, gen, 0)
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		values, missing := c.GetMulti(ctx, keys)
+		if len(missing) != 0 {
+			t.Fatalf("unexpected missing keys: %v", missing)
+		}
+		for j := 1; j < len(keys); j++ {
+			if values[keys[j]].(int) > values[keys[j-1]].(int) {
+				t.Fatalf("inconsistent snapshot: %s=%v ahead of %s=%v",
+					keys[j], values[keys[j]], keys[j-1], values[keys[j-1]])
+			}
+		}
+	}
+	close(stop)
+	<-done
+}
+
+func BenchmarkMemoryAdapter_GetMulti(b *testing.B) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, nil)
+	b.Cleanup(func() { _ = c.Close() })
+
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		_ = c.Set(ctx, keys[i], i, time.Hour)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.GetMulti(ctx, keys)
+	}
+}
+
+func TestMemoryAdapter_Metrics(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	cfg.MaxEntries = 2
+	metrics := testsupport.NewMetrics()
+	c := cacheimpl.NewMemoryAdapter(cfg, nil, cacheimpl.WithMetrics(metrics))
+	t.Cleanup(func() { _ = c.Close() })
+
+	_ = c.Set(ctx, "a", 1, 0)
+	_ = c.Set(ctx, "b", 2, 0)
+	if got := metrics.Gauge("cache_entries", nil); got != 2 {
+		t.Fatalf("expected cache_entries 2, got %v", got)
+	}
+
+	// Overwriting a key is not an eviction
+	_ = c.Set(ctx, "b", 3, 0)
+	metrics.AssertCounter(t, "cache_evictions_total", nil, 0)
+
+	_ = c.Set(ctx, "c", 4, 0)
+	metrics.AssertCounter(t, "cache_evictions_total", nil, 1)
+	if got := metrics.Gauge("cache_entries", nil); got != 2 {
+		t.Fatalf("expected cache_entries to stay at MaxEntries, got %v", got)
+	}
+
+	_ = c.Delete(ctx, "c")
+	if got := metrics.Gauge("cache_entries", nil); got != 1 {
+		t.Fatalf("expected cache_entries 1 after Delete, got %v", got)
+	}
+
+	_ = c.Set(ctx, "short", 5, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	c.GetMulti(ctx, []string{"short"})
+	metrics.AssertCounter(t, "cache_evictions_total", nil, 2)
+	if got := metrics.Gauge("cache_entries", nil); got != 1 {
+		t.Fatalf("expected the expired entry to be removed, got %v", got)
+	}
+}
+
+func TestNewMemory_DefaultsMatchLegacy(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+
+	for name, c := range map[string]appcache.Cache{
+		"options": cacheimpl.NewMemory(),
+		"legacy":  cacheimpl.NewMemoryAdapter(cfg, nil),
+	} {
+		t.Cleanup(func() { _ = c.Close() })
+
+		if err := c.SetDefault(ctx, "k", 1); err != nil {
+			t.Fatalf("%s: SetDefault: %v", name, err)
+		}
+		data, err := c.(appcache.Persister).Dump(ctx)
+		if err != nil {
+			t.Fatalf("%s: Dump: %v", name, err)
+		}
+		var entries map[string]struct {
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		if err := json.Unmarshal(data, &entries); err != nil {
+			t.Fatalf("%s: decode dump: %v", name, err)
+		}
+		if ttl := time.Until(entries["k"].ExpiresAt); ttl <= cfg.DefaultTTL-time.Second || ttl > cfg.DefaultTTL {
+			t.Fatalf("%s: expected the default TTL %v, got %v", name, cfg.DefaultTTL, ttl)
+		}
+	}
+}
+
+func TestNewMemory_Options(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.MaxEntries = 1
+	metrics := testsupport.NewMetrics()
+
+	c := cacheimpl.NewMemory(cacheimpl.WithConfig(cfg), cacheimpl.WithLogger(testsupport.NewLogger()), cacheimpl.WithMetrics(metrics))
+	t.Cleanup(func() { _ = c.Close() })
+
+	_ = c.Set(ctx, "a", 1, 0)
+	_ = c.Set(ctx, "b", 2, 0)
+	if c.Has(ctx, "a") && c.Has(ctx, "b") {
+		t.Fatalf("expected WithConfig's MaxEntries to be enforced")
+	}
+	metrics.AssertCounter(t, "cache_evictions_total", nil, 1)
+}
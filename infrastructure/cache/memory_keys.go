@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+)
+
+// Ensure memoryAdapter implements the appcache.KeyLister interface.
+var _ appcache.KeyLister = (*memoryAdapter)(nil)
+
+// Keys returns the sorted non-expired keys matching pattern. It scans every
+// entry under the read lock, so it is O(n) in the number of entries.
+func (m *memoryAdapter) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.config.Enabled {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.now()
+	keys := make([]string, 0)
+	for key, entry := range m.items {
+		if !entry.isExpired(now) && re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// compileGlob translates a Redis-style glob pattern into an anchored regexp.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		case '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("invalid key pattern %q: trailing escape", pattern)
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '^' || runes[end] == '!') {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("invalid key pattern %q: unterminated character class", pattern)
+			}
+			class := runes[i+1 : end]
+			b.WriteByte('[')
+			for j, r := range class {
+				switch {
+				case j == 0 && (r == '^' || r == '!'):
+					b.WriteByte('^')
+				case r == '-':
+					b.WriteByte('-')
+				default:
+					b.WriteString(regexp.QuoteMeta(string(r)))
+				}
+			}
+			b.WriteByte(']')
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`$`)
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
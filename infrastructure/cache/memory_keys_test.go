@@ -0,0 +1,62 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+)
+
+func TestMemoryAdapter_Keys(t *testing.T) {
+	ctx := context.Background()
+	c := cacheimpl.NewMemoryAdapter(appcache.DefaultConfig(), nil)
+	t.Cleanup(func() { _ = c.Close() })
+
+	lister, ok := c.(appcache.KeyLister)
+	if !ok {
+		t.Fatalf("memory adapter should implement KeyLister")
+	}
+
+	for _, key := range []string{"user:1", "user:2", "user:10", "item:1", "users", "user:a/b"} {
+		if err := c.Set(ctx, key, true, 0); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+	if err := c.Set(ctx, "user:expired", true, time.Nanosecond); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"user:*", []string{"user:1", "user:10", "user:2", "user:a/b"}},
+		{"user:?", []string{"user:1", "user:2"}},
+		{"user:[12]", []string{"user:1", "user:2"}},
+		{"user:[^1]", []string{"user:2"}},
+		{"*:1", []string{"item:1", "user:1"}},
+		{"users", []string{"users"}},
+		{"nothing*", []string{}},
+	}
+	for _, tt := range tests {
+		got, err := lister.Keys(ctx, tt.pattern)
+		if err != nil {
+			t.Fatalf("Keys(%q): %v", tt.pattern, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("Keys(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("Keys(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		}
+	}
+
+	if _, err := lister.Keys(ctx, "user:[1"); err == nil {
+		t.Fatalf("expected an error for an unterminated class")
+	}
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+)
+
+// Ensure memoryAdapter implements the appcache.Persister interface.
+var _ appcache.Persister = (*memoryAdapter)(nil)
+
+// dumpEntry is the serialized form of a cache entry.
+type dumpEntry struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt time.Time       `json:"expires_at,omitzero"` // Zero means no expiry
+}
+
+// Dump serializes all non-expired entries with their expiry time as JSON.
+// Values are restored by Load in their JSON-decoded form, so typed reads
+// should use GetWithType.
+func (m *memoryAdapter) Dump(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.now()
+	entries := make(map[string]dumpEntry, len(m.items))
+	for key, entry := range m.items {
+		if entry.isExpired(now) {
+			continue
+		}
+
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cache entry %q: %w", key, err)
+		}
+
+		entries[key] = dumpEntry{Value: value, ExpiresAt: entry.expiration}
+	}
+
+	return json.Marshal(entries)
+}
+
+// Load restores entries produced by Dump. Each entry keeps the expiry time it
+// had when dumped, so the time between Dump and Load counts against its TTL;
+// entries that expired in the meantime are skipped.
+func (m *memoryAdapter) Load(ctx context.Context, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var entries map[string]dumpEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal cache dump: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	loaded := 0
+	for key, entry := range entries {
+		var ttl time.Duration
+		if !entry.ExpiresAt.IsZero() {
+			ttl = entry.ExpiresAt.Sub(now)
+			if ttl <= 0 {
+				continue
+			}
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(entry.Value, &value); err != nil {
+			return fmt.Errorf("failed to unmarshal cache entry %q: %w", key, err)
+		}
+		m.setLocked(key, value, ttl)
+		loaded++
+	}
+
+	m.log.DebugKV(ctx, "cache entries loaded", map[string]interface{}{
+		"count": loaded,
+	})
+
+	return nil
+}
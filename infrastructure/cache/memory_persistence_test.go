@@ -0,0 +1,108 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+	infraLogger "github.com/next-trace/scg-service-api/infrastructure/logger"
+)
+
+type session struct {
+	UserID string `json:"user_id"`
+	Roles  []string
+}
+
+func TestMemoryAdapter_DumpLoad(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "debug")
+
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+
+	src := cacheimpl.NewMemoryAdapter(cfg, log)
+	_ = src.Set(ctx, "forever", "v", 0)
+	_ = src.Set(ctx, "session", session{UserID: "u1", Roles: []string{"admin"}}, 50*time.Millisecond)
+	_ = src.Set(ctx, "expired", "gone", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	data, err := src.(appcache.Persister).Dump(ctx)
+	if err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	_ = src.Close()
+
+	dst := cacheimpl.NewMemoryAdapter(cfg, log)
+	t.Cleanup(func() { _ = dst.Close() })
+	if err := dst.(appcache.Persister).Load(ctx, data); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if v, ok := dst.Get(ctx, "forever"); !ok || v != "v" {
+		t.Fatalf("expected forever entry, got %v ok=%v", v, ok)
+	}
+	var s session
+	if !dst.GetWithType(ctx, "session", &s) || s.UserID != "u1" || len(s.Roles) != 1 {
+		t.Fatalf("expected session entry, got %+v", s)
+	}
+	if dst.Has(ctx, "expired") {
+		t.Fatalf("expected expired entry to be skipped")
+	}
+
+	// The session keeps its remaining TTL across the dump.
+	time.Sleep(60 * time.Millisecond)
+	if dst.Has(ctx, "session") {
+		t.Fatalf("expected session to expire with its original TTL")
+	}
+	if !dst.Has(ctx, "forever") {
+		t.Fatalf("expected entry without TTL to persist")
+	}
+}
+
+func TestMemoryAdapter_LoadCountsDowntimeAgainstTTL(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	src := cacheimpl.NewMemory(cacheimpl.WithConfig(cfg), cacheimpl.WithClock(clock))
+	_ = src.Set(ctx, "short", "s", time.Minute)
+	_ = src.Set(ctx, "long", "l", time.Hour)
+	_ = src.Set(ctx, "forever", "f", 0)
+
+	data, err := src.(appcache.Persister).Dump(ctx)
+	if err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	_ = src.Close()
+
+	// The process is down for ten minutes before the dump is loaded.
+	now = now.Add(10 * time.Minute)
+	dst := cacheimpl.NewMemory(cacheimpl.WithConfig(cfg), cacheimpl.WithClock(clock))
+	t.Cleanup(func() { _ = dst.Close() })
+	if err := dst.(appcache.Persister).Load(ctx, data); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if dst.Has(ctx, "short") {
+		t.Fatalf("expected the entry that expired while down to be skipped")
+	}
+	if !dst.Has(ctx, "long") || !dst.Has(ctx, "forever") {
+		t.Fatalf("expected the unexpired entries to be loaded")
+	}
+
+	// "long" expires an hour after it was set, not an hour after Load.
+	now = now.Add(50*time.Minute + time.Second)
+	if dst.Has(ctx, "long") {
+		t.Fatalf("expected the entry to expire at its original time")
+	}
+	if !dst.Has(ctx, "forever") {
+		t.Fatalf("expected entry without TTL to persist")
+	}
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// retryBaseBackoff is the wait before the first retry; it doubles on each
+// further attempt up to retryMaxBackoff.
+const (
+	retryBaseBackoff = 10 * time.Millisecond
+	retryMaxBackoff  = 200 * time.Millisecond
+)
+
+// retryingCache retries idempotent writes of a remote cache on connection
+// errors. Operations it does not override are passed through unchanged.
+type retryingCache struct {
+	appcache.Cache
+	maxRetries int
+	log        applogger.Logger
+}
+
+// NewRetryingCache wraps a remote cache store so that Set, SetDefault,
+// SetMulti, Delete, DeleteMulti and Clear are retried up to
+// config.Redis.MaxRetries times, with exponential backoff, when they fail
+// with a connection error. Logical errors are returned at once.
+//
+// Get and the other lookups cannot report errors through the Cache port, so
+// they are not retried; nor are SetNX, CompareAndSwap, Increment,
+// IncrementWithTTL and Decrement, which are not safe to repeat once the
+// first attempt may have been applied. Optional capabilities such as
+// KeyLister are not forwarded.
+func NewRetryingCache(config appcache.Config, next appcache.Cache, log applogger.Logger) appcache.Cache {
+	log = applogger.OrNop(log)
+	if config.Redis.MaxRetries <= 0 {
+		return next
+	}
+	return &retryingCache{
+		Cache:      next,
+		maxRetries: config.Redis.MaxRetries,
+		log:        log,
+	}
+}
+
+// Set retries the wrapped Set on connection errors.
+func (c *retryingCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.retry(ctx, "set", func() error { return c.Cache.Set(ctx, key, value, ttl) })
+}
+
+// SetDefault retries the wrapped SetDefault on connection errors.
+func (c *retryingCache) SetDefault(ctx context.Context, key string, value interface{}) error {
+	return c.retry(ctx, "set", func() error { return c.Cache.SetDefault(ctx, key, value) })
+}
+
+// SetMulti retries the wrapped SetMulti on connection errors.
+func (c *retryingCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	return c.retry(ctx, "set_multi", func() error { return c.Cache.SetMulti(ctx, items, ttl) })
+}
+
+// SetMultiTTL retries the wrapped SetMultiTTL on connection errors.
+func (c *retryingCache) SetMultiTTL(ctx context.Context, items map[string]appcache.CacheItem) error {
+	return c.retry(ctx, "set_multi", func() error { return c.Cache.SetMultiTTL(ctx, items) })
+}
+
+// Delete retries the wrapped Delete on connection errors.
+func (c *retryingCache) Delete(ctx context.Context, key string) error {
+	return c.retry(ctx, "delete", func() error { return c.Cache.Delete(ctx, key) })
+}
+
+// DeleteMulti retries the wrapped DeleteMulti on connection errors.
+func (c *retryingCache) DeleteMulti(ctx context.Context, keys []string) error {
+	return c.retry(ctx, "delete_multi", func() error { return c.Cache.DeleteMulti(ctx, keys) })
+}
+
+// Clear retries the wrapped Clear on connection errors.
+func (c *retryingCache) Clear(ctx context.Context) error {
+	return c.retry(ctx, "clear", func() error { return c.Cache.Clear(ctx) })
+}
+
+// retry runs op until it succeeds, fails with a non-connection error, the
+// retries are used up or ctx is done.
+func (c *retryingCache) retry(ctx context.Context, operation string, op func() error) error {
+	backoff := retryBaseBackoff
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= c.maxRetries || !isConnectionError(err) {
+			return err
+		}
+
+		c.log.WarnKV(ctx, "retrying cache operation", map[string]interface{}{
+			"operation": operation,
+			"attempt":   attempt + 1,
+			"error":     err.Error(),
+		})
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, retryMaxBackoff)
+	}
+}
+
+// isConnectionError reports whether err is a transport failure worth
+// retrying, as opposed to an error returned by the store itself.
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,105 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+// flakyCache fails the first failures Set and Delete calls with err.
+type flakyCache struct {
+	*testsupport.Cache
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return f.Cache.Set(ctx, key, value, ttl)
+}
+
+func (f *flakyCache) Delete(ctx context.Context, key string) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return f.Cache.Delete(ctx, key)
+}
+
+func connReset() error {
+	return &net.OpError{Op: "write", Net: "tcp", Err: syscall.ECONNRESET}
+}
+
+func TestRetryingCache_SucceedsAfterTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.Redis.MaxRetries = 3
+	store := &flakyCache{Cache: testsupport.NewCache(0), failures: cfg.Redis.MaxRetries, err: connReset()}
+	c := cacheimpl.NewRetryingCache(cfg, store, nil)
+
+	if err := c.Set(ctx, "k", "v", 0); err != nil {
+		t.Fatalf("expected Set to succeed after retries, got %v", err)
+	}
+	if store.calls != cfg.Redis.MaxRetries+1 {
+		t.Fatalf("expected %d attempts, got %d", cfg.Redis.MaxRetries+1, store.calls)
+	}
+	if v, ok := c.Get(ctx, "k"); !ok || v != "v" {
+		t.Fatalf("expected value stored, got %v %v", v, ok)
+	}
+}
+
+func TestRetryingCache_GivesUpAfterMaxRetries(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	cfg.Redis.MaxRetries = 2
+	store := &flakyCache{Cache: testsupport.NewCache(0), failures: 10, err: connReset()}
+	c := cacheimpl.NewRetryingCache(cfg, store, nil)
+
+	if err := c.Delete(ctx, "k"); !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("expected connection error, got %v", err)
+	}
+	if store.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", store.calls)
+	}
+}
+
+func TestRetryingCache_LogicalErrorNotRetried(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcache.DefaultConfig()
+	logical := errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	store := &flakyCache{Cache: testsupport.NewCache(0), failures: 1, err: logical}
+	c := cacheimpl.NewRetryingCache(cfg, store, nil)
+
+	if err := c.Set(ctx, "k", "v", 0); !errors.Is(err, logical) {
+		t.Fatalf("expected logical error, got %v", err)
+	}
+	if store.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", store.calls)
+	}
+}
+
+func TestRetryingCache_StopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg := appcache.DefaultConfig()
+	store := &flakyCache{Cache: testsupport.NewCache(0), failures: 10, err: connReset()}
+	c := cacheimpl.NewRetryingCache(cfg, store, nil)
+
+	if err := c.Set(ctx, "k", "v", 0); err == nil {
+		t.Fatalf("expected error")
+	}
+	if store.calls != 1 {
+		t.Fatalf("expected no retries once ctx is done, got %d attempts", store.calls)
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+)
+
+// timeoutCache bounds every operation of the wrapped cache with a default
+// timeout when the caller's context carries no deadline of its own.
+type timeoutCache struct {
+	appcache.Cache
+	timeout time.Duration
+}
+
+// WithDefaultTimeout wraps next so that each operation runs under
+// context.WithTimeout(ctx, timeout) unless ctx already has a deadline, in
+// which case the caller's deadline is kept. A non-positive timeout returns
+// next unchanged. Optional capabilities such as KeyLister are not forwarded.
+func WithDefaultTimeout(next appcache.Cache, timeout time.Duration) appcache.Cache {
+	if timeout <= 0 {
+		return next
+	}
+	return &timeoutCache{Cache: next, timeout: timeout}
+}
+
+// bound returns ctx with the default timeout applied if it has no deadline.
+func (c *timeoutCache) bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// Get runs the wrapped Get under the default timeout.
+func (c *timeoutCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Get(ctx, key)
+}
+
+// GetWithType runs the wrapped GetWithType under the default timeout.
+func (c *timeoutCache) GetWithType(ctx context.Context, key string, value interface{}) bool {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.GetWithType(ctx, key, value)
+}
+
+// Set runs the wrapped Set under the default timeout.
+func (c *timeoutCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+// SetDefault runs the wrapped SetDefault under the default timeout.
+func (c *timeoutCache) SetDefault(ctx context.Context, key string, value interface{}) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.SetDefault(ctx, key, value)
+}
+
+// SetNX runs the wrapped SetNX under the default timeout.
+func (c *timeoutCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.SetNX(ctx, key, value, ttl)
+}
+
+// CompareAndSwap runs the wrapped CompareAndSwap under the default timeout.
+func (c *timeoutCache) CompareAndSwap(ctx context.Context, key string, old, newValue interface{}, ttl time.Duration) (bool, error) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.CompareAndSwap(ctx, key, old, newValue, ttl)
+}
+
+// Delete runs the wrapped Delete under the default timeout.
+func (c *timeoutCache) Delete(ctx context.Context, key string) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Delete(ctx, key)
+}
+
+// Clear runs the wrapped Clear under the default timeout.
+func (c *timeoutCache) Clear(ctx context.Context) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Clear(ctx)
+}
+
+// Has runs the wrapped Has under the default timeout.
+func (c *timeoutCache) Has(ctx context.Context, key string) bool {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Has(ctx, key)
+}
+
+// GetMulti runs the wrapped GetMulti under the default timeout.
+func (c *timeoutCache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, []string) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.GetMulti(ctx, keys)
+}
+
+// SetMulti runs the wrapped SetMulti under the default timeout.
+func (c *timeoutCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.SetMulti(ctx, items, ttl)
+}
+
+// SetMultiTTL runs the wrapped SetMultiTTL under the default timeout.
+func (c *timeoutCache) SetMultiTTL(ctx context.Context, items map[string]appcache.CacheItem) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.SetMultiTTL(ctx, items)
+}
+
+// DeleteMulti runs the wrapped DeleteMulti under the default timeout.
+func (c *timeoutCache) DeleteMulti(ctx context.Context, keys []string) error {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.DeleteMulti(ctx, keys)
+}
+
+// Increment runs the wrapped Increment under the default timeout.
+func (c *timeoutCache) Increment(ctx context.Context, key string, amount int64) (int64, error) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Increment(ctx, key, amount)
+}
+
+// IncrementWithTTL runs the wrapped IncrementWithTTL under the default timeout.
+func (c *timeoutCache) IncrementWithTTL(ctx context.Context, key string, amount int64, ttl time.Duration) (int64, error) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.IncrementWithTTL(ctx, key, amount, ttl)
+}
+
+// Decrement runs the wrapped Decrement under the default timeout.
+func (c *timeoutCache) Decrement(ctx context.Context, key string, amount int64) (int64, error) {
+	ctx, cancel := c.bound(ctx)
+	defer cancel()
+	return c.Cache.Decrement(ctx, key, amount)
+}
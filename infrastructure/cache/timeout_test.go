@@ -0,0 +1,52 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+// deadlineCache records the deadline of the context passed to Get.
+type deadlineCache struct {
+	*testsupport.Cache
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (d *deadlineCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	d.deadline, d.hasDeadline = ctx.Deadline()
+	return d.Cache.Get(ctx, key)
+}
+
+func TestWithDefaultTimeout_AppliesDefault(t *testing.T) {
+	store := &deadlineCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.WithDefaultTimeout(store, time.Second)
+
+	start := time.Now()
+	c.Get(context.Background(), "k")
+	end := time.Now()
+
+	if !store.hasDeadline {
+		t.Fatal("expected the default timeout to set a deadline")
+	}
+	if store.deadline.Before(start.Add(time.Second)) || store.deadline.After(end.Add(time.Second)) {
+		t.Fatalf("expected a deadline 1s after the call, got %v", store.deadline.Sub(start))
+	}
+}
+
+func TestWithDefaultTimeout_KeepsCallerDeadline(t *testing.T) {
+	store := &deadlineCache{Cache: testsupport.NewCache(0)}
+	c := cacheimpl.WithDefaultTimeout(store, time.Second)
+
+	want := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+	c.Get(ctx, "k")
+
+	if !store.hasDeadline || !store.deadline.Equal(want) {
+		t.Fatalf("expected caller deadline %v, got %v", want, store.deadline)
+	}
+}
@@ -0,0 +1,47 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	cacheimpl "github.com/next-trace/scg-service-api/infrastructure/cache"
+	infraLogger "github.com/next-trace/scg-service-api/infrastructure/logger"
+)
+
+type product struct {
+	SKU   string
+	Price int
+}
+
+func TestTypedHelpers_MemoryAdapter(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	cfg := appcache.DefaultConfig()
+	cfg.CleanupInterval = 0
+	c := cacheimpl.NewMemoryAdapter(cfg, infraLogger.NewSlogAdapter(&buf, "debug"))
+	t.Cleanup(func() { _ = c.Close() })
+
+	if err := appcache.SetTyped(ctx, c, "p1", product{SKU: "abc", Price: 42}, 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	got, ok := appcache.GetTyped[product](ctx, c, "p1")
+	if !ok || got.SKU != "abc" || got.Price != 42 {
+		t.Fatalf("unexpected typed get: %+v ok=%v", got, ok)
+	}
+
+	// A value stored in another shape is converted.
+	if err := c.Set(ctx, "p2", map[string]interface{}{"SKU": "def", "Price": 7}, 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, ok = appcache.GetTyped[product](ctx, c, "p2")
+	if !ok || got.SKU != "def" || got.Price != 7 {
+		t.Fatalf("unexpected converted get: %+v ok=%v", got, ok)
+	}
+
+	if _, ok := appcache.GetTyped[product](ctx, c, "missing"); ok {
+		t.Fatalf("expected miss for unknown key")
+	}
+}
@@ -0,0 +1,151 @@
+//go:build !gobreaker
+
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// circuitBreaker is the built-in breaker engine used when the gobreaker build
+// tag is not set. It mirrors the subset of gobreaker's behavior the adapter uses.
+type circuitBreaker struct {
+	name                string
+	maxRequests         uint32
+	interval            time.Duration
+	timeout             time.Duration
+	readyToTrip         func(counts interface{}) bool
+	onStateChange       func(name, from, to string)
+	counts              interface{}
+	state               string
+	generation          uint64
+	lastStateChangeTime time.Time
+	mutex               sync.Mutex
+}
+
+// newCircuitBreaker creates a circuit breaker in the closed state.
+func newCircuitBreaker(st settings) *circuitBreaker {
+	return &circuitBreaker{
+		name:                st.name,
+		maxRequests:         st.maxRequests,
+		interval:            st.interval,
+		timeout:             st.timeout,
+		readyToTrip:         st.readyToTrip,
+		onStateChange:       st.onStateChange,
+		counts:              &counts{},
+		state:               stateClosed,
+		lastStateChangeTime: time.Now(),
+	}
+}
+
+// Execute runs req if the breaker accepts it. Like gobreaker, an open breaker
+// rejects every request until its timeout has passed, and a half-open one
+// accepts at most maxRequests trial requests: one failure reopens it, and
+// maxRequests consecutive successes close it.
+func (cb *circuitBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
+	generation, err := cb.beforeRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := req()
+	cb.afterRequest(generation, err == nil)
+	return result, err
+}
+
+// beforeRequest admits a request and counts it, returning the generation it
+// belongs to.
+func (cb *circuitBreaker) beforeRequest() (uint64, error) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.refreshStateLocked()
+	c := cb.countsLocked()
+	switch {
+	case cb.state == stateOpen:
+		return 0, errors.New("circuit breaker is open")
+	case cb.state == stateHalfOpen && c.requests >= cb.maxRequests:
+		return 0, errors.New("too many requests")
+	}
+	c.requests++
+	return cb.generation, nil
+}
+
+// afterRequest records the outcome of a request. Outcomes from an earlier
+// generation, i.e. of requests admitted before the last state change, are
+// ignored.
+func (cb *circuitBreaker) afterRequest(generation uint64, success bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.refreshStateLocked()
+	if generation != cb.generation {
+		return
+	}
+
+	c := cb.countsLocked()
+	if !success {
+		c.totalFailures++
+		c.consecutiveFailures++
+		c.consecutiveSuccesses = 0
+
+		switch {
+		case cb.state == stateHalfOpen:
+			cb.setState(stateOpen)
+		case cb.state == stateClosed && cb.readyToTrip(c):
+			cb.setState(stateOpen)
+		}
+		return
+	}
+
+	c.totalSuccesses++
+	c.consecutiveSuccesses++
+	c.consecutiveFailures = 0
+
+	if cb.state == stateHalfOpen && c.consecutiveSuccesses >= cb.maxRequests {
+		cb.setState(stateClosed)
+	}
+}
+
+// countsLocked returns the counts of the current generation. cb.mutex must be held.
+func (cb *circuitBreaker) countsLocked() *counts {
+	c, ok := cb.counts.(*counts)
+	if !ok {
+		c = &counts{}
+		cb.counts = c
+	}
+	return c
+}
+
+// refreshStateLocked moves an open breaker to half-open once its timeout has
+// passed. cb.mutex must be held.
+func (cb *circuitBreaker) refreshStateLocked() {
+	if cb.state == stateOpen && time.Since(cb.lastStateChangeTime) > cb.timeout {
+		cb.setState(stateHalfOpen)
+	}
+}
+
+func (cb *circuitBreaker) setState(state string) {
+	if cb.state == state {
+		return
+	}
+
+	oldState := cb.state
+	cb.state = state
+	cb.generation++
+	cb.lastStateChangeTime = time.Now()
+	cb.counts = &counts{}
+
+	if cb.onStateChange != nil {
+		cb.onStateChange(cb.name, oldState, state)
+	}
+}
+
+func (cb *circuitBreaker) State() string {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.refreshStateLocked()
+	return cb.state
+}
@@ -0,0 +1,48 @@
+//go:build gobreaker
+
+package circuitbreaker
+
+import (
+	"github.com/sony/gobreaker"
+)
+
+// circuitBreaker wraps a gobreaker.CircuitBreaker when building with the gobreaker tag.
+type circuitBreaker struct {
+	cb *gobreaker.CircuitBreaker
+}
+
+// newCircuitBreaker creates a gobreaker-backed circuit breaker from the adapter settings.
+func newCircuitBreaker(st settings) *circuitBreaker {
+	return &circuitBreaker{
+		cb: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:        st.name,
+			MaxRequests: st.maxRequests,
+			Interval:    st.interval,
+			Timeout:     st.timeout,
+			ReadyToTrip: func(c gobreaker.Counts) bool {
+				return st.readyToTrip(&counts{
+					requests:             c.Requests,
+					totalSuccesses:       c.TotalSuccesses,
+					totalFailures:        c.TotalFailures,
+					consecutiveSuccesses: c.ConsecutiveSuccesses,
+					consecutiveFailures:  c.ConsecutiveFailures,
+				})
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				if st.onStateChange != nil {
+					st.onStateChange(name, from.String(), to.String())
+				}
+			},
+		}),
+	}
+}
+
+// Execute runs req if the breaker accepts the request.
+func (cb *circuitBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
+	return cb.cb.Execute(req)
+}
+
+// State returns the breaker state using the same names as the built-in engine.
+func (cb *circuitBreaker) State() string {
+	return cb.cb.State().String()
+}
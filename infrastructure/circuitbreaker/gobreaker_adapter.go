@@ -1,7 +1,9 @@
 // Package circuitbreaker provides circuit breaking functionality.
 //
-// Note: This package requires the following dependencies:
-// - github.com/sony/gobreaker
+// By default the package uses a built-in breaker engine with no external
+// dependencies. Building with the gobreaker tag (go build -tags gobreaker)
+// switches the engine to github.com/sony/gobreaker, which must then be
+// required in go.mod. The public API is identical in both configurations.
 //
 // See docs/dependencies.md for more information.
 package circuitbreaker
@@ -10,6 +12,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -30,25 +33,10 @@ func safeUint32(n int) uint32 {
 	return uint32(n)
 }
 
-// Type aliases for circuit breaker types
-// These are placeholders for the actual types from the gobreaker package
-// They will be replaced with the actual types when the dependencies are added
+// Types shared by both breaker engines. The engine itself (circuitBreaker and
+// newCircuitBreaker) is provided by breaker_builtin.go by default, or by
+// breaker_gobreaker.go when building with the gobreaker tag.
 type (
-	// circuitBreaker represents a circuit breaker
-	circuitBreaker struct {
-		name                string
-		maxRequests         uint32
-		interval            time.Duration
-		timeout             time.Duration
-		readyToTrip         func(counts interface{}) bool
-		onStateChange       func(name, from, to string)
-		counts              interface{}
-		state               string
-		generation          uint64
-		lastStateChangeTime time.Time
-		mutex               sync.Mutex
-	}
-
 	// counts represents statistics for the circuit breaker
 	counts struct {
 		requests             uint32
@@ -69,96 +57,13 @@ type (
 	}
 )
 
-// Mock constants for circuit breaker states
+// Circuit breaker states as reported by the engine
 const (
 	stateOpen     = "open"
 	stateClosed   = "closed"
 	stateHalfOpen = "half-open"
 )
 
-// Mock functions for the circuitBreaker type
-func newCircuitBreaker(st settings) *circuitBreaker {
-	return &circuitBreaker{
-		name:                st.name,
-		maxRequests:         st.maxRequests,
-		interval:            st.interval,
-		timeout:             st.timeout,
-		readyToTrip:         st.readyToTrip,
-		onStateChange:       st.onStateChange,
-		counts:              &counts{},
-		state:               stateClosed,
-		lastStateChangeTime: time.Now(),
-	}
-}
-
-func (cb *circuitBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
-	cb.mutex.Lock()
-	state := cb.state
-	cb.mutex.Unlock()
-
-	if state == stateOpen {
-		return nil, errors.New("circuit breaker is open")
-	}
-
-	result, err := req()
-
-	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
-
-	c, ok := cb.counts.(*counts)
-	if !ok {
-		c = &counts{}
-		cb.counts = c
-	}
-	c.requests++
-
-	if err != nil {
-		c.totalFailures++
-		c.consecutiveFailures++
-		c.consecutiveSuccesses = 0
-
-		if cb.state == stateClosed && cb.readyToTrip(c) {
-			cb.setState(stateOpen)
-		}
-	} else {
-		c.totalSuccesses++
-		c.consecutiveSuccesses++
-		c.consecutiveFailures = 0
-
-		if cb.state == stateHalfOpen && c.consecutiveSuccesses >= cb.maxRequests {
-			cb.setState(stateClosed)
-		}
-	}
-
-	return result, err
-}
-
-func (cb *circuitBreaker) setState(state string) {
-	if cb.state == state {
-		return
-	}
-
-	oldState := cb.state
-	cb.state = state
-	cb.lastStateChangeTime = time.Now()
-	cb.counts = &counts{}
-
-	if cb.onStateChange != nil {
-		cb.onStateChange(cb.name, oldState, state)
-	}
-}
-
-func (cb *circuitBreaker) State() string {
-	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
-
-	if cb.state == stateOpen && time.Since(cb.lastStateChangeTime) > cb.timeout {
-		cb.setState(stateHalfOpen)
-	}
-
-	return cb.state
-}
-
 // gobreakerAdapter implements the circuitbreaker.CircuitBreaker interface using the gobreaker package.
 type gobreakerAdapter struct {
 	config   appcircuitbreaker.Config
@@ -169,6 +74,7 @@ type gobreakerAdapter struct {
 
 // NewGoBreakerAdapter creates a new circuit breaker adapter using the gobreaker package.
 func NewGoBreakerAdapter(config appcircuitbreaker.Config, log applogger.Logger) appcircuitbreaker.CircuitBreaker {
+	log = applogger.OrNop(log)
 	return &gobreakerAdapter{
 		config:   config,
 		breakers: make(map[string]*circuitBreaker),
@@ -256,14 +162,37 @@ func (g *gobreakerAdapter) Execute(ctx context.Context, name string, fn func(ctx
 }
 
 // ExecuteWithFallback executes the given function with circuit breaking and a fallback.
+// If the primary failed on a deadline, the fallback runs with a fresh context
+// bounded by FallbackTimeout rather than the expired one.
 func (g *gobreakerAdapter) ExecuteWithFallback(ctx context.Context, name string, fn func(ctx context.Context) (interface{}, error), fallback func(ctx context.Context, err error) (interface{}, error)) (interface{}, error) {
 	result, err := g.Execute(ctx, name, fn)
 	if err != nil && fallback != nil {
-		return fallback(ctx, err)
+		fallbackCtx, cancel := g.fallbackContext(ctx, err)
+		defer cancel()
+		return fallback(fallbackCtx, err)
 	}
 	return result, err
 }
 
+// fallbackContext returns the context for a fallback. The caller's context is
+// reused unless the primary hit a deadline, in which case a context detached
+// from ctx's cancellation, but keeping its values, is bounded by FallbackTimeout
+// (or Timeout when that is zero).
+func (g *gobreakerAdapter) fallbackContext(ctx context.Context, err error) (context.Context, context.CancelFunc) {
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx, func() {}
+	}
+
+	timeout := g.config.FallbackTimeout
+	if timeout <= 0 {
+		timeout = g.config.Timeout
+	}
+	if timeout <= 0 {
+		return context.WithoutCancel(ctx), func() {}
+	}
+	return context.WithTimeout(context.WithoutCancel(ctx), timeout)
+}
+
 // GetState returns the current state of the circuit breaker for the given name.
 func (g *gobreakerAdapter) GetState(name string) appcircuitbreaker.State {
 	g.mu.RLock()
@@ -274,7 +203,11 @@ func (g *gobreakerAdapter) GetState(name string) appcircuitbreaker.State {
 		return appcircuitbreaker.StateClosed
 	}
 
-	state := breaker.State()
+	return toState(breaker.State())
+}
+
+// toState maps an engine state to the port's State.
+func toState(state string) appcircuitbreaker.State {
 	switch state {
 	case stateOpen:
 		return appcircuitbreaker.StateOpen
@@ -285,6 +218,24 @@ func (g *gobreakerAdapter) GetState(name string) appcircuitbreaker.State {
 	}
 }
 
+// List returns the status of every breaker created so far, sorted by name.
+// Reset breakers are dropped until they are used again.
+func (g *gobreakerAdapter) List() []appcircuitbreaker.BreakerStatus {
+	g.mu.RLock()
+	breakers := make(map[string]*circuitBreaker, len(g.breakers))
+	for name, breaker := range g.breakers {
+		breakers[name] = breaker
+	}
+	g.mu.RUnlock()
+
+	statuses := make([]appcircuitbreaker.BreakerStatus, 0, len(breakers))
+	for name, breaker := range breakers {
+		statuses = append(statuses, appcircuitbreaker.BreakerStatus{Name: name, State: toState(breaker.State())})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
 // Reset resets the circuit breaker for the given name to its initial state.
 func (g *gobreakerAdapter) Reset(name string) {
 	g.mu.Lock()
@@ -292,3 +243,11 @@ func (g *gobreakerAdapter) Reset(name string) {
 
 	delete(g.breakers, name)
 }
+
+// ResetAll resets every circuit breaker to its initial state.
+func (g *gobreakerAdapter) ResetAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	clear(g.breakers)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	appcb "github.com/next-trace/scg-service-api/application/circuitbreaker"
 	cbimpl "github.com/next-trace/scg-service-api/infrastructure/circuitbreaker"
@@ -38,3 +39,112 @@ func TestGoBreakerAdapter_ExecuteAndState(t *testing.T) {
 	// Reset should not panic
 	br.Reset("svc")
 }
+
+func TestGoBreakerAdapter_FallbackAfterTimeout(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := appcb.DefaultConfig()
+	cfg.Timeout = 20 * time.Millisecond
+	cfg.FallbackTimeout = time.Second
+	br := cbimpl.NewGoBreakerAdapter(cfg, infraLogger.NewSlogAdapter(&buf, "info"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	res, err := br.ExecuteWithFallback(ctx, "slow", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, func(ctx context.Context, err error) (interface{}, error) {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline error from primary, got %v", err)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) < 500*time.Millisecond {
+			t.Errorf("expected a fresh fallback deadline, got %v (set=%v)", deadline, ok)
+		}
+		return "fallback", nil
+	})
+	if err != nil || res != "fallback" {
+		t.Fatalf("unexpected fallback result: %v, %v", res, err)
+	}
+}
+
+func TestGoBreakerAdapter_NilLogger(t *testing.T) {
+	cfg := appcb.DefaultConfig()
+	cfg.RequestVolumeThreshold = 1
+	br := cbimpl.NewGoBreakerAdapter(cfg, nil)
+
+	// Tripping the breaker logs the state change.
+	_, _ = br.Execute(context.Background(), "svc", func(context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	if st := br.GetState("svc"); st != appcb.StateOpen {
+		t.Fatalf("expected OPEN, got %s", st)
+	}
+}
+
+func TestGoBreakerAdapter_List(t *testing.T) {
+	cfg := appcb.DefaultConfig()
+	cfg.RequestVolumeThreshold = 1
+	br := cbimpl.NewGoBreakerAdapter(cfg, nil)
+	ctx := context.Background()
+
+	if got := br.List(); len(got) != 0 {
+		t.Fatalf("expected no breakers, got %v", got)
+	}
+
+	_, _ = br.Execute(ctx, "payments", func(context.Context) (interface{}, error) { return nil, errors.New("boom") })
+	_, _ = br.Execute(ctx, "inventory", func(context.Context) (interface{}, error) { return "ok", nil })
+
+	want := []appcb.BreakerStatus{
+		{Name: "inventory", State: appcb.StateClosed},
+		{Name: "payments", State: appcb.StateOpen},
+	}
+	got := br.List()
+	if len(got) != len(want) {
+		t.Fatalf("List() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("List() = %v, want %v", got, want)
+		}
+	}
+
+	br.Reset("payments")
+	if got := br.List(); len(got) != 1 || got[0].Name != "inventory" {
+		t.Fatalf("expected only inventory after reset, got %v", got)
+	}
+}
+
+func TestGoBreakerAdapter_ResetAll(t *testing.T) {
+	cfg := appcb.DefaultConfig()
+	cfg.RequestVolumeThreshold = 1
+	br := cbimpl.NewGoBreakerAdapter(cfg, nil)
+	ctx := context.Background()
+
+	names := []string{"payments", "inventory", "shipping"}
+	for _, name := range names {
+		_, _ = br.Execute(ctx, name, func(context.Context) (interface{}, error) { return nil, errors.New("boom") })
+		if st := br.GetState(name); st != appcb.StateOpen {
+			t.Fatalf("expected %s OPEN, got %s", name, st)
+		}
+	}
+
+	br.ResetAll()
+	for _, name := range names {
+		if st := br.GetState(name); st != appcb.StateClosed {
+			t.Fatalf("expected %s CLOSED after ResetAll, got %s", name, st)
+		}
+	}
+	if got := br.List(); len(got) != 0 {
+		t.Fatalf("expected no breakers after ResetAll, got %v", got)
+	}
+
+	// Breakers are usable again after the reset.
+	res, err := br.Execute(ctx, "payments", func(context.Context) (interface{}, error) { return "ok", nil })
+	if err != nil || res != "ok" {
+		t.Fatalf("unexpected result after ResetAll: %v, %v", res, err)
+	}
+}
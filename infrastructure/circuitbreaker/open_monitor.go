@@ -0,0 +1,150 @@
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	appcircuitbreaker "github.com/next-trace/scg-service-api/application/circuitbreaker"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+)
+
+// openAlertsMetric counts the warnings the open monitor has logged, labelled
+// with the breaker name.
+const openAlertsMetric = "circuit_breaker_open_alerts_total"
+
+// MonitorOption customizes an OpenMonitor.
+type MonitorOption func(*OpenMonitor)
+
+// WithMonitorMetrics makes the monitor increment
+// circuit_breaker_open_alerts_total, labelled by breaker, with every warning.
+func WithMonitorMetrics(m appmetrics.Metrics) MonitorOption {
+	return func(o *OpenMonitor) { o.metrics = m }
+}
+
+// WithMonitorClock replaces the clock used to measure how long breakers have
+// been open, e.g. with a fake clock in tests.
+func WithMonitorClock(now func() time.Time) MonitorOption {
+	return func(o *OpenMonitor) { o.now = now }
+}
+
+// OpenMonitor watches a CircuitBreaker and logs a warning for every breaker
+// that has been open longer than Config.OpenAlertThreshold, repeating it every
+// Config.OpenAlertInterval until the breaker closes. A breaker that flips to
+// half-open and back while its dependency is still down counts as staying
+// open. It implements lifecycle.Runnable.
+type OpenMonitor struct {
+	cb        appcircuitbreaker.CircuitBreaker
+	threshold time.Duration
+	interval  time.Duration
+	log       applogger.Logger
+	metrics   appmetrics.Metrics
+	now       func() time.Time
+
+	mu         sync.Mutex
+	openSince  map[string]time.Time
+	lastWarned map[string]time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewOpenMonitor creates a monitor for cb configured from config.
+func NewOpenMonitor(config appcircuitbreaker.Config, cb appcircuitbreaker.CircuitBreaker, log applogger.Logger, opts ...MonitorOption) *OpenMonitor {
+	log = applogger.OrNop(log)
+	m := &OpenMonitor{
+		cb:         cb,
+		threshold:  config.OpenAlertThreshold,
+		interval:   config.OpenAlertInterval,
+		log:        log,
+		now:        time.Now,
+		openSince:  make(map[string]time.Time),
+		lastWarned: make(map[string]time.Time),
+		stop:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Run checks the breakers every OpenAlertInterval until ctx is done or
+// Shutdown is called. It returns at once if the monitor is disabled.
+func (m *OpenMonitor) Run(ctx context.Context) error {
+	if m.threshold <= 0 || m.interval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-m.stop:
+			return nil
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Shutdown stops Run.
+func (m *OpenMonitor) Shutdown(ctx context.Context) error {
+	_ = ctx
+	m.stopOnce.Do(func() { close(m.stop) })
+	return nil
+}
+
+// Check looks at every breaker once, recording when it opened and warning if
+// it has been open too long. Run calls it on every tick.
+func (m *OpenMonitor) Check(ctx context.Context) {
+	now := m.now()
+	statuses := m.cb.List()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		if status.State == appcircuitbreaker.StateClosed {
+			continue
+		}
+		seen[status.Name] = true
+
+		since, ok := m.openSince[status.Name]
+		if !ok {
+			m.openSince[status.Name] = now
+			continue
+		}
+
+		openFor := now.Sub(since)
+		if openFor < m.threshold {
+			continue
+		}
+		if last, warned := m.lastWarned[status.Name]; warned && now.Sub(last) < m.interval {
+			continue
+		}
+		m.lastWarned[status.Name] = now
+
+		m.log.WarnKV(ctx, "circuit breaker open too long", map[string]interface{}{
+			"breaker":    status.Name,
+			"state":      string(status.State),
+			"open_since": since.Format(time.RFC3339),
+			"open_for":   openFor.String(),
+		})
+		if m.metrics != nil {
+			m.metrics.WithLabels(map[string]string{"breaker": status.Name}).CounterInc(openAlertsMetric)
+		}
+	}
+
+	// Breakers that closed or were reset start over.
+	for name := range m.openSince {
+		if !seen[name] {
+			delete(m.openSince, name)
+			delete(m.lastWarned, name)
+		}
+	}
+}
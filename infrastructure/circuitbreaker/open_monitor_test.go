@@ -0,0 +1,105 @@
+package circuitbreaker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appcb "github.com/next-trace/scg-service-api/application/circuitbreaker"
+	cbimpl "github.com/next-trace/scg-service-api/infrastructure/circuitbreaker"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+func countWarnings(log *testsupport.Logger) int {
+	n := 0
+	for _, e := range log.Entries() {
+		if e.Msg == "circuit breaker open too long" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestOpenMonitor_WarnsUntilClosed(t *testing.T) {
+	ctx := context.Background()
+	cfg := appcb.DefaultConfig()
+	cfg.OpenAlertThreshold = time.Minute
+	cfg.OpenAlertInterval = 30 * time.Second
+
+	cb := testsupport.NewCircuitBreaker()
+	log := testsupport.NewLogger()
+	metrics := testsupport.NewMetrics()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mon := cbimpl.NewOpenMonitor(cfg, cb, log,
+		cbimpl.WithMonitorClock(func() time.Time { return now }),
+		cbimpl.WithMonitorMetrics(metrics))
+
+	cb.SetState("payments", appcb.StateOpen)
+	mon.Check(ctx)
+
+	now = now.Add(59 * time.Second)
+	mon.Check(ctx)
+	if n := countWarnings(log); n != 0 {
+		t.Fatalf("expected no warning before the threshold, got %d", n)
+	}
+
+	now = now.Add(time.Second)
+	mon.Check(ctx)
+	entry := log.AssertLogged(t, "circuit breaker open too long")
+	if entry.Level != "warn" || entry.Fields["breaker"] != "payments" {
+		t.Fatalf("unexpected warning entry: %+v", entry)
+	}
+
+	// Checks within the interval do not repeat the warning; the next one does.
+	now = now.Add(10 * time.Second)
+	mon.Check(ctx)
+	if n := countWarnings(log); n != 1 {
+		t.Fatalf("expected 1 warning within the interval, got %d", n)
+	}
+	now = now.Add(20 * time.Second)
+	mon.Check(ctx)
+	if n := countWarnings(log); n != 2 {
+		t.Fatalf("expected the warning to repeat after the interval, got %d", n)
+	}
+	metrics.AssertCounter(t, "circuit_breaker_open_alerts_total", map[string]string{"breaker": "payments"}, 2)
+
+	cb.Reset("payments")
+	for i := 0; i < 5; i++ {
+		now = now.Add(time.Minute)
+		mon.Check(ctx)
+	}
+	if n := countWarnings(log); n != 2 {
+		t.Fatalf("expected no warnings after the breaker closed, got %d", n)
+	}
+
+	// Opening again starts a new threshold period.
+	cb.SetState("payments", appcb.StateOpen)
+	mon.Check(ctx)
+	now = now.Add(30 * time.Second)
+	mon.Check(ctx)
+	if n := countWarnings(log); n != 2 {
+		t.Fatalf("expected the threshold to restart after reopening, got %d", n)
+	}
+}
+
+func TestOpenMonitor_RunStopsOnShutdown(t *testing.T) {
+	cfg := appcb.DefaultConfig()
+	cfg.OpenAlertInterval = time.Millisecond
+	mon := cbimpl.NewOpenMonitor(cfg, testsupport.NewCircuitBreaker(), nil)
+
+	done := make(chan error, 1)
+	go func() { done <- mon.Run(context.Background()) }()
+
+	time.Sleep(5 * time.Millisecond)
+	if err := mon.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected Run to return nil, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Shutdown")
+	}
+}
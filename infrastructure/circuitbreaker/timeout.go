@@ -0,0 +1,51 @@
+package circuitbreaker
+
+import (
+	"context"
+	"time"
+
+	appcb "github.com/next-trace/scg-service-api/application/circuitbreaker"
+)
+
+// timeoutBreaker bounds every protected call of the wrapped breaker with a
+// default timeout when the caller's context carries no deadline of its own.
+type timeoutBreaker struct {
+	appcb.CircuitBreaker
+	timeout time.Duration
+}
+
+// WithDefaultTimeout wraps next so that the fn passed to Execute and
+// ExecuteWithFallback runs under context.WithTimeout(ctx, timeout) unless ctx
+// already has a deadline, in which case the caller's deadline is kept. The
+// fallback still receives the caller's context, so it can run after fn timed
+// out. A non-positive timeout returns next unchanged.
+func WithDefaultTimeout(next appcb.CircuitBreaker, timeout time.Duration) appcb.CircuitBreaker {
+	if timeout <= 0 {
+		return next
+	}
+	return &timeoutBreaker{CircuitBreaker: next, timeout: timeout}
+}
+
+// Execute runs the wrapped Execute with fn bounded by the default timeout.
+func (b *timeoutBreaker) Execute(ctx context.Context, name string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return b.CircuitBreaker.Execute(ctx, name, b.bound(fn))
+}
+
+// ExecuteWithFallback runs the wrapped ExecuteWithFallback with fn bounded by
+// the default timeout.
+func (b *timeoutBreaker) ExecuteWithFallback(ctx context.Context, name string, fn func(ctx context.Context) (interface{}, error), fallback func(ctx context.Context, err error) (interface{}, error)) (interface{}, error) {
+	return b.CircuitBreaker.ExecuteWithFallback(ctx, name, b.bound(fn), fallback)
+}
+
+// bound returns fn wrapped to apply the default timeout to a context that has
+// no deadline.
+func (b *timeoutBreaker) bound(fn func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		if _, ok := ctx.Deadline(); ok {
+			return fn(ctx)
+		}
+		ctx, cancel := context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+		return fn(ctx)
+	}
+}
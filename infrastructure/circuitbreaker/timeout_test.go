@@ -0,0 +1,65 @@
+package circuitbreaker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cbimpl "github.com/next-trace/scg-service-api/infrastructure/circuitbreaker"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+func TestWithDefaultTimeout_AppliesDefault(t *testing.T) {
+	cb := cbimpl.WithDefaultTimeout(testsupport.NewCircuitBreaker(), time.Second)
+
+	start := time.Now()
+	var deadline time.Time
+	var ok bool
+	_, _ = cb.Execute(context.Background(), "svc", func(ctx context.Context) (interface{}, error) {
+		deadline, ok = ctx.Deadline()
+		return nil, nil
+	})
+	end := time.Now()
+
+	if !ok {
+		t.Fatal("expected the default timeout to set a deadline")
+	}
+	if deadline.Before(start.Add(time.Second)) || deadline.After(end.Add(time.Second)) {
+		t.Fatalf("expected a deadline 1s after the call, got %v", deadline.Sub(start))
+	}
+}
+
+func TestWithDefaultTimeout_KeepsCallerDeadline(t *testing.T) {
+	cb := cbimpl.WithDefaultTimeout(testsupport.NewCircuitBreaker(), time.Second)
+
+	want := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+
+	var deadline time.Time
+	_, _ = cb.Execute(ctx, "svc", func(ctx context.Context) (interface{}, error) {
+		deadline, _ = ctx.Deadline()
+		return nil, nil
+	})
+
+	if !deadline.Equal(want) {
+		t.Fatalf("expected caller deadline %v, got %v", want, deadline)
+	}
+}
+
+func TestWithDefaultTimeout_FallbackGetsCallerContext(t *testing.T) {
+	cb := cbimpl.WithDefaultTimeout(testsupport.NewCircuitBreaker(), 10*time.Millisecond)
+
+	result, err := cb.ExecuteWithFallback(context.Background(), "svc",
+		func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		func(ctx context.Context, _ error) (interface{}, error) {
+			return "fallback", ctx.Err()
+		})
+
+	if err != nil || result != "fallback" {
+		t.Fatalf("expected the fallback to run with a live context, got %v %v", result, err)
+	}
+}
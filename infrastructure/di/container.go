@@ -1,35 +1,83 @@
 // Package di provides dependency injection utilities.
 //
-// Note: This package requires the following dependencies:
-// - github.com/uber-go/dig
+// By default the package uses a built-in resolver with no external
+// dependencies. Building with the dig tag (go build -tags dig) switches the
+// resolver to go.uber.org/dig, which must then be required in go.mod. The
+// public API is identical in both configurations.
 //
 // See docs/dependencies.md for more information.
 package di
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"time"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
 )
 
-// Container is a dependency injection container.
-// In a real implementation, this would use the dig library from Uber.
-// For now, we'll provide a simple implementation that can be replaced later.
+// Container is a dependency injection container. Resolution is delegated to
+// the resolver in resolver_builtin.go or, with the dig build tag,
+// resolver_dig.go; the container adds timing and shutdown hooks on top.
 type Container struct {
-	providers map[reflect.Type]provider
-	instances map[reflect.Type]interface{}
+	resolver *resolver
+	hooks    []shutdownHook
+
+	timing  bool
+	log     applogger.Logger
+	timings []ResolutionTiming
+}
+
+// Option configures a Container.
+type Option func(*Container)
+
+// WithResolutionTiming records how long each constructor takes, for finding
+// the expensive ones behind a slow startup. Timings are available from
+// Report and, when log is non-nil, logged at debug as they are recorded.
+func WithResolutionTiming(log applogger.Logger) Option {
+	return func(c *Container) {
+		c.timing = true
+		c.log = applogger.OrNop(log)
+	}
+}
+
+// ResolutionTiming is the time spent constructing one resolved type.
+// Duration covers only the constructor call, not the resolution of its
+// parameters, which have entries of their own.
+type ResolutionTiming struct {
+	Type     reflect.Type
+	Duration time.Duration
+}
+
+// shutdownHook stops a constructed instance when the container is stopped.
+type shutdownHook struct {
+	typ  reflect.Type
+	stop func(ctx context.Context) error
+}
+
+// flusher is implemented by loggers that buffer entries, see applogger.Logger.Flush.
+type flusher interface {
+	Flush() error
 }
 
-type provider struct {
-	constructor interface{}
-	params      []reflect.Type
+// shutdowner is implemented by adapters that release resources with a context,
+// such as metrics.Shutdown or tracer.Shutdown.
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
 }
 
 // NewContainer creates a new dependency injection container.
-func NewContainer() *Container {
-	return &Container{
-		providers: make(map[reflect.Type]provider),
-		instances: make(map[reflect.Type]interface{}),
+func NewContainer(opts ...Option) *Container {
+	c := &Container{
+		resolver: newResolver(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Provide registers a constructor function with the container.
@@ -44,106 +92,105 @@ func (c *Container) Provide(constructor interface{}) error {
 		return fmt.Errorf("constructor must return at least one value")
 	}
 
-	// Get the type of the first return value
-	returnType := constructorType.Out(0)
-
-	// Get parameter types
-	params := make([]reflect.Type, constructorType.NumIn())
-	for i := range constructorType.NumIn() {
-		params[i] = constructorType.In(i)
-	}
-
-	// Register the provider
-	c.providers[returnType] = provider{
-		constructor: constructor,
-		params:      params,
-	}
+	return c.resolver.provide(c.instrument(constructor))
+}
 
-	return nil
+// instrument wraps constructor so that each value it builds is timed and
+// registered for Stop, whichever resolver calls it.
+func (c *Container) instrument(constructor interface{}) interface{} {
+	fn := reflect.ValueOf(constructor)
+	fnType := fn.Type()
+	typ := fnType.Out(0)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		start := time.Now()
+		results := fn.Call(args)
+		c.recordTiming(typ, time.Since(start))
+
+		// A constructor that failed built nothing to stop.
+		if n := fnType.NumOut(); n > 1 && fnType.Out(n-1) == errorType && !results[n-1].IsNil() {
+			return results
+		}
+		c.registerShutdownHook(typ, results[0].Interface())
+		return results
+	}).Interface()
 }
 
+// errorType is the reflect.Type of error, used to spot constructors that can fail.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // Resolve resolves a dependency from the container.
 func (c *Container) Resolve(target interface{}) error {
-	targetValue := reflect.ValueOf(target)
-	if targetValue.Kind() != reflect.Ptr {
+	if reflect.ValueOf(target).Kind() != reflect.Ptr {
 		return fmt.Errorf("target must be a pointer")
 	}
-
-	targetElem := targetValue.Elem()
-	targetType := targetElem.Type()
-
-	// Check if we already have an instance
-	if instance, ok := c.instances[targetType]; ok {
-		targetElem.Set(reflect.ValueOf(instance))
-		return nil
-	}
-
-	// Find the provider
-	provider, ok := c.providers[targetType]
-	if !ok {
-		return fmt.Errorf("no provider found for type %s", targetType)
-	}
-
-	// Resolve dependencies
-	args := make([]reflect.Value, len(provider.params))
-	for i, paramType := range provider.params {
-		// Create a new instance of the parameter type
-		paramInstance := reflect.New(paramType).Interface()
-
-		// Resolve the parameter
-		if err := c.Resolve(paramInstance); err != nil {
-			return fmt.Errorf("failed to resolve parameter %d: %w", i, err)
-		}
-
-		// Get the value of the parameter
-		args[i] = reflect.ValueOf(paramInstance).Elem()
-	}
-
-	// Call the constructor
-	results := reflect.ValueOf(provider.constructor).Call(args)
-	if len(results) == 0 {
-		return fmt.Errorf("constructor returned no values")
-	}
-
-	// Store the instance
-	instance := results[0].Interface()
-	c.instances[targetType] = instance
-
-	// Set the target value
-	targetElem.Set(reflect.ValueOf(instance))
-
-	return nil
+	return c.resolver.resolve(target)
 }
 
 // Invoke calls the given function with resolved dependencies.
 func (c *Container) Invoke(function interface{}) error {
-	functionType := reflect.TypeOf(function)
-	if functionType.Kind() != reflect.Func {
+	if reflect.TypeOf(function).Kind() != reflect.Func {
 		return fmt.Errorf("function must be a function")
 	}
+	return c.resolver.invoke(function)
+}
 
-	// Resolve dependencies
-	args := make([]reflect.Value, functionType.NumIn())
-	for i := range functionType.NumIn() {
-		paramType := functionType.In(i)
-		paramInstance := reflect.New(paramType).Interface()
+// Report returns the constructor timings recorded since the container was
+// created or last Reset, in construction order. It is empty unless the
+// container was created with WithResolutionTiming.
+func (c *Container) Report() []ResolutionTiming {
+	return append([]ResolutionTiming(nil), c.timings...)
+}
 
-		// Resolve the parameter
-		if err := c.Resolve(paramInstance); err != nil {
-			return fmt.Errorf("failed to resolve parameter %d: %w", i, err)
-		}
+// Reset clears all instances and recorded timings from the container.
+// Registered shutdown hooks are discarded without being run; call Stop first
+// if the instances hold resources.
+func (c *Container) Reset() {
+	c.resolver.reset()
+	c.hooks = nil
+	c.timings = nil
+}
 
-		// Get the value of the parameter
-		args[i] = reflect.ValueOf(paramInstance).Elem()
+// recordTiming stores and logs a constructor timing when timing is enabled.
+func (c *Container) recordTiming(typ reflect.Type, d time.Duration) {
+	if !c.timing {
+		return
 	}
+	c.timings = append(c.timings, ResolutionTiming{Type: typ, Duration: d})
+	c.log.DebugKV(context.Background(), "dependency resolved", map[string]interface{}{
+		"type":        typ.String(),
+		"duration_ms": d.Milliseconds(),
+	})
+}
 
-	// Call the function
-	reflect.ValueOf(function).Call(args)
+// Stop shuts down every constructed instance that implements io.Closer,
+// Shutdown(ctx) error or Flush() error, in reverse construction order. All hooks are run even
+// if some fail; the returned error joins every failure.
+func (c *Container) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(c.hooks) - 1; i >= 0; i-- {
+		hook := c.hooks[i]
+		if err := hook.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hook.typ, err))
+		}
+	}
+	c.hooks = nil
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// Reset clears all instances from the container.
-func (c *Container) Reset() {
-	c.instances = make(map[reflect.Type]interface{})
+// registerShutdownHook records instance for Stop if it exposes a shutdown method.
+// Shutdown(ctx) takes precedence over Close, and Close over Flush.
+func (c *Container) registerShutdownHook(typ reflect.Type, instance interface{}) {
+	switch v := instance.(type) {
+	case shutdowner:
+		c.hooks = append(c.hooks, shutdownHook{typ: typ, stop: v.Shutdown})
+	case io.Closer:
+		c.hooks = append(c.hooks, shutdownHook{typ: typ, stop: func(context.Context) error {
+			return v.Close()
+		}})
+	case flusher:
+		c.hooks = append(c.hooks, shutdownHook{typ: typ, stop: func(context.Context) error {
+			return v.Flush()
+		}})
+	}
 }
@@ -1,9 +1,13 @@
 package di_test
 
 import (
+	"context"
+	"reflect"
 	"testing"
+	"time"
 
 	di "github.com/next-trace/scg-service-api/infrastructure/di"
+	"github.com/next-trace/scg-service-api/testsupport"
 )
 
 type A struct{ Name string }
@@ -46,3 +50,132 @@ func TestContainer_ProvideResolveInvoke(t *testing.T) {
 
 	c.Reset()
 }
+
+type closer struct {
+	name  string
+	order *[]string
+}
+
+func (c *closer) Close() error {
+	*c.order = append(*c.order, c.name)
+	return nil
+}
+
+type first struct{ *closer }
+
+type second struct{ *closer }
+
+func TestContainer_StopClosesInReverseOrder(t *testing.T) {
+	var order []string
+	c := di.NewContainer()
+	if err := c.Provide(func() first { return first{&closer{name: "first", order: &order}} }); err != nil {
+		t.Fatalf("provide first: %v", err)
+	}
+	if err := c.Provide(func(f first) second { return second{&closer{name: "second", order: &order}} }); err != nil {
+		t.Fatalf("provide second: %v", err)
+	}
+
+	var s second
+	if err := c.Resolve(&s); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if len(order) != 2 || order[0] != "second" || order[1] != "first" {
+		t.Fatalf("unexpected close order: %v", order)
+	}
+
+	// A second Stop is a no-op.
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("second stop: %v", err)
+	}
+	if len(order) != 2 {
+		t.Fatalf("expected closers to run once, got %v", order)
+	}
+}
+
+func TestContainer_ResolutionTimingReport(t *testing.T) {
+	log := testsupport.NewLogger()
+	c := di.NewContainer(di.WithResolutionTiming(log))
+	slowA := func() A {
+		time.Sleep(10 * time.Millisecond)
+		return newA()
+	}
+	if err := c.Provide(slowA); err != nil {
+		t.Fatalf("provide A: %v", err)
+	}
+	if err := c.Provide(newB); err != nil {
+		t.Fatalf("provide B: %v", err)
+	}
+
+	var b B
+	if err := c.Resolve(&b); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	report := c.Report()
+	if len(report) != 2 {
+		t.Fatalf("expected 2 timings, got %d", len(report))
+	}
+	// Dependencies are constructed first.
+	if report[0].Type != reflect.TypeOf(A{}) || report[1].Type != reflect.TypeOf(B{}) {
+		t.Fatalf("unexpected report order: %v, %v", report[0].Type, report[1].Type)
+	}
+	if report[0].Duration < 10*time.Millisecond {
+		t.Fatalf("expected A timing of at least 10ms, got %v", report[0].Duration)
+	}
+	entry := log.AssertLogged(t, "dependency resolved")
+	if entry.Level != "debug" || entry.Fields["type"] != "di_test.A" {
+		t.Fatalf("unexpected log entry: %+v", entry)
+	}
+
+	c.Reset()
+	if len(c.Report()) != 0 {
+		t.Fatalf("expected Reset to clear the report")
+	}
+}
+
+func TestContainer_ReportDisabledByDefault(t *testing.T) {
+	c := di.NewContainer()
+	if err := c.Provide(newA); err != nil {
+		t.Fatalf("provide A: %v", err)
+	}
+	var a A
+	if err := c.Resolve(&a); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(c.Report()) != 0 {
+		t.Fatalf("expected no timings without WithResolutionTiming")
+	}
+}
+
+func TestContainer_ResetRebuildsInstances(t *testing.T) {
+	built := 0
+	c := di.NewContainer()
+	if err := c.Provide(func() A {
+		built++
+		return newA()
+	}); err != nil {
+		t.Fatalf("provide A: %v", err)
+	}
+
+	var a A
+	for range 2 {
+		if err := c.Resolve(&a); err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+	}
+	if built != 1 {
+		t.Fatalf("expected A to be built once, got %d", built)
+	}
+
+	c.Reset()
+	if err := c.Resolve(&a); err != nil {
+		t.Fatalf("resolve after reset: %v", err)
+	}
+	if built != 2 {
+		t.Fatalf("expected Reset to rebuild A, got %d builds", built)
+	}
+}
@@ -74,7 +74,7 @@ func registerInfrastructure(container *Container) {
 
 	// Register logger
 	err = container.Provide(func() applogger.Logger {
-		return logger.NewSlogAdapter(nil, "info")
+		return logger.New()
 	})
 	handleProvideError(err, "logger")
 
@@ -0,0 +1,134 @@
+//go:build !dig
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// resolver is the built-in engine used when the dig build tag is not set.
+// It mirrors the subset of go.uber.org/dig the container uses: one
+// constructor per type, each constructed at most once.
+type resolver struct {
+	providers map[reflect.Type]provider
+	instances map[reflect.Type]interface{}
+}
+
+type provider struct {
+	constructor interface{}
+	params      []reflect.Type
+}
+
+// newResolver creates an empty resolver.
+func newResolver() *resolver {
+	return &resolver{
+		providers: make(map[reflect.Type]provider),
+		instances: make(map[reflect.Type]interface{}),
+	}
+}
+
+// provide registers constructor for the type of its first result, replacing
+// any earlier constructor for that type.
+func (r *resolver) provide(constructor interface{}) error {
+	constructorType := reflect.TypeOf(constructor)
+
+	// Get the type of the first return value
+	returnType := constructorType.Out(0)
+
+	// Get parameter types
+	params := make([]reflect.Type, constructorType.NumIn())
+	for i := range constructorType.NumIn() {
+		params[i] = constructorType.In(i)
+	}
+
+	// Register the provider
+	r.providers[returnType] = provider{
+		constructor: constructor,
+		params:      params,
+	}
+
+	return nil
+}
+
+// resolve sets the value target points to, constructing it and its
+// dependencies if needed.
+func (r *resolver) resolve(target interface{}) error {
+	targetElem := reflect.ValueOf(target).Elem()
+	targetType := targetElem.Type()
+
+	// Check if we already have an instance
+	if instance, ok := r.instances[targetType]; ok {
+		targetElem.Set(reflect.ValueOf(instance))
+		return nil
+	}
+
+	// Find the provider
+	provider, ok := r.providers[targetType]
+	if !ok {
+		return fmt.Errorf("no provider found for type %s", targetType)
+	}
+
+	// Resolve dependencies
+	args, err := r.resolveParams(provider.params)
+	if err != nil {
+		return err
+	}
+
+	// Call the constructor
+	results := reflect.ValueOf(provider.constructor).Call(args)
+	if len(results) == 0 {
+		return fmt.Errorf("constructor returned no values")
+	}
+
+	// Store the instance
+	instance := results[0].Interface()
+	r.instances[targetType] = instance
+
+	// Set the target value
+	targetElem.Set(reflect.ValueOf(instance))
+
+	return nil
+}
+
+// invoke calls function with its parameters resolved.
+func (r *resolver) invoke(function interface{}) error {
+	functionType := reflect.TypeOf(function)
+	params := make([]reflect.Type, functionType.NumIn())
+	for i := range functionType.NumIn() {
+		params[i] = functionType.In(i)
+	}
+
+	args, err := r.resolveParams(params)
+	if err != nil {
+		return err
+	}
+
+	// Call the function
+	reflect.ValueOf(function).Call(args)
+
+	return nil
+}
+
+// resolveParams resolves a value for each of the given types.
+func (r *resolver) resolveParams(params []reflect.Type) ([]reflect.Value, error) {
+	args := make([]reflect.Value, len(params))
+	for i, paramType := range params {
+		// Create a new instance of the parameter type
+		paramInstance := reflect.New(paramType).Interface()
+
+		// Resolve the parameter
+		if err := r.resolve(paramInstance); err != nil {
+			return nil, fmt.Errorf("failed to resolve parameter %d: %w", i, err)
+		}
+
+		// Get the value of the parameter
+		args[i] = reflect.ValueOf(paramInstance).Elem()
+	}
+	return args, nil
+}
+
+// reset discards every constructed instance; constructors stay registered.
+func (r *resolver) reset() {
+	r.instances = make(map[reflect.Type]interface{})
+}
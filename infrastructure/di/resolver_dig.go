@@ -0,0 +1,59 @@
+//go:build dig
+
+package di
+
+import (
+	"reflect"
+
+	"go.uber.org/dig"
+)
+
+// resolver wraps a dig.Container when building with the dig tag.
+type resolver struct {
+	c *dig.Container
+
+	// constructors are replayed into a fresh dig.Container by reset, as dig
+	// cannot discard the instances it has built.
+	constructors []interface{}
+}
+
+// newResolver creates an empty resolver.
+func newResolver() *resolver {
+	return &resolver{c: dig.New()}
+}
+
+// provide registers constructor with dig. Unlike the built-in engine, dig
+// rejects a second constructor for a type that already has one.
+func (r *resolver) provide(constructor interface{}) error {
+	if err := r.c.Provide(constructor); err != nil {
+		return err
+	}
+	r.constructors = append(r.constructors, constructor)
+	return nil
+}
+
+// resolve sets the value target points to, constructing it and its
+// dependencies if needed.
+func (r *resolver) resolve(target interface{}) error {
+	targetElem := reflect.ValueOf(target).Elem()
+	fnType := reflect.FuncOf([]reflect.Type{targetElem.Type()}, nil, false)
+	fn := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		targetElem.Set(args[0])
+		return nil
+	})
+	return r.c.Invoke(fn.Interface())
+}
+
+// invoke calls function with its parameters resolved.
+func (r *resolver) invoke(function interface{}) error {
+	return r.c.Invoke(function)
+}
+
+// reset discards every constructed instance; constructors stay registered.
+func (r *resolver) reset() {
+	r.c = dig.New()
+	for _, constructor := range r.constructors {
+		// Each constructor was accepted by an identical container before.
+		_ = r.c.Provide(constructor)
+	}
+}
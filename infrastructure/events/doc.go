@@ -0,0 +1,4 @@
+// Package events contains an in-memory adapter that satisfies application/events.
+// It delivers events within one process, synchronously or through a worker pool;
+// broker-backed adapters can be added later without changing publishers.
+package events
@@ -0,0 +1,175 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	appevents "github.com/next-trace/scg-service-api/application/events"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// ErrBusClosed is returned by Publish after Close.
+var ErrBusClosed = errors.New("event bus is closed")
+
+// subscription is one handler subscribed to a topic.
+type subscription struct {
+	id      uint64
+	handler appevents.Handler
+}
+
+// queuedEvent is an event waiting for a worker, with the context it was
+// published under.
+type queuedEvent struct {
+	ctx   context.Context
+	event appevents.Event
+}
+
+// memoryBus implements the events.EventBus interface in process.
+type memoryBus struct {
+	config appevents.Config
+	log    applogger.Logger
+
+	subsMu sync.RWMutex
+	subs   map[string][]subscription
+	nextID uint64
+
+	// closeMu is held for reading while publishing and for writing while
+	// closing, so the queue is never written to after it is closed.
+	closeMu sync.RWMutex
+	closed  bool
+	queue   chan queuedEvent
+	workers sync.WaitGroup
+}
+
+// NewMemoryBus creates an in-memory event bus. With config.Workers at zero,
+// Publish calls the handlers itself and returns their joined errors. With
+// positive Workers, Publish queues the event and returns once it is queued;
+// the workers deliver it with the publisher's context values, but not its
+// cancellation, and log handler errors.
+func NewMemoryBus(config appevents.Config, log applogger.Logger) appevents.EventBus {
+	log = applogger.OrNop(log)
+	bus := &memoryBus{
+		config: config,
+		log:    log,
+		subs:   make(map[string][]subscription),
+	}
+
+	if config.Workers > 0 {
+		bus.queue = make(chan queuedEvent, max(config.QueueSize, 0))
+		for range config.Workers {
+			bus.workers.Go(bus.work)
+		}
+	}
+
+	return bus
+}
+
+// Publish delivers event to the handlers subscribed to its topic.
+func (b *memoryBus) Publish(ctx context.Context, event appevents.Event) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	if b.queue == nil {
+		return b.deliver(ctx, event)
+	}
+
+	select {
+	case b.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: event}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Subscribe registers handler for events on topic.
+func (b *memoryBus) Subscribe(topic string, handler appevents.Handler) func() {
+	b.subsMu.Lock()
+	defer b.subsMu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subs[topic] = append(b.subs[topic], subscription{id: id, handler: handler})
+
+	return func() { b.unsubscribe(topic, id) }
+}
+
+// unsubscribe removes the subscription with the given ID, if still present.
+func (b *memoryBus) unsubscribe(topic string, id uint64) {
+	b.subsMu.Lock()
+	defer b.subsMu.Unlock()
+
+	subs := b.subs[topic]
+	for i, sub := range subs {
+		if sub.id == id {
+			b.subs[topic] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.subs[topic]) == 0 {
+		delete(b.subs, topic)
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered.
+func (b *memoryBus) Close(ctx context.Context) error {
+	b.closeMu.Lock()
+	if b.closed {
+		b.closeMu.Unlock()
+		return nil
+	}
+	b.closed = true
+	if b.queue != nil {
+		close(b.queue)
+	}
+	b.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("event bus closed before queued events were delivered: %w", ctx.Err())
+	}
+}
+
+// work delivers queued events until the queue is closed and drained.
+func (b *memoryBus) work() {
+	for queued := range b.queue {
+		if err := b.deliver(queued.ctx, queued.event); err != nil {
+			b.log.ErrorKV(queued.ctx, err, "event handler failed", map[string]interface{}{
+				"topic": queued.event.Topic,
+			})
+		}
+	}
+}
+
+// deliver calls every handler subscribed to the event's topic, in
+// subscription order, and joins their errors.
+func (b *memoryBus) deliver(ctx context.Context, event appevents.Event) error {
+	b.subsMu.RLock()
+	subs := append([]subscription(nil), b.subs[event.Topic]...)
+	b.subsMu.RUnlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if err := sub.handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	appevents "github.com/next-trace/scg-service-api/application/events"
+	eventsimpl "github.com/next-trace/scg-service-api/infrastructure/events"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBus_SynchronousDelivery(t *testing.T) {
+	ctx := context.Background()
+	bus := eventsimpl.NewMemoryBus(appevents.DefaultConfig(), nil)
+	t.Cleanup(func() { _ = bus.Close(ctx) })
+
+	var got []appevents.Event
+	bus.Subscribe("item.created", func(_ context.Context, event appevents.Event) error {
+		got = append(got, event)
+		return nil
+	})
+	bus.Subscribe("item.deleted", func(context.Context, appevents.Event) error {
+		t.Fatalf("unexpected delivery to another topic")
+		return nil
+	})
+
+	if err := bus.Publish(ctx, appevents.Event{Topic: "item.created", Payload: "42"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Payload != "42" {
+		t.Fatalf("expected the event to be delivered once, got %+v", got)
+	}
+	assert.False(t, got[0].OccurredAt.IsZero(), "Publish should stamp OccurredAt")
+}
+
+func TestMemoryBus_MultipleSubscribers(t *testing.T) {
+	ctx := context.Background()
+	bus := eventsimpl.NewMemoryBus(appevents.DefaultConfig(), nil)
+	t.Cleanup(func() { _ = bus.Close(ctx) })
+
+	var order []string
+	failure := errors.New("projection failed")
+	bus.Subscribe("item.created", func(context.Context, appevents.Event) error {
+		order = append(order, "audit")
+		return nil
+	})
+	unsubscribe := bus.Subscribe("item.created", func(context.Context, appevents.Event) error {
+		order = append(order, "projection")
+		return failure
+	})
+
+	err := bus.Publish(ctx, appevents.Event{Topic: "item.created"})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"audit", "projection"}, order)
+
+	unsubscribe()
+	order = nil
+	assert.NoError(t, bus.Publish(ctx, appevents.Event{Topic: "item.created"}))
+	assert.Equal(t, []string{"audit"}, order)
+}
+
+func TestMemoryBus_WorkerPool(t *testing.T) {
+	ctx := context.Background()
+	cfg := appevents.DefaultConfig()
+	cfg.Workers = 4
+	log := testsupport.NewLogger()
+	bus := eventsimpl.NewMemoryBus(cfg, log)
+
+	var (
+		mu    sync.Mutex
+		count int
+	)
+	for range 2 {
+		bus.Subscribe("item.updated", func(context.Context, appevents.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			count++
+			return nil
+		})
+	}
+	bus.Subscribe("item.failed", func(context.Context, appevents.Event) error {
+		return errors.New("boom")
+	})
+
+	for range 10 {
+		if err := bus.Publish(ctx, appevents.Event{Topic: "item.updated"}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := bus.Publish(ctx, appevents.Event{Topic: "item.failed"}); err != nil {
+		t.Fatalf("expected asynchronous Publish to succeed, got %v", err)
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := bus.Close(closeCtx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 20, count, "every queued event should reach both subscribers before Close returns")
+	assert.Equal(t, "error", log.AssertLogged(t, "event handler failed").Level)
+	assert.ErrorIs(t, bus.Publish(ctx, appevents.Event{Topic: "item.updated"}), eventsimpl.ErrBusClosed)
+}
@@ -10,40 +10,58 @@ package grpc
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
+	"sync"
 
 	appgrpc "github.com/next-trace/scg-service-api/application/grpc"
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
-// Type aliases for gRPC client types
-// These are placeholders for the actual types from the gRPC packages
-// They will be replaced with the actual types when the dependencies are added
-type (
-	// grpcClientConn represents a gRPC client connection
-	grpcClientConn struct{}
+// Ensure clientAdapter implements the optional client capabilities.
+var (
+	_ appgrpc.Pinger            = (*clientAdapter)(nil)
+	_ appgrpc.ConnectionMonitor = (*clientAdapter)(nil)
 )
 
+// errNotConnected is returned by Ping before Connect or after Close.
+var errNotConnected = errors.New("gRPC client is not connected")
+
 // clientAdapter implements the appgrpc.Client interface using the gRPC library.
+// The connection is established lazily and re-established by grpc itself
+// after transient failures, with exponential backoff starting at
+// config.RetryBackoff. Unary calls without a deadline get config.Timeout.
 type clientAdapter struct {
-	conn      *grpcClientConn
-	config    appgrpc.ClientConfig
-	log       applogger.Logger
-	connected bool
+	conn   *grpc.ClientConn
+	config appgrpc.ClientConfig
+	log    applogger.Logger
+	mu     sync.Mutex
 }
 
 // NewClientAdapter creates a new gRPC client adapter.
 func NewClientAdapter(config appgrpc.ClientConfig, log applogger.Logger) appgrpc.Client {
+	log = applogger.OrNop(log)
 	return &clientAdapter{
-		config:    config,
-		log:       log,
-		connected: false,
+		config: config,
+		log:    log,
 	}
 }
 
-// Connect establishes a connection to the gRPC server.
+// Connect creates the connection to the gRPC server. It does not wait for
+// the server to be reachable; use Ping for that.
 func (c *clientAdapter) Connect(ctx context.Context) error {
-	if c.connected {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
 		return nil
 	}
 
@@ -52,84 +70,160 @@ func (c *clientAdapter) Connect(ctx context.Context) error {
 		"timeout": c.config.Timeout,
 	})
 
-	// In a real implementation, this would be:
-	// opts := []grpc.DialOption{
-	//     grpc.WithBlock(),
-	//     grpc.WithTimeout(c.config.Timeout),
-	// }
-	//
-	// if c.config.MaxRecvMsgSize > 0 {
-	//     opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.config.MaxRecvMsgSize)))
-	// }
-	//
-	// if c.config.MaxSendMsgSize > 0 {
-	//     opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(c.config.MaxSendMsgSize)))
-	// }
-	//
-	// if c.config.EnableRetry {
-	//     opts = append(opts, grpc.WithDefaultServiceConfig(`{
-	//         "methodConfig": [{
-	//             "name": [{"service": ""}],
-	//             "retryPolicy": {
-	//                 "maxAttempts": `+strconv.Itoa(c.config.MaxRetryAttempts)+`,
-	//                 "initialBackoff": "`+c.config.RetryBackoff.String()+`",
-	//                 "maxBackoff": "`+(c.config.RetryBackoff * 10).String()+`",
-	//                 "backoffMultiplier": 1.5,
-	//                 "retryableStatusCodes": ["UNAVAILABLE"]
-	//             }
-	//         }]
-	//     }`))
-	// }
-	//
-	// if c.config.EnableTLS {
-	//     creds, err := credentials.NewClientTLSFromFile(c.config.TLSCertPath, "")
-	//     if err != nil {
-	//         return fmt.Errorf("failed to load TLS credentials: %w", err)
-	//     }
-	//     opts = append(opts, grpc.WithTransportCredentials(creds))
-	// } else {
-	//     opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	// }
-	//
-	// conn, err := grpc.DialContext(ctx, c.config.Target, opts...)
-	// if err != nil {
-	//     return fmt.Errorf("failed to connect to gRPC server: %w", err)
-	// }
-	//
-	// c.conn = conn
-
-	// For this mock implementation, we'll just create a dummy connection
-	c.conn = &grpcClientConn{}
-	c.connected = true
-
-	// Simulate connection delay
-	time.Sleep(10 * time.Millisecond)
+	opts, err := c.dialOptions()
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(c.config.Target, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to gRPC server: %w", err)
+	}
+	c.conn = conn
 
 	return nil
 }
 
+// dialOptions builds the grpc dial options from the client configuration.
+func (c *clientAdapter) dialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	if c.config.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.config.MaxRecvMsgSize)))
+	}
+	if c.config.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(c.config.MaxSendMsgSize)))
+	}
+
+	if c.config.Timeout > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(DeadlineUnaryClientInterceptor(c.config.Timeout)))
+	}
+
+	if c.config.RetryBackoff > 0 {
+		params := grpc.ConnectParams{Backoff: backoff.DefaultConfig}
+		params.Backoff.BaseDelay = c.config.RetryBackoff
+		params.Backoff.MaxDelay = c.config.RetryBackoff * 10
+		params.MinConnectTimeout = c.config.Timeout
+		opts = append(opts, grpc.WithConnectParams(params))
+	}
+
+	if c.config.EnableRetry && c.config.MaxRetryAttempts > 1 && c.config.RetryBackoff > 0 {
+		opts = append(opts, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{
+			"methodConfig": [{
+				"name": [{"service": ""}],
+				"retryPolicy": {
+					"maxAttempts": %d,
+					"initialBackoff": "%.3fs",
+					"maxBackoff": "%.3fs",
+					"backoffMultiplier": 1.5,
+					"retryableStatusCodes": ["UNAVAILABLE"]
+				}
+			}]
+		}`, c.config.MaxRetryAttempts, c.config.RetryBackoff.Seconds(), (c.config.RetryBackoff*10).Seconds())))
+	}
+
+	if c.config.EnableTLS {
+		creds, err := credentials.NewClientTLSFromFile(c.config.TLSCertPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	return opts, nil
+}
+
 // Close closes the connection to the gRPC server.
 func (c *clientAdapter) Close() error {
-	if !c.connected {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
 		return nil
 	}
 
-	// In a real implementation, this would be:
-	// err := c.conn.Close()
-	// if err != nil {
-	//     return fmt.Errorf("failed to close gRPC connection: %w", err)
-	// }
-
-	c.connected = false
+	err := c.conn.Close()
 	c.conn = nil
+	if err != nil {
+		return fmt.Errorf("failed to close gRPC connection: %w", err)
+	}
 
 	return nil
 }
 
-// GetConnection returns the underlying connection for use by service clients.
+// GetConnection returns the underlying *grpc.ClientConn for use by service
+// clients, or nil when not connected.
 func (c *clientAdapter) GetConnection() interface{} {
-	if !c.connected {
+	conn := c.connection()
+	if conn == nil {
 		return nil
 	}
+	return conn
+}
+
+// connection returns the current connection, or nil.
+func (c *clientAdapter) connection() *grpc.ClientConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.conn
 }
+
+// Ping checks that the server is reachable and serving, using the standard
+// grpc.health.v1 service. It fails fast while the connection is down. A
+// server without the health service counts as serving once it answers.
+func (c *clientAdapter) Ping(ctx context.Context) error {
+	conn := c.connection()
+	if conn == nil {
+		return errNotConnected
+	}
+
+	resp, err := healthgrpc.NewHealthClient(conn).Check(ctx, &healthgrpc.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gRPC server %s is unreachable: %w", c.config.Target, err)
+	}
+	if resp.GetStatus() != healthgrpc.HealthCheckResponse_SERVING {
+		return fmt.Errorf("gRPC server %s is %s", c.config.Target, resp.GetStatus())
+	}
+	return nil
+}
+
+// MonitorConnection logs every state transition of the connection until ctx
+// is done or the client is closed. When the connection goes idle it asks
+// grpc to reconnect right away instead of on the next call.
+func (c *clientAdapter) MonitorConnection(ctx context.Context) {
+	conn := c.connection()
+	if conn == nil {
+		return
+	}
+
+	state := conn.GetState()
+	for {
+		if state == connectivity.Idle {
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return
+		}
+
+		next := conn.GetState()
+		fields := map[string]interface{}{
+			"target": c.config.Target,
+			"from":   state.String(),
+			"to":     next.String(),
+		}
+		if next == connectivity.TransientFailure {
+			c.log.WarnKV(ctx, "gRPC connection state changed", fields)
+		} else {
+			c.log.InfoKV(ctx, "gRPC connection state changed", fields)
+		}
+		if next == connectivity.Shutdown {
+			return
+		}
+		state = next
+	}
+}
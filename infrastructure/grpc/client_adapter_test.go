@@ -2,12 +2,18 @@ package grpc_test
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
 	appgrpc "github.com/next-trace/scg-service-api/application/grpc"
 	applogger "github.com/next-trace/scg-service-api/application/logger"
 	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // stubLogger is a simple no-op logger implementing the application logger interface
@@ -25,6 +31,7 @@ func (s stubLogger) WarnKV(_ context.Context, _ string, _ map[string]interface{}
 func (s stubLogger) ErrorKV(_ context.Context, _ error, _ string, _ map[string]interface{}) {}
 func (s stubLogger) FatalKV(_ context.Context, _ error, _ string, _ map[string]interface{}) {}
 func (s stubLogger) WithField(_ string, _ interface{}) applogger.Logger                     { return s }
+func (s stubLogger) Flush() error                                                           { return nil }
 
 func TestClientAdapter_ConnectCloseLifecycle(t *testing.T) {
 	cfg := appgrpc.ClientConfig{
@@ -74,3 +81,48 @@ func TestClientAdapter_ConnectCloseLifecycle(t *testing.T) {
 		t.Fatalf("second close error: %v", err)
 	}
 }
+
+func TestClientAdapter_PingReconnects(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Reserve an address with nothing listening on it yet.
+	lc := net.ListenConfig{}
+	lis, err := lc.Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+
+	cfg := appgrpc.DefaultClientConfig()
+	cfg.Target = addr
+	cfg.RetryBackoff = 10 * time.Millisecond
+	log := testsupport.NewLogger()
+	c := infragrpc.NewClientAdapter(cfg, log)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	go c.(appgrpc.ConnectionMonitor).MonitorConnection(ctx)
+
+	pinger := c.(appgrpc.Pinger)
+	if err := pinger.Ping(ctx); err == nil {
+		t.Fatalf("expected Ping to fail while the server is down")
+	}
+
+	lis, err = lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		t.Fatalf("listen again: %v", err)
+	}
+	srv := grpc.NewServer()
+	healthgrpc.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	assert.Eventually(t, func() bool { return pinger.Ping(ctx) == nil }, 5*time.Second, 20*time.Millisecond)
+	if _, ok := log.Find("gRPC connection state changed"); !ok {
+		t.Fatalf("expected the monitor to log state transitions")
+	}
+}
@@ -0,0 +1,44 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestDeadlineUnaryClientInterceptor(t *testing.T) {
+	interceptor := infragrpc.DeadlineUnaryClientInterceptor(5 * time.Second)
+
+	var deadline time.Time
+	var hasDeadline bool
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		deadline, hasDeadline = ctx.Deadline()
+		return nil
+	}
+
+	t.Run("No deadline gets the configured timeout", func(t *testing.T) {
+		start := time.Now()
+		require.NoError(t, interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker))
+		require.True(t, hasDeadline)
+		assert.WithinDuration(t, start.Add(5*time.Second), deadline, time.Second)
+	})
+
+	t.Run("Existing deadline is preserved", func(t *testing.T) {
+		want := time.Now().Add(time.Minute)
+		ctx, cancel := context.WithDeadline(context.Background(), want)
+		defer cancel()
+		require.NoError(t, interceptor(ctx, "/svc/Method", nil, nil, nil, invoker))
+		require.True(t, hasDeadline)
+		assert.Equal(t, want, deadline)
+	})
+
+	t.Run("Non-positive timeout adds no deadline", func(t *testing.T) {
+		require.NoError(t, infragrpc.DeadlineUnaryClientInterceptor(0)(context.Background(), "/svc/Method", nil, nil, nil, invoker))
+		assert.False(t, hasDeadline)
+	})
+}
@@ -1,3 +1,7 @@
 // Package grpc contains adapters for the application/grpc ports. The server adapter
-// supports health status toggling and (in a real implementation) reflection and interceptors.
+// runs a grpc-go server with the standard health service, reflection and the recovery,
+// logging and error-mapping interceptors.
+// The tracing interceptors continue the caller's trace from the incoming metadata
+// using the global OpenTelemetry propagator, which the caller must install.
+// GRPCWebWrapper serves gRPC-Web for browser clients next to the REST API.
 package grpc
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// domainCodes maps the domain error sentinels to gRPC status codes.
+var domainCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{domainerrors.ErrNotFound, codes.NotFound},
+	{domainerrors.ErrAlreadyExists, codes.AlreadyExists},
+	{domainerrors.ErrConflict, codes.Aborted},
+	{domainerrors.ErrPreconditionFailed, codes.FailedPrecondition},
+	{domainerrors.ErrInvalidInput, codes.InvalidArgument},
+	{domainerrors.ErrUnauthorized, codes.Unauthenticated},
+	{domainerrors.ErrForbidden, codes.PermissionDenied},
+	{domainerrors.ErrTooManyRequests, codes.ResourceExhausted},
+	{domainerrors.ErrTimeout, codes.DeadlineExceeded},
+	{domainerrors.ErrUnavailable, codes.Unavailable},
+	{domainerrors.ErrInternal, codes.Internal},
+}
+
+// StatusFromError converts err to a gRPC status. Errors that already carry a
+// status keep it, context errors map to DeadlineExceeded and Canceled, domain
+// errors map to their matching code, and anything else becomes Unknown. A nil
+// err yields an OK status.
+//
+// A DomainError also attaches a google.rpc.ErrorInfo detail whose reason is
+// its Code and whose metadata holds its Details, formatted as strings, so
+// clients can read them with status.Details.
+func StatusFromError(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	}
+	for _, m := range domainCodes {
+		if errors.Is(err, m.err) {
+			return withErrorInfo(status.New(m.code, err.Error()), err)
+		}
+	}
+	return status.New(codes.Unknown, err.Error())
+}
+
+// withErrorInfo attaches the code and details of a DomainError in err to st
+// as an ErrorInfo. st is returned unchanged if err carries neither.
+func withErrorInfo(st *status.Status, err error) *status.Status {
+	var domainErr *domainerrors.DomainError
+	if !errors.As(err, &domainErr) || (domainErr.Code == "" && len(domainErr.Details) == 0) {
+		return st
+	}
+
+	info := &errdetails.ErrorInfo{Reason: domainErr.Code}
+	if len(domainErr.Details) > 0 {
+		info.Metadata = make(map[string]string, len(domainErr.Details))
+		for key, value := range domainErr.Details {
+			info.Metadata[key] = fmt.Sprint(value)
+		}
+	}
+
+	detailed, detailErr := st.WithDetails(info)
+	if detailErr != nil {
+		return st
+	}
+	return detailed
+}
+
+// ErrorMappingUnaryServerInterceptor returns a unary interceptor that converts
+// handler errors to gRPC statuses with StatusFromError.
+func ErrorMappingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, StatusFromError(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// ErrorMappingStreamServerInterceptor returns a stream interceptor that
+// converts handler errors to gRPC statuses with StatusFromError.
+func ErrorMappingStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return StatusFromError(err).Err()
+		}
+		return nil
+	}
+}
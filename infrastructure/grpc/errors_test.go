@@ -0,0 +1,99 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	examplev1 "github.com/next-trace/scg-service-api/gen/v1"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"not found", domainerrors.NewNotFound("item", "42"), codes.NotFound},
+		{"already exists", domainerrors.NewAlreadyExists("item", "42"), codes.AlreadyExists},
+		{"conflict", domainerrors.NewConflict("item", "42", "stale version"), codes.Aborted},
+		{"version mismatch", domainerrors.NewVersionMismatch("item", "42", "1", "2"), codes.FailedPrecondition},
+		{"invalid input", domainerrors.NewInvalidInput("name is required"), codes.InvalidArgument},
+		{"too many requests", domainerrors.NewTooManyRequests(time.Second), codes.ResourceExhausted},
+		{"wrapped sentinel", fmt.Errorf("load: %w", domainerrors.ErrUnavailable), codes.Unavailable},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"existing status", status.Error(codes.FailedPrecondition, "no"), codes.FailedPrecondition},
+		{"unknown", errors.New("boom"), codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := infragrpc.StatusFromError(tt.err).Code(); got != tt.want {
+				t.Fatalf("StatusFromError(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusFromError_ErrorInfo(t *testing.T) {
+	st := infragrpc.StatusFromError(fmt.Errorf("load: %w", domainerrors.NewNotFound("item", "42")))
+	if st.Code() != codes.NotFound {
+		t.Fatalf("expected NotFound, got %s", st.Code())
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = d
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected an ErrorInfo detail, got %v", st.Details())
+	}
+	if info.GetReason() != "not_found" {
+		t.Fatalf("unexpected reason %q", info.GetReason())
+	}
+	if info.GetMetadata()["entity"] != "item" || info.GetMetadata()["id"] != "42" {
+		t.Fatalf("unexpected metadata %v", info.GetMetadata())
+	}
+
+	if details := infragrpc.StatusFromError(errors.New("boom")).Details(); len(details) != 0 {
+		t.Fatalf("expected no details for a plain error, got %v", details)
+	}
+}
+
+// conflictingService fails GetItem with a domain conflict error.
+type conflictingService struct {
+	examplev1.UnimplementedExampleServiceServer
+}
+
+func (conflictingService) GetItem(context.Context, *examplev1.GetItemRequest) (*examplev1.GetItemResponse, error) {
+	return nil, domainerrors.NewConflict("item", "42", "stale version")
+}
+
+func TestErrorMappingInterceptor(t *testing.T) {
+	client := startTestServer(t, conflictingService{},
+		grpc.ChainUnaryInterceptor(infragrpc.ErrorMappingUnaryServerInterceptor()),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "42"})
+	st := status.Convert(err)
+	if st.Code() != codes.Aborted {
+		t.Fatalf("expected Aborted, got %v", err)
+	}
+	if st.Message() != "item with ID 42 conflicts: stale version" {
+		t.Fatalf("unexpected message %q", st.Message())
+	}
+}
@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// GRPCWebContentType is the Content-Type of binary gRPC-Web requests and responses.
+const GRPCWebContentType = "application/grpc-web"
+
+// grpcWebTrailerFlag marks the frame carrying the trailers in a gRPC-Web body.
+const grpcWebTrailerFlag = 0x80
+
+// grpcWebAllowedHeaders are the request headers browsers send with gRPC-Web calls.
+var grpcWebAllowedHeaders = []string{"Content-Type", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout"}
+
+// grpcWebExposedHeaders are the response headers browsers may read.
+var grpcWebExposedHeaders = []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"}
+
+// GRPCWebOption customizes a GRPCWebWrapper.
+type GRPCWebOption func(*GRPCWebWrapper)
+
+// WithGRPCWebAllowedOrigins allows browsers on origins to make gRPC-Web calls,
+// answering their CORS preflights. "*" allows any origin. Without it only
+// same-origin pages can call the server.
+func WithGRPCWebAllowedOrigins(origins ...string) GRPCWebOption {
+	return func(g *GRPCWebWrapper) { g.allowedOrigins = append(g.allowedOrigins, origins...) }
+}
+
+// GRPCWebWrapper serves gRPC-Web, the protocol browsers use to call gRPC
+// services over HTTP/1.1, by translating each call for a gRPC server's
+// ServeHTTP and the response back again. Other requests go to the wrapped
+// handler, so gRPC-Web and the REST API can share one http.Server.
+//
+// The binary application/grpc-web encoding is supported; the base64
+// application/grpc-web-text variant is not.
+type GRPCWebWrapper struct {
+	server         http.Handler
+	allowedOrigins []string
+}
+
+// NewGRPCWebWrapper creates a wrapper serving gRPC-Web calls with server,
+// usually a *grpc.Server.
+func NewGRPCWebWrapper(server http.Handler, opts ...GRPCWebOption) *GRPCWebWrapper {
+	g := &GRPCWebWrapper{server: server}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(g)
+		}
+	}
+	return g
+}
+
+// Middleware returns an http.Handler middleware function; pass it to
+// apphttp.WithMiddleware to serve gRPC-Web from Run.
+func (g *GRPCWebWrapper) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case IsGRPCWebRequest(r):
+				g.setCORSHeaders(w, r)
+				g.serveGRPCWeb(w, r)
+			case isGRPCWebPreflight(r):
+				g.setCORSHeaders(w, r)
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// IsGRPCWebRequest reports whether r is a binary gRPC-Web call.
+func IsGRPCWebRequest(r *http.Request) bool {
+	contentType := r.Header.Get("Content-Type")
+	return r.Method == http.MethodPost &&
+		(contentType == GRPCWebContentType || strings.HasPrefix(contentType, GRPCWebContentType+"+"))
+}
+
+// isGRPCWebPreflight reports whether r is the CORS preflight of a gRPC-Web call.
+func isGRPCWebPreflight(r *http.Request) bool {
+	if r.Method != http.MethodOptions || r.Header.Get("Origin") == "" {
+		return false
+	}
+	for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		if strings.EqualFold(strings.TrimSpace(header), "x-grpc-web") {
+			return true
+		}
+	}
+	return false
+}
+
+// setCORSHeaders allows the request's origin if it is configured.
+func (g *GRPCWebWrapper) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || (!slices.Contains(g.allowedOrigins, "*") && !slices.Contains(g.allowedOrigins, origin)) {
+		return
+	}
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Add("Vary", "Origin")
+	h.Set("Access-Control-Allow-Methods", http.MethodPost)
+	h.Set("Access-Control-Allow-Headers", strings.Join(grpcWebAllowedHeaders, ", "))
+	h.Set("Access-Control-Expose-Headers", strings.Join(grpcWebExposedHeaders, ", "))
+}
+
+// serveGRPCWeb presents r to the gRPC server as an HTTP/2 gRPC call and
+// writes its response in gRPC-Web framing.
+func (g *GRPCWebWrapper) serveGRPCWeb(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2"
+	req.Header.Set("Content-Type", "application/grpc"+strings.TrimPrefix(contentType, GRPCWebContentType))
+	req.Header.Del("Content-Length")
+
+	resp := &grpcWebResponse{w: w, header: make(http.Header), contentType: contentType}
+	g.server.ServeHTTP(resp, req)
+	resp.finish()
+}
+
+// grpcWebResponse is the http.ResponseWriter given to the gRPC server. It
+// passes headers and messages through and, once the call ends, writes the
+// trailers as the final frame of the body, where gRPC-Web clients expect them.
+type grpcWebResponse struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	wroteHeader bool
+}
+
+// Header returns the headers and trailers set by the gRPC server.
+func (rw *grpcWebResponse) Header() http.Header {
+	return rw.header
+}
+
+// WriteHeader copies the headers, except the trailers, and writes the status.
+func (rw *grpcWebResponse) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	trailers := rw.trailerNames()
+	h := rw.w.Header()
+	for k, vv := range rw.header {
+		if k == "Trailer" || slices.Contains(trailers, k) || strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+		h[k] = vv
+	}
+	h.Set("Content-Type", rw.contentType)
+	h.Del("Content-Length")
+	rw.w.WriteHeader(statusCode)
+}
+
+// Write writes a framed message.
+func (rw *grpcWebResponse) Write(b []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	return rw.w.Write(b)
+}
+
+// Flush sends buffered data to the client; the gRPC server requires it.
+func (rw *grpcWebResponse) Flush() {
+	rw.WriteHeader(http.StatusOK)
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// trailerNames returns the canonical names of the trailers the server declared.
+func (rw *grpcWebResponse) trailerNames() []string {
+	var names []string
+	for _, v := range rw.header.Values("Trailer") {
+		for _, name := range strings.Split(v, ",") {
+			names = append(names, http.CanonicalHeaderKey(strings.TrimSpace(name)))
+		}
+	}
+	return names
+}
+
+// finish writes the trailer frame: a flag byte, a big-endian length and the
+// trailers as lower-case HTTP/1 header lines.
+func (rw *grpcWebResponse) finish() {
+	rw.WriteHeader(http.StatusOK)
+
+	trailers := make(http.Header)
+	for _, name := range rw.trailerNames() {
+		if vv := rw.header.Values(name); len(vv) > 0 {
+			trailers[strings.ToLower(name)] = vv
+		}
+	}
+	for k, vv := range rw.header {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			trailers[strings.ToLower(name)] = append(trailers[strings.ToLower(name)], vv...)
+		}
+	}
+
+	var body bytes.Buffer
+	for k, vv := range trailers {
+		for _, v := range vv {
+			body.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	frame := make([]byte, 5, 5+body.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(body.Len()))
+	_, _ = rw.w.Write(append(frame, body.Bytes()...))
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,133 @@
+package grpc_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// startGRPCWebServer serves the health service over gRPC-Web in front of a
+// REST handler answering "rest".
+func startGRPCWebServer(t *testing.T, opts ...infragrpc.GRPCWebOption) *httptest.Server {
+	t.Helper()
+	s := grpc.NewServer()
+	healthgrpc.RegisterHealthServer(s, health.NewServer())
+	rest := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { _, _ = io.WriteString(w, "rest") })
+
+	srv := httptest.NewServer(infragrpc.NewGRPCWebWrapper(s, opts...).Middleware()(rest))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// grpcWebCall sends req as a gRPC-Web call and returns the response's message
+// frames and its trailers.
+func grpcWebCall(t *testing.T, url string, req proto.Message) (*http.Response, [][]byte, http.Header) {
+	t.Helper()
+	payload, err := proto.Marshal(req)
+	require.NoError(t, err)
+	body := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(payload)))
+	body = append(body, payload...)
+
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	httpReq.Header.Set("X-Grpc-Web", "1")
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var messages [][]byte
+	trailers := make(http.Header)
+	for len(raw) > 0 {
+		require.GreaterOrEqual(t, len(raw), 5, "truncated frame header")
+		n := int(binary.BigEndian.Uint32(raw[1:5]))
+		require.GreaterOrEqual(t, len(raw), 5+n, "truncated frame")
+		flag, frame := raw[0], raw[5:5+n]
+		raw = raw[5+n:]
+		if flag&0x80 == 0 {
+			messages = append(messages, frame)
+			continue
+		}
+		assert.Empty(t, raw, "the trailer frame must be last")
+		for _, line := range strings.Split(strings.TrimSpace(string(frame)), "\r\n") {
+			k, v, ok := strings.Cut(line, ": ")
+			require.True(t, ok, "malformed trailer %q", line)
+			trailers.Add(k, v)
+		}
+	}
+	return resp, messages, trailers
+}
+
+func TestGRPCWebWrapper_UnaryCall(t *testing.T) {
+	srv := startGRPCWebServer(t)
+
+	resp, messages, trailers := grpcWebCall(t, srv.URL+"/grpc.health.v1.Health/Check", &healthgrpc.HealthCheckRequest{})
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/grpc-web+proto", resp.Header.Get("Content-Type"))
+	require.Len(t, messages, 1)
+	var out healthgrpc.HealthCheckResponse
+	require.NoError(t, proto.Unmarshal(messages[0], &out))
+	assert.Equal(t, healthgrpc.HealthCheckResponse_SERVING, out.GetStatus())
+	assert.Equal(t, "0", trailers.Get("Grpc-Status"))
+}
+
+func TestGRPCWebWrapper_ErrorStatus(t *testing.T) {
+	srv := startGRPCWebServer(t)
+
+	_, messages, trailers := grpcWebCall(t, srv.URL+"/grpc.health.v1.Health/Check", &healthgrpc.HealthCheckRequest{Service: "missing"})
+
+	assert.Empty(t, messages)
+	assert.Equal(t, "5", trailers.Get("Grpc-Status"), "codes.NotFound")
+	assert.NotEmpty(t, trailers.Get("Grpc-Message"))
+}
+
+func TestGRPCWebWrapper_PassesOtherRequests(t *testing.T) {
+	srv := startGRPCWebServer(t)
+
+	resp, err := http.Get(srv.URL + "/items")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "rest", string(body))
+}
+
+func TestGRPCWebWrapper_CORS(t *testing.T) {
+	srv := startGRPCWebServer(t, infragrpc.WithGRPCWebAllowedOrigins("https://app.example.com"))
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodOptions, srv.URL+"/grpc.health.v1.Health/Check", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	resp := preflight("https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "X-Grpc-Web")
+	assert.Contains(t, resp.Header.Get("Access-Control-Expose-Headers"), "Grpc-Status")
+
+	resp = preflight("https://evil.example.com")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
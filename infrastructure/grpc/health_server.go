@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	apphealth "github.com/next-trace/scg-service-api/application/health"
+	infrahealth "github.com/next-trace/scg-service-api/infrastructure/health"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegistryHealthServer serves grpc.health.v1 from the application health registry.
+// The overall status (empty service name) is evaluated from the readiness checks
+// on every Check call, so gRPC and HTTP readiness always agree. Named services
+// are served by the embedded standard health server.
+type RegistryHealthServer struct {
+	*health.Server
+	aggregator *infrahealth.Aggregator
+}
+
+// Ensure RegistryHealthServer implements the grpc_health_v1.HealthServer interface.
+var _ healthgrpc.HealthServer = (*RegistryHealthServer)(nil)
+
+// NewRegistryHealthServer creates a gRPC health server backed by aggregator.
+// Pass the same aggregator (or registry) used by the HTTP health handler.
+func NewRegistryHealthServer(aggregator *infrahealth.Aggregator) *RegistryHealthServer {
+	return &RegistryHealthServer{
+		Server:     health.NewServer(),
+		aggregator: aggregator,
+	}
+}
+
+// Check reports the health of the requested service. The overall status is
+// SERVING unless a readiness check is DOWN; DEGRADED still serves traffic,
+// matching the HTTP readiness endpoint.
+func (s *RegistryHealthServer) Check(ctx context.Context, req *healthgrpc.HealthCheckRequest) (*healthgrpc.HealthCheckResponse, error) {
+	if req.GetService() == "" {
+		s.Refresh(ctx)
+	}
+	return s.Server.Check(ctx, req)
+}
+
+// Watch streams status changes for the requested service. For the overall
+// status the readiness checks are evaluated first, so the initial response
+// reflects the registry; later changes are pushed when Refresh or Poll
+// observes a different status.
+func (s *RegistryHealthServer) Watch(req *healthgrpc.HealthCheckRequest, stream healthgrpc.Health_WatchServer) error {
+	if req.GetService() == "" {
+		s.Refresh(stream.Context())
+	}
+	return s.Server.Watch(req, stream)
+}
+
+// Poll refreshes the overall status every interval until ctx is done, so that
+// Watch subscribers are notified when a readiness check changes state without
+// anyone calling Check. It blocks and is meant to run in its own goroutine.
+func (s *RegistryHealthServer) Poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Refresh evaluates the readiness checks and publishes the overall status.
+func (s *RegistryHealthServer) Refresh(ctx context.Context) healthgrpc.HealthCheckResponse_ServingStatus {
+	status := servingStatus(s.aggregator.Evaluate(ctx, apphealth.CheckTypeReadiness).Status)
+	s.SetServingStatus("", status)
+	return status
+}
+
+// servingStatus maps an application health status to a gRPC serving status.
+func servingStatus(status apphealth.Status) healthgrpc.HealthCheckResponse_ServingStatus {
+	if status == apphealth.StatusDown {
+		return healthgrpc.HealthCheckResponse_NOT_SERVING
+	}
+	return healthgrpc.HealthCheckResponse_SERVING
+}
@@ -0,0 +1,132 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apphealth "github.com/next-trace/scg-service-api/application/health"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	infrahealth "github.com/next-trace/scg-service-api/infrastructure/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startHealthServer serves srv over an in-memory listener and returns a connected health client.
+func startHealthServer(t *testing.T, srv healthgrpc.HealthServer) healthgrpc.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	healthgrpc.RegisterHealthServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthgrpc.NewHealthClient(conn)
+}
+
+func TestRegistryHealthServer_MatchesHTTPReadiness(t *testing.T) {
+	var dbUp atomic.Bool
+	dbUp.Store(true)
+
+	reg := infrahealth.NewRegistry()
+	reg.RegisterCheck("db", apphealth.CheckTypeReadiness, func(_ context.Context) apphealth.Result {
+		status := apphealth.StatusDown
+		if dbUp.Load() {
+			status = apphealth.StatusUp
+		}
+		return apphealth.Result{Status: status, Component: "db", Timestamp: time.Now()}
+	})
+
+	cfg := apphealth.DefaultConfig()
+	aggregator := infrahealth.NewAggregator(reg, cfg.Timeout)
+	httpHandler := infrahealth.NewHTTPHandlerWithAggregator(aggregator, cfg, stubLogger{})
+	readiness := httpHandler.ReadinessHandler().(http.Handler)
+	client := startHealthServer(t, infragrpc.NewRegistryHealthServer(aggregator))
+
+	check := func() (int, healthgrpc.HealthCheckResponse_ServingStatus) {
+		w := httptest.NewRecorder()
+		readiness.ServeHTTP(w, httptest.NewRequest(http.MethodGet, cfg.ReadinessPath, nil))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := client.Check(ctx, &healthgrpc.HealthCheckRequest{})
+		if err != nil {
+			t.Fatalf("grpc check: %v", err)
+		}
+		return w.Code, resp.GetStatus()
+	}
+
+	code, status := check()
+	if code != http.StatusOK || status != healthgrpc.HealthCheckResponse_SERVING {
+		t.Fatalf("expected both healthy, got HTTP %d and gRPC %v", code, status)
+	}
+
+	dbUp.Store(false)
+
+	code, status = check()
+	if code != http.StatusServiceUnavailable || status != healthgrpc.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected both unhealthy, got HTTP %d and gRPC %v", code, status)
+	}
+}
+
+func TestRegistryHealthServer_WatchPushesStatusChanges(t *testing.T) {
+	var dbUp atomic.Bool
+	dbUp.Store(true)
+
+	reg := infrahealth.NewRegistry()
+	reg.RegisterCheck("db", apphealth.CheckTypeReadiness, func(_ context.Context) apphealth.Result {
+		status := apphealth.StatusDown
+		if dbUp.Load() {
+			status = apphealth.StatusUp
+		}
+		return apphealth.Result{Status: status, Component: "db", Timestamp: time.Now()}
+	})
+
+	srv := infragrpc.NewRegistryHealthServer(infrahealth.NewAggregator(reg, time.Second))
+	client := startHealthServer(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go srv.Poll(ctx, 10*time.Millisecond)
+
+	stream, err := client.Watch(ctx, &healthgrpc.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv initial status: %v", err)
+	}
+	if resp.GetStatus() != healthgrpc.HealthCheckResponse_SERVING {
+		t.Fatalf("expected initial SERVING, got %v", resp.GetStatus())
+	}
+
+	dbUp.Store(false)
+
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("recv status change: %v", err)
+	}
+	if resp.GetStatus() != healthgrpc.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected pushed NOT_SERVING, got %v", resp.GetStatus())
+	}
+}
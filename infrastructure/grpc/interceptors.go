@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryUnaryServerInterceptor returns a unary interceptor that converts a
+// handler panic into a codes.Internal error so the server keeps serving.
+func RecoveryUnaryServerInterceptor(log applogger.Logger) grpc.UnaryServerInterceptor {
+	log = applogger.OrNop(log)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ctx, log, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor returns a stream interceptor that converts a
+// handler panic into a codes.Internal status on that stream only. Other streams
+// and subsequent calls are unaffected.
+func RecoveryStreamServerInterceptor(log applogger.Logger) grpc.StreamServerInterceptor {
+	log = applogger.OrNop(log)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(ss.Context(), log, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recoverPanic logs a recovered panic and returns the status error sent to the client.
+func recoverPanic(ctx context.Context, log applogger.Logger, method string, r interface{}) error {
+	log.ErrorKV(ctx, fmt.Errorf("panic: %v", r), "gRPC panic recovered", map[string]interface{}{
+		"method": method,
+		"stack":  string(debug.Stack()),
+	})
+
+	return status.Errorf(codes.Internal, "internal server error")
+}
@@ -0,0 +1,95 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	examplev1 "github.com/next-trace/scg-service-api/gen/v1"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// panickingService panics in StreamItems when asked for tag "panic".
+type panickingService struct {
+	examplev1.UnimplementedExampleServiceServer
+}
+
+func (panickingService) StreamItems(req *examplev1.StreamItemsRequest, stream examplev1.ExampleService_StreamItemsServer) error {
+	if len(req.GetTags()) > 0 && req.GetTags()[0] == "panic" {
+		panic("boom")
+	}
+	return stream.Send(&examplev1.ItemUpdate{})
+}
+
+func (panickingService) GetItem(_ context.Context, req *examplev1.GetItemRequest) (*examplev1.GetItemResponse, error) {
+	if req.GetId() == "panic" {
+		panic("boom")
+	}
+	return &examplev1.GetItemResponse{}, nil
+}
+
+// startTestServer serves svc over an in-memory listener and returns a connected client.
+func startTestServer(t *testing.T, svc examplev1.ExampleServiceServer, opts ...grpc.ServerOption) examplev1.ExampleServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(opts...)
+	examplev1.RegisterExampleServiceServer(srv, svc)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return examplev1.NewExampleServiceClient(conn)
+}
+
+func TestRecoveryInterceptors_IsolatePanics(t *testing.T) {
+	client := startTestServer(t, panickingService{},
+		grpc.ChainUnaryInterceptor(infragrpc.RecoveryUnaryServerInterceptor(stubLogger{})),
+		grpc.ChainStreamInterceptor(infragrpc.RecoveryStreamServerInterceptor(stubLogger{})),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A panicking stream handler fails only that stream with Internal.
+	stream, err := client.StreamItems(ctx, &examplev1.StreamItemsRequest{Tags: []string{"panic"}})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal from panicking stream, got %v", err)
+	}
+
+	// A panicking unary handler is converted the same way.
+	if _, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "panic"}); status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal from panicking unary call, got %v", err)
+	}
+
+	// The server keeps serving subsequent calls.
+	stream, err = client.StreamItems(ctx, &examplev1.StreamItemsRequest{})
+	if err != nil {
+		t.Fatalf("open second stream: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("expected healthy stream after panic, got %v", err)
+	}
+	if _, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "ok"}); err != nil {
+		t.Fatalf("expected healthy unary call after panic, got %v", err)
+	}
+}
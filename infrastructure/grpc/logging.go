@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// LoggingUnaryServerInterceptor returns a unary interceptor that logs one
+// entry per completed call with the method, status code, duration and the
+// request and response sizes. Calls ending in codes.OK are logged at info,
+// everything else at error.
+//
+// For auditing, each entry also carries the client's "peer_address" and the
+// authenticated "subject": the user ID an earlier auth interceptor stored
+// with reqmeta.WithUserID, else the common name of a verified TLS client
+// certificate, else "anonymous". Client-supplied metadata is never trusted
+// as the subject.
+func LoggingUnaryServerInterceptor(log applogger.Logger) grpc.UnaryServerInterceptor {
+	log = applogger.OrNop(log)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		fields := map[string]interface{}{
+			"request_bytes":  messageSize(req),
+			"response_bytes": messageSize(resp),
+		}
+		logCall(ctx, log, info.FullMethod, start, err, fields)
+		return resp, err
+	}
+}
+
+// LoggingStreamServerInterceptor is the streaming counterpart of
+// LoggingUnaryServerInterceptor. It logs when the stream ends, with the number
+// and total size of messages received and sent.
+func LoggingStreamServerInterceptor(log applogger.Logger) grpc.StreamServerInterceptor {
+	log = applogger.OrNop(log)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		counted := &countingServerStream{ServerStream: ss}
+		err := handler(srv, counted)
+
+		fields := map[string]interface{}{
+			"messages_received": counted.received,
+			"messages_sent":     counted.sent,
+			"request_bytes":     counted.receivedBytes,
+			"response_bytes":    counted.sentBytes,
+		}
+		logCall(ss.Context(), log, info.FullMethod, start, err, fields)
+		return err
+	}
+}
+
+// logCall adds the common fields and writes the access log entry.
+func logCall(ctx context.Context, log applogger.Logger, method string, start time.Time, err error, fields map[string]interface{}) {
+	code := status.Code(err)
+	fields["method"] = method
+	fields["code"] = code.String()
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["peer_address"] = p.Addr.String()
+	}
+	fields["subject"] = authSubject(ctx)
+
+	if code == codes.OK {
+		log.InfoKV(ctx, "gRPC call", fields)
+		return
+	}
+	log.ErrorKV(ctx, err, "gRPC call", fields)
+}
+
+// anonymousSubject is logged for calls without an authenticated caller.
+const anonymousSubject = "anonymous"
+
+// authSubject returns the authenticated caller of the RPC, or anonymousSubject.
+func authSubject(ctx context.Context) string {
+	if userID := reqmeta.UserID(ctx); userID != "" {
+		return userID
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			if cn := tlsInfo.State.PeerCertificates[0].Subject.CommonName; cn != "" {
+				return cn
+			}
+		}
+	}
+	return anonymousSubject
+}
+
+// messageSize returns the encoded size of a protobuf message, or 0.
+func messageSize(msg interface{}) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// countingServerStream counts the messages passing through a stream.
+type countingServerStream struct {
+	grpc.ServerStream
+	sent, received           int
+	sentBytes, receivedBytes int
+}
+
+// SendMsg counts and forwards an outgoing message.
+func (s *countingServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+		s.sentBytes += messageSize(m)
+	}
+	return err
+}
+
+// RecvMsg forwards and counts an incoming message.
+func (s *countingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+		s.receivedBytes += messageSize(m)
+	}
+	return err
+}
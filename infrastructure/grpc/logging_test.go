@@ -0,0 +1,115 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	examplev1 "github.com/next-trace/scg-service-api/gen/v1"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// notFoundService fails GetItem for id "missing" and streams one update.
+type notFoundService struct {
+	examplev1.UnimplementedExampleServiceServer
+}
+
+func (notFoundService) GetItem(_ context.Context, req *examplev1.GetItemRequest) (*examplev1.GetItemResponse, error) {
+	if req.GetId() == "missing" {
+		return nil, status.Error(codes.NotFound, "item not found")
+	}
+	return &examplev1.GetItemResponse{}, nil
+}
+
+func (notFoundService) StreamItems(_ *examplev1.StreamItemsRequest, stream examplev1.ExampleService_StreamItemsServer) error {
+	return stream.Send(&examplev1.ItemUpdate{})
+}
+
+func TestLoggingInterceptors(t *testing.T) {
+	log := testsupport.NewLogger()
+	client := startTestServer(t, notFoundService{},
+		grpc.ChainUnaryInterceptor(infragrpc.LoggingUnaryServerInterceptor(log)),
+		grpc.ChainStreamInterceptor(infragrpc.LoggingStreamServerInterceptor(log)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "ok"}); err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	if _, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	stream, err := client.StreamItems(ctx, &examplev1.StreamItemsRequest{})
+	if err != nil {
+		t.Fatalf("StreamItems: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+
+	// The stream entry is written after the client sees EOF, so wait for it.
+	assert.Eventually(t, func() bool { return len(log.Entries()) == 3 }, time.Second, 5*time.Millisecond)
+	entries := log.Entries()
+
+	ok, failed, streamed := entries[0], entries[1], entries[2]
+	assert.Equal(t, "info", ok.Level)
+	assert.Equal(t, "OK", ok.Fields["code"])
+	assert.Equal(t, "/example.v1.ExampleService/GetItem", ok.Fields["method"])
+	assert.Equal(t, 4, ok.Fields["request_bytes"])
+
+	assert.Equal(t, "error", failed.Level)
+	assert.Equal(t, "NotFound", failed.Fields["code"])
+	assert.Equal(t, codes.NotFound, status.Code(failed.Err))
+
+	assert.Equal(t, "info", streamed.Level)
+	assert.Equal(t, 1, streamed.Fields["messages_sent"])
+}
+
+func TestLoggingInterceptors_PeerAndSubject(t *testing.T) {
+	log := testsupport.NewLogger()
+	// authenticate stands in for an auth interceptor: it accepts one bearer
+	// token and stores the caller's user ID in the context.
+	authenticate := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 && values[0] == "Bearer token-42" {
+			ctx = reqmeta.WithUserID(ctx, "user-42")
+		}
+		return handler(ctx, req)
+	}
+	client := startTestServer(t, notFoundService{},
+		grpc.ChainUnaryInterceptor(authenticate, infragrpc.LoggingUnaryServerInterceptor(log)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, md := range []metadata.MD{
+		nil,
+		metadata.Pairs("x-auth-subject", "admin"),
+		metadata.Pairs("authorization", "Bearer token-42"),
+	} {
+		if _, err := client.GetItem(metadata.NewOutgoingContext(ctx, md), &examplev1.GetItemRequest{Id: "ok"}); err != nil {
+			t.Fatalf("GetItem: %v", err)
+		}
+	}
+
+	entries := log.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	// bufconn reports its listener address as "bufconn".
+	assert.Equal(t, "bufconn", entries[0].Fields["peer_address"])
+	assert.Equal(t, "anonymous", entries[0].Fields["subject"])
+	assert.Equal(t, "anonymous", entries[1].Fields["subject"], "a client-supplied subject must not be trusted")
+	assert.Equal(t, "user-42", entries[2].Fields["subject"])
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultMetadataFields maps the incoming metadata keys propagated by default
+// to the log field names they are stored under.
+var DefaultMetadataFields = map[string]string{
+	"x-request-id": "request_id",
+	"x-tenant-id":  "tenant_id",
+}
+
+// MetadataToContext copies the configured incoming metadata keys into ctx as
+// log fields (see applogger.ContextWithFields), so every entry logged with the
+// returned context carries them. fields maps metadata keys to field names;
+// keys absent from the metadata are skipped and the first value wins when a
+// key is repeated.
+func MetadataToContext(ctx context.Context, fields map[string]string) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	values := make(map[string]interface{}, len(fields))
+	for key, field := range fields {
+		if v := md.Get(key); len(v) > 0 && v[0] != "" {
+			values[field] = v[0]
+		}
+	}
+	return applogger.ContextWithFields(ctx, values)
+}
+
+// MetadataUnaryServerInterceptor returns a unary interceptor that applies
+// MetadataToContext before calling the handler. A nil fields map uses
+// DefaultMetadataFields.
+func MetadataUnaryServerInterceptor(fields map[string]string) grpc.UnaryServerInterceptor {
+	if fields == nil {
+		fields = DefaultMetadataFields
+	}
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(MetadataToContext(ctx, fields), req)
+	}
+}
+
+// MetadataStreamServerInterceptor is the streaming counterpart of
+// MetadataUnaryServerInterceptor; the handler sees the enriched context via
+// stream.Context().
+func MetadataStreamServerInterceptor(fields map[string]string) grpc.StreamServerInterceptor {
+	if fields == nil {
+		fields = DefaultMetadataFields
+	}
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: MetadataToContext(ss.Context(), fields)})
+	}
+}
+
+// contextServerStream overrides the context of a grpc.ServerStream.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the overridden context.
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
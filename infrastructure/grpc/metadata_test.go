@@ -0,0 +1,63 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	examplev1 "github.com/next-trace/scg-service-api/gen/v1"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// fieldCapturingService records the log fields visible in each handler's context.
+type fieldCapturingService struct {
+	examplev1.UnimplementedExampleServiceServer
+	unary  chan map[string]interface{}
+	stream chan map[string]interface{}
+}
+
+func (s fieldCapturingService) GetItem(ctx context.Context, _ *examplev1.GetItemRequest) (*examplev1.GetItemResponse, error) {
+	s.unary <- applogger.FieldsFromContext(ctx)
+	return &examplev1.GetItemResponse{}, nil
+}
+
+func (s fieldCapturingService) StreamItems(_ *examplev1.StreamItemsRequest, stream examplev1.ExampleService_StreamItemsServer) error {
+	s.stream <- applogger.FieldsFromContext(stream.Context())
+	return nil
+}
+
+func TestMetadataInterceptors_PropagateToContext(t *testing.T) {
+	svc := fieldCapturingService{
+		unary:  make(chan map[string]interface{}, 1),
+		stream: make(chan map[string]interface{}, 1),
+	}
+	client := startTestServer(t, svc,
+		grpc.ChainUnaryInterceptor(infragrpc.MetadataUnaryServerInterceptor(nil)),
+		grpc.ChainStreamInterceptor(infragrpc.MetadataStreamServerInterceptor(nil)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-1", "x-tenant-id", "acme", "x-other", "ignored")
+
+	if _, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "1"}); err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	assert.Equal(t, map[string]interface{}{"request_id": "req-1", "tenant_id": "acme"}, <-svc.unary)
+
+	stream, err := client.StreamItems(ctx, &examplev1.StreamItemsRequest{})
+	if err != nil {
+		t.Fatalf("StreamItems: %v", err)
+	}
+	_, _ = stream.Recv()
+	assert.Equal(t, map[string]interface{}{"request_id": "req-1", "tenant_id": "acme"}, <-svc.stream)
+}
+
+func TestMetadataToContext_NoMetadata(t *testing.T) {
+	ctx := infragrpc.MetadataToContext(context.Background(), infragrpc.DefaultMetadataFields)
+	assert.Nil(t, applogger.FieldsFromContext(ctx))
+}
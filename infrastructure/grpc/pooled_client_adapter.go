@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	appgrpc "github.com/next-trace/scg-service-api/application/grpc"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// Ensure pooledClientAdapter implements the appgrpc.Client interface and the
+// optional client capabilities.
+var (
+	_ appgrpc.Client            = (*pooledClientAdapter)(nil)
+	_ appgrpc.Pinger            = (*pooledClientAdapter)(nil)
+	_ appgrpc.ConnectionMonitor = (*pooledClientAdapter)(nil)
+)
+
+// pooledClientAdapter implements the appgrpc.Client interface over a fixed pool
+// of connections to the same target. GetConnection hands out the connections
+// round-robin so concurrent callers spread their load across the pool.
+type pooledClientAdapter struct {
+	clients []appgrpc.Client
+	next    atomic.Uint64
+	config  appgrpc.ClientConfig
+	log     applogger.Logger
+	mu      sync.Mutex
+}
+
+// NewPooledClientAdapter creates a gRPC client that keeps config.PoolSize
+// connections to config.Target.
+func NewPooledClientAdapter(config appgrpc.ClientConfig, log applogger.Logger) appgrpc.Client {
+	log = applogger.OrNop(log)
+	size := config.PoolSize
+	if size < 1 {
+		size = 1
+	}
+
+	clients := make([]appgrpc.Client, size)
+	for i := range clients {
+		clients[i] = NewClientAdapter(config, log)
+	}
+
+	return &pooledClientAdapter{
+		clients: clients,
+		config:  config,
+		log:     log,
+	}
+}
+
+// Connect establishes every connection in the pool.
+// If any connection fails, the ones already established are closed.
+func (p *pooledClientAdapter) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, c := range p.clients {
+		if err := c.Connect(ctx); err != nil {
+			for _, opened := range p.clients[:i] {
+				_ = opened.Close()
+			}
+			return fmt.Errorf("failed to connect pooled connection %d: %w", i, err)
+		}
+	}
+
+	p.log.InfoKV(ctx, "gRPC connection pool ready", map[string]interface{}{
+		"target": p.config.Target,
+		"size":   len(p.clients),
+	})
+
+	return nil
+}
+
+// Close closes every connection in the pool.
+func (p *pooledClientAdapter) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for _, c := range p.clients {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetConnection returns the next connection in round-robin order,
+// or nil if the pool is not connected.
+func (p *pooledClientAdapter) GetConnection() interface{} {
+	n := p.next.Add(1) - 1
+	return p.clients[n%uint64(len(p.clients))].GetConnection()
+}
+
+// Ping pings every connection in the pool and joins their errors.
+func (p *pooledClientAdapter) Ping(ctx context.Context) error {
+	var errs []error
+	for i, c := range p.clients {
+		pinger, ok := c.(appgrpc.Pinger)
+		if !ok {
+			continue
+		}
+		if err := pinger.Ping(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("pooled connection %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MonitorConnection monitors every connection in the pool until ctx is done
+// or the pool is closed.
+func (p *pooledClientAdapter) MonitorConnection(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range p.clients {
+		if monitor, ok := c.(appgrpc.ConnectionMonitor); ok {
+			wg.Go(func() { monitor.MonitorConnection(ctx) })
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,50 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	appgrpc "github.com/next-trace/scg-service-api/application/grpc"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+)
+
+func TestPooledClientAdapter_RoundRobin(t *testing.T) {
+	cfg := appgrpc.DefaultClientConfig()
+	cfg.PoolSize = 3
+	c := infragrpc.NewPooledClientAdapter(cfg, stubLogger{})
+
+	if got := c.GetConnection(); got != nil {
+		t.Fatalf("expected nil connection before connect, got %T", got)
+	}
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	seen := make([]interface{}, 0, 6)
+	for range 6 {
+		seen = append(seen, c.GetConnection())
+	}
+
+	// The first three are distinct and the sequence then repeats.
+	for i := range 3 {
+		if seen[i] == nil {
+			t.Fatalf("connection %d is nil", i)
+		}
+		for j := range i {
+			if seen[i] == seen[j] {
+				t.Fatalf("connections %d and %d are the same", i, j)
+			}
+		}
+		if seen[i] != seen[i+3] {
+			t.Fatalf("expected round-robin to cycle back to connection %d", i)
+		}
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if got := c.GetConnection(); got != nil {
+		t.Fatalf("expected nil connection after close, got %T", got)
+	}
+}
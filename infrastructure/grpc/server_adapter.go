@@ -1,10 +1,7 @@
 // Package grpc provides gRPC server and client implementations.
 //
-// Note: This package requires the following dependencies:
-// - google.golang.org/grpc
-// - google.golang.org/grpc/health
-// - google.golang.org/grpc/health/grpc_health_v1
-// - google.golang.org/grpc/reflection
+// The package is built on google.golang.org/grpc, including its health and
+// reflection services, which the module always requires.
 //
 // See docs/dependencies.md for more information.
 package grpc
@@ -13,107 +10,62 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
 	appgrpc "github.com/next-trace/scg-service-api/application/grpc"
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 // Ensure serverAdapter implements the appgrpc.Server interface.
 var _ appgrpc.Server = (*serverAdapter)(nil)
 
-// Type aliases for gRPC types
-// These are placeholders for the actual types from the gRPC packages
-// They will be replaced with the actual types when the dependencies are added
-type (
-	// grpcServer represents the gRPC server
-	grpcServer struct{}
-
-	// healthServer represents the gRPC health server
-	healthServer struct{}
-
-	// healthCheckResponse represents a health check response
-	healthCheckResponse int
-)
-
-// Constants for health check response
-const (
-	// HealthCheckResponseServing indicates the service is serving
-	HealthCheckResponseServing healthCheckResponse = 1
-
-	// HealthCheckResponseNotServing indicates the service is not serving
-	HealthCheckResponseNotServing healthCheckResponse = 2
-)
-
-// Mock methods for the grpcServer type
-func (s *grpcServer) Serve(listener net.Listener) error {
-	// In a real implementation, this would start the gRPC server
-	_ = listener
-	return nil
-}
-
-func (s *grpcServer) GracefulStop() {
-	// In a real implementation, this would gracefully stop the gRPC server
-}
-
-// Mock methods for the healthServer type
-func (s *healthServer) SetServingStatus(service string, status healthCheckResponse) {
-	// In a real implementation, this would set the serving status of a service
-	_ = service
-	_ = status
-}
-
-// Mock namespace for health check responses
-var healthpb = struct {
-	HealthCheckResponse_SERVING     healthCheckResponse
-	HealthCheckResponse_NOT_SERVING healthCheckResponse
-}{
-	HealthCheckResponse_SERVING:     HealthCheckResponseServing,
-	HealthCheckResponse_NOT_SERVING: HealthCheckResponseNotServing,
-}
-
 // serverAdapter implements the appgrpc.Server interface using the gRPC library.
 type serverAdapter struct {
-	server     *grpcServer
+	server     *grpc.Server
 	config     appgrpc.ServerConfig
 	log        applogger.Logger
-	healthSvc  *healthServer
+	healthSvc  *health.Server
 	registered bool
 }
 
-// NewServerAdapter creates a new gRPC server adapter.
+// NewServerAdapter creates a new gRPC server adapter. The server recovers
+// panics, logs every call and maps errors to gRPC status codes. To report the
+// application health registry instead of the built-in health service,
+// disable EnableHealthCheck and register NewRegistryHealthServer with the
+// aggregator shared with the HTTP handler.
 func NewServerAdapter(config appgrpc.ServerConfig, log applogger.Logger) appgrpc.Server {
-	// Create server options
-	// In a real implementation, these would be:
-	// opts := []grpc.ServerOption{
-	//     grpc.MaxConcurrentStreams(config.MaxConcurrentStreams),
-	//     grpc.MaxRecvMsgSize(config.MaxRecvMsgSize),
-	//     grpc.MaxSendMsgSize(config.MaxSendMsgSize),
-	// }
-	// We're not using options in this mock implementation
-
-	// Create gRPC server with options
-	// In a real implementation, this would be:
-	// server := grpc.NewServer(opts...)
-	server := &grpcServer{}
-
-	// Create health service if enabled
-	// In a real implementation, this would be:
-	// var healthSvc *health.Server
-	// if config.EnableHealthCheck {
-	//     healthSvc = health.NewServer()
-	//     healthpb.RegisterHealthServer(server, healthSvc)
-	// }
-	var healthSvc *healthServer
+	log = applogger.OrNop(log)
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(RecoveryUnaryServerInterceptor(log), LoggingUnaryServerInterceptor(log), ErrorMappingUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(RecoveryStreamServerInterceptor(log), LoggingStreamServerInterceptor(log), ErrorMappingStreamServerInterceptor()),
+	}
+	if config.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(config.MaxConcurrentStreams))
+	}
+	if config.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(config.MaxRecvMsgSize))
+	}
+	if config.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(config.MaxSendMsgSize))
+	}
+	if config.ConnectionTimeout > 0 {
+		opts = append(opts, grpc.ConnectionTimeout(time.Duration(config.ConnectionTimeout)*time.Second))
+	}
+	server := grpc.NewServer(opts...)
+
+	var healthSvc *health.Server
 	if config.EnableHealthCheck {
-		healthSvc = &healthServer{}
+		healthSvc = health.NewServer()
+		healthgrpc.RegisterHealthServer(server, healthSvc)
 	}
 
-	// Enable reflection if configured
-	// In a real implementation, this would be:
-	// if config.EnableReflection {
-	//     reflection.Register(server)
-	// }
-	// No-op for now
+	if config.EnableReflection {
+		reflection.Register(server)
+	}
 
 	return &serverAdapter{
 		server:     server,
@@ -124,7 +76,8 @@ func NewServerAdapter(config appgrpc.ServerConfig, log applogger.Logger) appgrpc
 	}
 }
 
-// Start starts the gRPC server on the given listener.
+// Start serves gRPC on the given listener. It blocks until Stop is called
+// and then returns nil.
 func (s *serverAdapter) Start(ctx context.Context, listener net.Listener) error {
 	if !s.registered {
 		s.log.Warn(ctx, "starting gRPC server with no registered services")
@@ -132,7 +85,7 @@ func (s *serverAdapter) Start(ctx context.Context, listener net.Listener) error
 
 	// Set all services to SERVING status if health check is enabled
 	if s.healthSvc != nil {
-		s.healthSvc.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		s.healthSvc.SetServingStatus("", healthgrpc.HealthCheckResponse_SERVING)
 	}
 
 	// Log server start
@@ -144,36 +97,40 @@ func (s *serverAdapter) Start(ctx context.Context, listener net.Listener) error
 	return s.server.Serve(listener)
 }
 
-// Stop gracefully stops the gRPC server.
+// Stop gracefully stops the gRPC server, waiting for in-flight calls to
+// finish. If ctx is done first, the remaining calls are cancelled.
 func (s *serverAdapter) Stop(ctx context.Context) error {
 	// Set all services to NOT_SERVING status if health check is enabled
 	if s.healthSvc != nil {
-		s.healthSvc.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		s.healthSvc.SetServingStatus("", healthgrpc.HealthCheckResponse_NOT_SERVING)
 	}
 
 	// Log server stop
 	s.log.Info(ctx, "stopping gRPC server")
 
 	// Gracefully stop the server
-	s.server.GracefulStop()
-	return nil
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+		<-stopped
+	}
+
+	// Flush any buffered log entries
+	return s.log.Flush()
 }
 
-// RegisterService registers a gRPC service with the server.
+// RegisterService registers a gRPC service with the server. The service must
+// have a Register(grpc.ServiceRegistrar) method, which typically calls the
+// generated RegisterXxxServer function.
 func (s *serverAdapter) RegisterService(service interface{}) error {
-	// The service should implement a Register method that takes a serviceRegistrar
-	// In a real implementation, this would be:
-	// if registrar, ok := service.(interface {
-	//     Register(grpc.ServiceRegistrar)
-	// }); ok {
-	//     registrar.Register(s.server)
-	//     s.registered = true
-	//     return nil
-	// }
-
-	// For this mock implementation, we'll just check if the service has a Register method
 	if registrar, ok := service.(interface {
-		Register(interface{})
+		Register(grpc.ServiceRegistrar)
 	}); ok {
 		registrar.Register(s.server)
 		s.registered = true
@@ -5,15 +5,69 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	appgrpc "github.com/next-trace/scg-service-api/application/grpc"
 	grpcimpl "github.com/next-trace/scg-service-api/infrastructure/grpc"
 	infraLogger "github.com/next-trace/scg-service-api/infrastructure/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type dummyService struct{ registered bool }
 
-func (d *dummyService) Register(_ interface{}) { d.registered = true }
+func (d *dummyService) Register(_ grpc.ServiceRegistrar) { d.registered = true }
+
+// serve starts srv on a local listener, waits until its health service
+// reports SERVING and returns a channel receiving the result of Start.
+func serve(t *testing.T, srv appgrpc.Server) <-chan error {
+	t.Helper()
+
+	lc := &net.ListenConfig{}
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Start(context.Background(), ln) }()
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := healthgrpc.NewHealthClient(conn).Check(ctx, &healthgrpc.HealthCheckRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatalf("health check: %v", err)
+	}
+	if resp.GetStatus() != healthgrpc.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v", resp.GetStatus())
+	}
+	return done
+}
+
+// stop stops srv and checks that Start returned nil.
+func stop(t *testing.T, srv appgrpc.Server, done <-chan error) {
+	t.Helper()
+
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Start did not return after Stop")
+	}
+}
 
 func TestServerAdapter_RegisterStartStop(t *testing.T) {
 	var buf bytes.Buffer
@@ -29,18 +83,18 @@ func TestServerAdapter_RegisterStartStop(t *testing.T) {
 	if !d.registered {
 		t.Fatalf("expected dummy service to be registered")
 	}
-
-	lc := &net.ListenConfig{}
-	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
-	if err != nil {
-		t.Fatalf("listen: %v", err)
+	if err := srv.RegisterService(struct{}{}); err == nil {
+		t.Fatalf("expected a service without Register to be rejected")
 	}
-	t.Cleanup(func() { _ = ln.Close() })
 
-	if err := srv.Start(context.Background(), ln); err != nil {
-		t.Fatalf("start: %v", err)
-	}
-	if err := srv.Stop(context.Background()); err != nil {
-		t.Fatalf("stop: %v", err)
-	}
+	done := serve(t, srv)
+	stop(t, srv, done)
+}
+
+func TestServerAdapter_NilLogger(t *testing.T) {
+	srv := grpcimpl.NewServerAdapter(appgrpc.DefaultServerConfig(), nil)
+
+	// Starting without registered services logs a warning.
+	done := serve(t, srv)
+	stop(t, srv, done)
 }
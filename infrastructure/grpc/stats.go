@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	"google.golang.org/grpc/stats"
+)
+
+// Gauges maintained by MetricsStatsHandler.
+const (
+	ActiveConnectionsGauge = "grpc_active_connections"
+	InFlightRequestsGauge  = "grpc_in_flight_requests"
+)
+
+// metricsStatsHandler tracks open connections and in-flight RPCs of a server.
+type metricsStatsHandler struct {
+	metrics appmetrics.Metrics
+}
+
+// NewMetricsStatsHandler returns a stats.Handler that keeps the
+// grpc_active_connections and grpc_in_flight_requests gauges up to date.
+// Install it on a server with grpc.StatsHandler; client-side events are
+// ignored.
+func NewMetricsStatsHandler(metrics appmetrics.Metrics) stats.Handler {
+	return &metricsStatsHandler{metrics: metrics}
+}
+
+// TagRPC leaves the RPC context unchanged.
+func (h *metricsStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC counts an RPC as in flight from Begin until End.
+func (h *metricsStatsHandler) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if s.IsClient() {
+		return
+	}
+	switch s.(type) {
+	case *stats.Begin:
+		h.metrics.GaugeInc(InFlightRequestsGauge)
+	case *stats.End:
+		h.metrics.GaugeDec(InFlightRequestsGauge)
+	}
+}
+
+// TagConn leaves the connection context unchanged.
+func (h *metricsStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn counts a connection as active from ConnBegin until ConnEnd.
+func (h *metricsStatsHandler) HandleConn(_ context.Context, s stats.ConnStats) {
+	if s.IsClient() {
+		return
+	}
+	switch s.(type) {
+	case *stats.ConnBegin:
+		h.metrics.GaugeInc(ActiveConnectionsGauge)
+	case *stats.ConnEnd:
+		h.metrics.GaugeDec(ActiveConnectionsGauge)
+	}
+}
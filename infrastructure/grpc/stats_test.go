@@ -0,0 +1,53 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	examplev1 "github.com/next-trace/scg-service-api/gen/v1"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// slowService blocks GetItem until release is closed.
+type slowService struct {
+	examplev1.UnimplementedExampleServiceServer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s slowService) GetItem(_ context.Context, _ *examplev1.GetItemRequest) (*examplev1.GetItemResponse, error) {
+	close(s.started)
+	<-s.release
+	return &examplev1.GetItemResponse{}, nil
+}
+
+func TestMetricsStatsHandler(t *testing.T) {
+	metrics := testsupport.NewMetrics()
+	svc := slowService{started: make(chan struct{}), release: make(chan struct{})}
+	client := startTestServer(t, svc, grpc.StatsHandler(infragrpc.NewMetricsStatsHandler(metrics)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetItem(ctx, &examplev1.GetItemRequest{Id: "1"})
+		done <- err
+	}()
+
+	<-svc.started
+	assert.Equal(t, 1.0, metrics.Gauge(infragrpc.InFlightRequestsGauge, nil))
+	assert.Equal(t, 1.0, metrics.Gauge(infragrpc.ActiveConnectionsGauge, nil))
+
+	close(svc.release)
+	if err := <-done; err != nil {
+		t.Fatalf("GetItem: %v", err)
+	}
+	assert.Eventually(t, func() bool {
+		return metrics.Gauge(infragrpc.InFlightRequestsGauge, nil) == 0
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+
+	apptracing "github.com/next-trace/scg-service-api/application/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TracingUnaryServerInterceptor returns a unary interceptor that starts a
+// server span named after the full method for every call. The caller's trace
+// context is extracted from the incoming metadata with the global OpenTelemetry
+// propagator, so the span joins the client's trace. A nil tracer is replaced
+// with apptracing.Nop().
+//
+// Callers must install that propagator, with otel.SetTextMapPropagator,
+// before serving: tracing.NewOtelAdapter installs W3C Trace Context, but with
+// any other tracer the global propagator is a no-op and every call starts a
+// new trace.
+func TracingUnaryServerInterceptor(tracer apptracing.Tracer) grpc.UnaryServerInterceptor {
+	tracer = apptracing.OrNop(tracer)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, end := startServerSpan(ctx, tracer, info.FullMethod)
+		defer end()
+
+		resp, err := handler(ctx, req)
+		recordStatus(ctx, tracer, err)
+		return resp, err
+	}
+}
+
+// TracingStreamServerInterceptor is the streaming counterpart of
+// TracingUnaryServerInterceptor; the span covers the whole stream.
+func TracingStreamServerInterceptor(tracer apptracing.Tracer) grpc.StreamServerInterceptor {
+	tracer = apptracing.OrNop(tracer)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, end := startServerSpan(ss.Context(), tracer, info.FullMethod)
+		defer end()
+
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		recordStatus(ctx, tracer, err)
+		return err
+	}
+}
+
+// startServerSpan starts the server span of a call, as a child of the trace
+// context in the incoming metadata, and stores tracer in the returned context
+// for apptracing.AddSpanAttributes and friends.
+func startServerSpan(ctx context.Context, tracer apptracing.Tracer, method string) (context.Context, func()) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	ctx, end := tracer.Start(apptracing.ContextWithSpanKind(ctx, apptracing.SpanKindServer), method)
+	tracer.SetAttributes(ctx, map[string]string{
+		"rpc.system": "grpc",
+		"rpc.method": method,
+	})
+	return apptracing.ContextWithTracer(ctx, tracer), end
+}
+
+// recordStatus records the call's status code on the span, and the error if
+// it failed.
+func recordStatus(ctx context.Context, tracer apptracing.Tracer, err error) {
+	tracer.SetAttributes(ctx, map[string]string{"rpc.grpc.status_code": status.Code(err).String()})
+	if err != nil {
+		tracer.RecordError(ctx, err)
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to an OpenTelemetry TextMapCarrier.
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier(nil)
+
+// Get returns the first value for key.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces the values for key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns the metadata keys.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,49 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	apptracing "github.com/next-trace/scg-service-api/application/tracing"
+	infragrpc "github.com/next-trace/scg-service-api/infrastructure/grpc"
+	"github.com/next-trace/scg-service-api/infrastructure/tracing"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracingUnaryServerInterceptor_JoinsIncomingTrace(t *testing.T) {
+	exporter := testsupport.NewSpanExporter()
+	tracer, err := tracing.NewOtelAdapterWithOptions(apptracing.Config{ServiceName: "test"},
+		tracing.WithExporter(exporter), tracing.WithSampler(sdktrace.AlwaysSample()))
+	require.NoError(t, err)
+
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-"+traceID+"-"+spanID+"-01"))
+
+	var handlerSpan trace.SpanContext
+	interceptor := infragrpc.TracingUnaryServerInterceptor(tracer)
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/example.v1.ExampleService/GetItem"}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, traceID, handlerSpan.TraceID().String())
+	require.NoError(t, tracer.Shutdown(context.Background()))
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "/example.v1.ExampleService/GetItem", span.Name)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.Equal(t, traceID, span.SpanContext.TraceID().String())
+	assert.Equal(t, spanID, span.Parent.SpanID().String())
+	assert.True(t, span.Parent.IsRemote())
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apphealth "github.com/next-trace/scg-service-api/application/health"
+)
+
+// Report is the combined outcome of running a set of health checks.
+type Report struct {
+	// Status is the worst status among Results (UP if there are none).
+	Status apphealth.Status
+
+	// Results holds each check's result keyed by check name.
+	Results map[string]apphealth.Result
+}
+
+// Aggregator runs the checks of a Registry and combines their results.
+// Every transport that reports health (HTTP, gRPC) should share one Aggregator,
+// or at least one Registry, so they can never disagree.
+type Aggregator struct {
+	registry apphealth.Registry
+	timeout  time.Duration
+}
+
+// NewAggregator creates an Aggregator over registry. A positive timeout bounds
+// each check. If registry is an apphealth.TimeoutRegistry, a check registered
+// with its own timeout is bounded by that instead, and the evaluation as a
+// whole by the longest timeout involved.
+func NewAggregator(registry apphealth.Registry, timeout time.Duration) *Aggregator {
+	return &Aggregator{
+		registry: registry,
+		timeout:  timeout,
+	}
+}
+
+// Evaluate runs all checks of the given type concurrently and returns the
+// combined report. Every check's context is derived from one evaluation
+// context, so no check outlives the evaluation, and a slow check cannot use
+// up the time of the others.
+func (a *Aggregator) Evaluate(ctx context.Context, checkType apphealth.CheckType) Report {
+	timeouts, _ := a.registry.(apphealth.TimeoutRegistry)
+	checks := a.registry.GetChecks(checkType)
+
+	budget := a.timeout
+	limits := make(map[string]time.Duration, len(checks))
+	for name := range checks {
+		limit := a.timeout
+		if timeout := checkTimeout(timeouts, name, checkType); timeout > 0 {
+			limit = timeout
+			if budget > 0 {
+				budget = max(budget, timeout)
+			}
+		}
+		limits[name] = limit
+	}
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	report := Report{
+		Status:  apphealth.StatusUp,
+		Results: make(map[string]apphealth.Result, len(checks)),
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Go(func() {
+			var result apphealth.Result
+			if limit := limits[name]; limit > 0 {
+				result = runCheckWithTimeout(ctx, name, check, limit)
+			} else {
+				result = runCheck(ctx, name, check)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Results[name] = result
+			report.Status = worseStatus(report.Status, result.Status)
+		})
+	}
+	wg.Wait()
+
+	return report
+}
+
+// checkTimeout returns the per-check timeout registered in timeouts, or 0.
+func checkTimeout(timeouts apphealth.TimeoutRegistry, name string, checkType apphealth.CheckType) time.Duration {
+	if timeouts == nil {
+		return 0
+	}
+	return timeouts.CheckTimeout(name, checkType)
+}
+
+// runCheckWithTimeout runs check with its own timeout, or the time left in
+// ctx if that is shorter.
+func runCheckWithTimeout(ctx context.Context, name string, check apphealth.Check, timeout time.Duration) apphealth.Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return runCheck(ctx, name, check)
+}
+
+// runCheck runs check, turning a panic into a DOWN result carrying the panic
+// value as its error so one faulty check cannot crash the server.
+func runCheck(ctx context.Context, name string, check apphealth.Check) (result apphealth.Result) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			result = apphealth.Result{
+				Status:    apphealth.StatusDown,
+				Component: name,
+				Error:     fmt.Sprintf("health check panicked: %v", rec),
+				Timestamp: time.Now(),
+			}
+		}
+	}()
+	return check(ctx)
+}
+
+// worseStatus returns the more severe of two statuses: DOWN, then DEGRADED, then UP.
+func worseStatus(a, b apphealth.Status) apphealth.Status {
+	if a == apphealth.StatusDown || b == apphealth.StatusDown {
+		return apphealth.StatusDown
+	}
+	if a == apphealth.StatusDegraded || b == apphealth.StatusDegraded {
+		return apphealth.StatusDegraded
+	}
+	return apphealth.StatusUp
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	apphealth "github.com/next-trace/scg-service-api/application/health"
+)
+
+// cacheCheckTTL bounds how long a probe entry can outlive a failed Delete.
+const cacheCheckTTL = 10 * time.Second
+
+// errCacheProbeMismatch is reported when the probe value cannot be read back.
+var errCacheProbeMismatch = errors.New("cache probe value not read back")
+
+// CacheCheck returns a readiness check for cache connectivity. Caches that
+// implement appcache.Pinger are pinged; others get a Set/Get/Delete round-trip
+// on a short-lived probe key unique to each run. Any failure reports StatusDown.
+func CacheCheck(name string, cache appcache.Cache) apphealth.Check {
+	return func(ctx context.Context) apphealth.Result {
+		start := time.Now()
+		err := probeCache(ctx, name, cache)
+
+		result := apphealth.Result{
+			Status:    apphealth.StatusUp,
+			Component: name,
+			Details:   map[string]interface{}{"latency_ms": time.Since(start).Milliseconds()},
+			Timestamp: time.Now(),
+		}
+		if err != nil {
+			result.Status = apphealth.StatusDown
+			result.Error = err.Error()
+		}
+		return result
+	}
+}
+
+// cacheProbePrefix namespaces the probe keys written by CacheCheck.
+const cacheProbePrefix = "health:probe:"
+
+// probeCache pings cache or performs the round-trip on a unique key.
+func probeCache(ctx context.Context, name string, cache appcache.Cache) error {
+	if pinger, ok := cache.(appcache.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	// A random token per probe keeps concurrent probes, and the application's
+	// own keys, from being overwritten or deleted by this one.
+	token := rand.Text()
+	key := cacheProbePrefix + name + ":" + token
+	if err := cache.Set(ctx, key, token, cacheCheckTTL); err != nil {
+		return fmt.Errorf("set: %w", err)
+	}
+	if got, ok := cache.Get(ctx, key); !ok || got != token {
+		return errCacheProbeMismatch
+	}
+	if err := cache.Delete(ctx, key); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
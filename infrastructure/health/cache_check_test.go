@@ -0,0 +1,81 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	apphealth "github.com/next-trace/scg-service-api/application/health"
+	healthimpl "github.com/next-trace/scg-service-api/infrastructure/health"
+	"github.com/next-trace/scg-service-api/testsupport"
+)
+
+// failingCache rejects every write, like a cache whose backend is unreachable.
+type failingCache struct {
+	*testsupport.Cache
+}
+
+func (failingCache) Set(context.Context, string, interface{}, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+// pingingCache reports connectivity through Ping.
+type pingingCache struct {
+	*testsupport.Cache
+	err error
+}
+
+func (c pingingCache) Ping(context.Context) error { return c.err }
+
+func TestCacheCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache appcache.Cache
+		want  apphealth.Status
+	}{
+		{"Round-trip succeeds", testsupport.NewCache(0), apphealth.StatusUp},
+		{"Write fails", failingCache{testsupport.NewCache(0)}, apphealth.StatusDown},
+		{"Ping succeeds", pingingCache{Cache: testsupport.NewCache(0)}, apphealth.StatusUp},
+		{"Ping fails", pingingCache{Cache: testsupport.NewCache(0), err: errors.New("timeout")}, apphealth.StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := healthimpl.CacheCheck("cache", tt.cache)(context.Background())
+			if res.Status != tt.want {
+				t.Fatalf("expected %s, got %s (error %q)", tt.want, res.Status, res.Error)
+			}
+			if tt.want == apphealth.StatusDown && res.Error == "" {
+				t.Fatalf("expected an error message for a failed check")
+			}
+			if res.Component != "cache" {
+				t.Fatalf("unexpected component: %s", res.Component)
+			}
+		})
+	}
+}
+
+func TestCacheCheck_ProbeLeavesOtherKeysAlone(t *testing.T) {
+	ctx := context.Background()
+	cache := testsupport.NewCache(0)
+	if err := cache.Set(ctx, "health:cache", "app value", 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	check := healthimpl.CacheCheck("cache", cache)
+	done := make(chan apphealth.Result, 8)
+	for range cap(done) {
+		go func() { done <- check(ctx) }()
+	}
+	for range cap(done) {
+		if res := <-done; res.Status != apphealth.StatusUp {
+			t.Fatalf("expected concurrent probes to pass, got %s (error %q)", res.Status, res.Error)
+		}
+	}
+
+	if got, ok := cache.Get(ctx, "health:cache"); !ok || got != "app value" {
+		t.Fatalf("expected the probe to leave health:cache alone, got %v, %v", got, ok)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -48,3 +49,190 @@ func TestHealthHandlers_RegistryAndHTTP(t *testing.T) {
 		t.Fatalf("missing status in body")
 	}
 }
+
+func TestHealthHandlers_PanickingCheck(t *testing.T) {
+	reg := healthimpl.NewRegistry()
+	healthimpl.RegisterCommonChecks(reg)
+	reg.RegisterCheck("flaky", apphealth.CheckTypeLiveness, func(_ context.Context) apphealth.Result {
+		panic("nil pointer in driver")
+	})
+
+	cfg := apphealth.DefaultConfig()
+	h := healthimpl.NewHTTPHandler(reg, cfg, nil)
+	handler, ok := h.LivenessHandler().(http.Handler)
+	if !ok {
+		t.Fatalf("expected an http.Handler")
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, cfg.LivenessPath, nil))
+	if rw.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rw.Code)
+	}
+
+	var body struct {
+		Status apphealth.Status            `json:"status"`
+		Checks map[string]apphealth.Result `json:"checks"`
+	}
+	if err := json.Unmarshal(rw.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	flaky := body.Checks["flaky"]
+	if flaky.Status != apphealth.StatusDown || !strings.Contains(flaky.Error, "nil pointer in driver") {
+		t.Fatalf("expected DOWN result carrying the panic, got %+v", flaky)
+	}
+	if body.Checks["service"].Status != apphealth.StatusUp {
+		t.Fatalf("expected other checks to still run, got %+v", body.Checks["service"])
+	}
+}
+
+func TestHealthHandlers_Head(t *testing.T) {
+	reg := healthimpl.NewRegistry()
+	reg.RegisterCheck("db", apphealth.CheckTypeReadiness, func(_ context.Context) apphealth.Result {
+		return apphealth.Result{Status: apphealth.StatusDown, Component: "db", Timestamp: time.Now()}
+	})
+
+	cfg := apphealth.DefaultConfig()
+	cfg.Enabled = true
+	mux := http.NewServeMux()
+	healthimpl.RegisterHTTPHandlers(healthimpl.NewHTTPHandler(reg, cfg, nil), mux, cfg)
+
+	for path, want := range map[string]int{
+		cfg.LivenessPath:  http.StatusOK,
+		cfg.ReadinessPath: http.StatusServiceUnavailable,
+		cfg.Path:          http.StatusServiceUnavailable,
+	} {
+		rw := httptest.NewRecorder()
+		mux.ServeHTTP(rw, httptest.NewRequest(http.MethodHead, path, nil))
+		if rw.Code != want {
+			t.Fatalf("HEAD %s: expected %d, got %d", path, want, rw.Code)
+		}
+		if rw.Body.Len() != 0 {
+			t.Fatalf("HEAD %s: expected an empty body, got %q", path, rw.Body.String())
+		}
+	}
+}
+
+func TestAggregator_PerCheckTimeout(t *testing.T) {
+	// slow reports UP only if it is allowed to run for 50ms.
+	slow := func(ctx context.Context) apphealth.Result {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return apphealth.Result{Status: apphealth.StatusUp, Component: "db", Timestamp: time.Now()}
+		case <-ctx.Done():
+			return apphealth.Result{Status: apphealth.StatusDown, Component: "db", Error: ctx.Err().Error(), Timestamp: time.Now()}
+		}
+	}
+
+	reg, ok := healthimpl.NewRegistry().(apphealth.TimeoutRegistry)
+	if !ok {
+		t.Fatalf("expected the registry to support per-check timeouts")
+	}
+	reg.RegisterCheckWithTimeout("db", apphealth.CheckTypeReadiness, time.Second, slow)
+	reg.RegisterCheck("cache", apphealth.CheckTypeLiveness, slow)
+
+	agg := healthimpl.NewAggregator(reg, 10*time.Millisecond)
+
+	if report := agg.Evaluate(context.Background(), apphealth.CheckTypeReadiness); report.Status != apphealth.StatusUp {
+		t.Fatalf("expected the per-check timeout to win over the global one, got %+v", report.Results["db"])
+	}
+	if report := agg.Evaluate(context.Background(), apphealth.CheckTypeLiveness); report.Status != apphealth.StatusDown {
+		t.Fatalf("expected the global timeout to cut off a check without its own, got %+v", report.Results["cache"])
+	}
+	if got := reg.CheckTimeout("db", apphealth.CheckTypeReadiness); got != time.Second {
+		t.Fatalf("unexpected registered timeout: %v", got)
+	}
+	reg.UnregisterCheck("db", apphealth.CheckTypeReadiness)
+	if got := reg.CheckTimeout("db", apphealth.CheckTypeReadiness); got != 0 {
+		t.Fatalf("expected the timeout to be removed with the check, got %v", got)
+	}
+}
+
+func TestAggregator_SlowCheckDoesNotStarveOthers(t *testing.T) {
+	// sleeper reports UP only if it is allowed to run for d.
+	sleeper := func(name string, d time.Duration) apphealth.Check {
+		return func(ctx context.Context) apphealth.Result {
+			select {
+			case <-time.After(d):
+				return apphealth.Result{Status: apphealth.StatusUp, Component: name, Timestamp: time.Now()}
+			case <-ctx.Done():
+				return apphealth.Result{Status: apphealth.StatusDown, Component: name, Error: ctx.Err().Error(), Timestamp: time.Now()}
+			}
+		}
+	}
+
+	reg := healthimpl.NewRegistry()
+	reg.RegisterCheck("slow", apphealth.CheckTypeReadiness, sleeper("slow", 150*time.Millisecond))
+	reg.RegisterCheck("fast", apphealth.CheckTypeReadiness, sleeper("fast", 100*time.Millisecond))
+
+	// Run one after the other, the two checks would need 250ms.
+	agg := healthimpl.NewAggregator(reg, 200*time.Millisecond)
+	report := agg.Evaluate(context.Background(), apphealth.CheckTypeReadiness)
+	if report.Status != apphealth.StatusUp {
+		t.Fatalf("expected both checks to finish within the timeout, got %+v", report.Results)
+	}
+}
+
+func TestHealthHandlers_EvaluatesLivenessAndReadinessConcurrently(t *testing.T) {
+	// Each check waits for the other to start, so both are UP only when the
+	// two types are evaluated at the same time.
+	livenessStarted, readinessStarted := make(chan struct{}), make(chan struct{})
+	waitFor := func(name string, started chan struct{}, other <-chan struct{}) apphealth.Check {
+		return func(ctx context.Context) apphealth.Result {
+			close(started)
+			select {
+			case <-other:
+				return apphealth.Result{Status: apphealth.StatusUp, Component: name, Timestamp: time.Now()}
+			case <-time.After(time.Second):
+				return apphealth.Result{Status: apphealth.StatusDown, Component: name, Error: "other check type did not run", Timestamp: time.Now()}
+			case <-ctx.Done():
+				return apphealth.Result{Status: apphealth.StatusDown, Component: name, Error: ctx.Err().Error(), Timestamp: time.Now()}
+			}
+		}
+	}
+
+	reg := healthimpl.NewRegistry()
+	reg.RegisterCheck("live", apphealth.CheckTypeLiveness, waitFor("live", livenessStarted, readinessStarted))
+	reg.RegisterCheck("ready", apphealth.CheckTypeReadiness, waitFor("ready", readinessStarted, livenessStarted))
+
+	cfg := apphealth.DefaultConfig()
+	cfg.Timeout = 0
+	h := healthimpl.NewHTTPHandler(reg, cfg, nil)
+
+	rw := httptest.NewRecorder()
+	h.HealthHandler().(http.Handler).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, cfg.Path, nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected both check types to run concurrently, got %d: %s", rw.Code, rw.Body.String())
+	}
+}
+
+func TestHealthHandlers_ResponseFormat(t *testing.T) {
+	reg := healthimpl.NewRegistry()
+	healthimpl.RegisterCommonChecks(reg)
+
+	cfg := apphealth.DefaultConfig()
+	cfg.ProbeFormat = apphealth.ResponseFormatSimple
+	mux := http.NewServeMux()
+	healthimpl.RegisterHTTPHandlers(healthimpl.NewHTTPHandler(reg, cfg, nil), mux, cfg)
+
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, cfg.LivenessPath, nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rw.Code)
+	}
+	if got := strings.TrimSpace(rw.Body.String()); got != `{"status":"UP"}` {
+		t.Fatalf("expected a simple liveness body, got %s", got)
+	}
+
+	rw = httptest.NewRecorder()
+	mux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, cfg.Path, nil))
+	var body map[string]any
+	if err := json.Unmarshal(rw.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	for _, field := range []string{"status", "timestamp", "checks"} {
+		if _, ok := body[field]; !ok {
+			t.Fatalf("expected the aggregate body to keep %q, got %v", field, body)
+		}
+	}
+}
@@ -2,9 +2,9 @@
 package health
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
+	"sync"
 	"time"
 
 	apphealth "github.com/next-trace/scg-service-api/application/health"
@@ -13,17 +13,25 @@ import (
 
 // httpHandler implements the health.Handler interface.
 type httpHandler struct {
-	registry apphealth.Registry
-	config   apphealth.Config
-	log      applogger.Logger
+	aggregator *Aggregator
+	config     apphealth.Config
+	log        applogger.Logger
 }
 
 // NewHTTPHandler creates a new HTTP handler for health checks.
 func NewHTTPHandler(registry apphealth.Registry, config apphealth.Config, log applogger.Logger) apphealth.Handler {
+	log = applogger.OrNop(log)
+	return NewHTTPHandlerWithAggregator(NewAggregator(registry, config.Timeout), config, log)
+}
+
+// NewHTTPHandlerWithAggregator creates a new HTTP handler for health checks that
+// reports from aggregator, so it can share its view with other transports.
+func NewHTTPHandlerWithAggregator(aggregator *Aggregator, config apphealth.Config, log applogger.Logger) apphealth.Handler {
+	log = applogger.OrNop(log)
 	return &httpHandler{
-		registry: registry,
-		config:   config,
-		log:      log,
+		aggregator: aggregator,
+		config:     config,
+		log:        log,
 	}
 }
 
@@ -50,104 +58,53 @@ func (h *httpHandler) HealthHandler() interface{} {
 
 // handleChecks runs all health checks of the given type and returns the results.
 func (h *httpHandler) handleChecks(w http.ResponseWriter, r *http.Request, checkType apphealth.CheckType) {
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), h.config.Timeout)
-	defer cancel()
-
-	// Get all checks of the given type
-	checks := h.registry.GetChecks(checkType)
-
-	// Run all checks and collect results
-	results := make(map[string]apphealth.Result)
-	overallStatus := apphealth.StatusUp
-
-	for name, check := range checks {
-		result := check(ctx)
-		results[name] = result
-
-		// Update overall status based on the result
-		if result.Status == apphealth.StatusDown {
-			overallStatus = apphealth.StatusDown
-		} else if result.Status == apphealth.StatusDegraded && overallStatus != apphealth.StatusDown {
-			overallStatus = apphealth.StatusDegraded
-		}
-	}
+	report := h.aggregator.Evaluate(r.Context(), checkType)
 
 	// Create the response
 	response := map[string]interface{}{
-		"status":    overallStatus,
+		"status":    report.Status,
 		"timestamp": time.Now(),
-		"checks":    results,
-	}
-
-	// Set the status code based on the overall status
-	statusCode := http.StatusOK
-	switch overallStatus {
-	case apphealth.StatusDown:
-		statusCode = http.StatusServiceUnavailable
-	case apphealth.StatusDegraded:
-		statusCode = http.StatusOK // Still OK, but degraded
+		"checks":    report.Results,
 	}
 
-	// Write the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error(ctx, err, "failed to encode health check response")
-	}
+	h.writeResponse(w, r, report.Status, formatResponse(h.config.ProbeFormat, response))
 }
 
-// handleAllChecks runs all health checks and returns the results.
+// handleAllChecks runs all health checks and returns the results. Liveness
+// and readiness are evaluated concurrently, so the response takes as long as
+// the slower of the two rather than their sum.
 func (h *httpHandler) handleAllChecks(w http.ResponseWriter, r *http.Request) {
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), h.config.Timeout)
-	defer cancel()
-
-	// Get all checks
-	livenessChecks := h.registry.GetChecks(apphealth.CheckTypeLiveness)
-	readinessChecks := h.registry.GetChecks(apphealth.CheckTypeReadiness)
-
-	// Run all checks and collect results
-	livenessResults := make(map[string]apphealth.Result)
-	readinessResults := make(map[string]apphealth.Result)
-	overallStatus := apphealth.StatusUp
-
-	// Run liveness checks
-	for name, check := range livenessChecks {
-		result := check(ctx)
-		livenessResults[name] = result
-
-		// Update overall status based on the result
-		if result.Status == apphealth.StatusDown {
-			overallStatus = apphealth.StatusDown
-		} else if result.Status == apphealth.StatusDegraded && overallStatus != apphealth.StatusDown {
-			overallStatus = apphealth.StatusDegraded
-		}
-	}
-
-	// Run readiness checks
-	for name, check := range readinessChecks {
-		result := check(ctx)
-		readinessResults[name] = result
-
-		// Update overall status based on the result
-		if result.Status == apphealth.StatusDown {
-			overallStatus = apphealth.StatusDown
-		} else if result.Status == apphealth.StatusDegraded && overallStatus != apphealth.StatusDown {
-			overallStatus = apphealth.StatusDegraded
-		}
-	}
+	var liveness Report
+	var wg sync.WaitGroup
+	wg.Go(func() { liveness = h.aggregator.Evaluate(r.Context(), apphealth.CheckTypeLiveness) })
+	readiness := h.aggregator.Evaluate(r.Context(), apphealth.CheckTypeReadiness)
+	wg.Wait()
+	overallStatus := worseStatus(liveness.Status, readiness.Status)
 
 	// Create the response
 	response := map[string]interface{}{
 		"status":    overallStatus,
 		"timestamp": time.Now(),
 		"checks": map[string]interface{}{
-			"liveness":  livenessResults,
-			"readiness": readinessResults,
+			"liveness":  liveness.Results,
+			"readiness": readiness.Results,
 		},
 	}
 
+	h.writeResponse(w, r, overallStatus, formatResponse(h.config.Format, response))
+}
+
+// formatResponse reduces a full response to the fields of format.
+func formatResponse(format apphealth.ResponseFormat, response map[string]interface{}) map[string]interface{} {
+	if format == apphealth.ResponseFormatSimple {
+		return map[string]interface{}{"status": response["status"]}
+	}
+	return response
+}
+
+// writeResponse writes the health response with a status code derived from the overall status.
+// HEAD requests get the status code and headers without the body.
+func (h *httpHandler) writeResponse(w http.ResponseWriter, r *http.Request, overallStatus apphealth.Status, response map[string]interface{}) {
 	// Set the status code based on the overall status
 	statusCode := http.StatusOK
 	switch overallStatus {
@@ -160,8 +117,11 @@ func (h *httpHandler) handleAllChecks(w http.ResponseWriter, r *http.Request) {
 	// Write the response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
+	if r.Method == http.MethodHead {
+		return
+	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.log.Error(ctx, err, "failed to encode health check response")
+		h.log.Error(r.Context(), err, "failed to encode health check response")
 	}
 }
 
@@ -9,24 +9,39 @@ import (
 	apphealth "github.com/next-trace/scg-service-api/application/health"
 )
 
-// registry implements the health.Registry interface.
+// registry implements the health.TimeoutRegistry interface.
 type registry struct {
-	checks map[apphealth.CheckType]map[string]apphealth.Check
-	mu     sync.RWMutex
+	checks   map[apphealth.CheckType]map[string]apphealth.Check
+	timeouts map[checkKey]time.Duration
+	mu       sync.RWMutex
 }
 
-// NewRegistry creates a new health check registry.
+// checkKey identifies a registered check.
+type checkKey struct {
+	checkType apphealth.CheckType
+	name      string
+}
+
+// NewRegistry creates a new health check registry. The returned registry also
+// implements apphealth.TimeoutRegistry.
 func NewRegistry() apphealth.Registry {
 	return &registry{
 		checks: map[apphealth.CheckType]map[string]apphealth.Check{
 			apphealth.CheckTypeLiveness:  make(map[string]apphealth.Check),
 			apphealth.CheckTypeReadiness: make(map[string]apphealth.Check),
 		},
+		timeouts: make(map[checkKey]time.Duration),
 	}
 }
 
 // RegisterCheck registers a health check with the given name and type.
 func (r *registry) RegisterCheck(name string, checkType apphealth.CheckType, check apphealth.Check) {
+	r.RegisterCheckWithTimeout(name, checkType, 0, check)
+}
+
+// RegisterCheckWithTimeout registers a health check with its own timeout. A
+// non-positive timeout makes the check use the global one.
+func (r *registry) RegisterCheckWithTimeout(name string, checkType apphealth.CheckType, timeout time.Duration, check apphealth.Check) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -35,6 +50,20 @@ func (r *registry) RegisterCheck(name string, checkType apphealth.CheckType, che
 	}
 
 	r.checks[checkType][name] = check
+	key := checkKey{checkType: checkType, name: name}
+	if timeout > 0 {
+		r.timeouts[key] = timeout
+	} else {
+		delete(r.timeouts, key)
+	}
+}
+
+// CheckTimeout returns the timeout registered for a check, or 0.
+func (r *registry) CheckTimeout(name string, checkType apphealth.CheckType) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.timeouts[checkKey{checkType: checkType, name: name}]
 }
 
 // UnregisterCheck removes a health check with the given name and type.
@@ -45,6 +74,7 @@ func (r *registry) UnregisterCheck(name string, checkType apphealth.CheckType) {
 	if checks, ok := r.checks[checkType]; ok {
 		delete(checks, name)
 	}
+	delete(r.timeouts, checkKey{checkType: checkType, name: name})
 }
 
 // GetChecks returns all registered health checks of the given type.
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// AccessLogOption customizes the access log middleware.
+type AccessLogOption func(*AccessLogMiddleware)
+
+// WithAccessLogSampling logs only a fraction rate, between 0 and 1, of the
+// successful (2xx) requests to paths, or to every path if none are given.
+// Requests answered with any other status are always logged. Sampled
+// entries carry the rate as sample_rate so request counts can be recovered.
+func WithAccessLogSampling(rate float64, paths ...string) AccessLogOption {
+	return func(alm *AccessLogMiddleware) {
+		alm.sampleRate = min(max(rate, 0), 1)
+		alm.samplePaths = paths
+	}
+}
+
+// AccessLogMiddleware logs one entry per completed request.
+type AccessLogMiddleware struct {
+	log         applogger.Logger
+	sampleRate  float64
+	samplePaths []string
+	sampled     atomic.Uint64
+}
+
+// NewAccessLogMiddleware creates a new access log middleware.
+func NewAccessLogMiddleware(log applogger.Logger, opts ...AccessLogOption) *AccessLogMiddleware {
+	log = applogger.OrNop(log)
+	alm := &AccessLogMiddleware{
+		log:        log,
+		sampleRate: 1,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(alm)
+		}
+	}
+	return alm
+}
+
+// Middleware returns an http.Handler middleware function.
+func (alm *AccessLogMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := newResponseWriterWrapper(w)
+
+			next.ServeHTTP(rw, r)
+
+			fields := map[string]interface{}{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status":      rw.statusCode,
+				"bytes":       rw.bytesWritten,
+				"duration_ms": time.Since(start).Milliseconds(),
+			}
+			if alm.isSampled(r, rw.statusCode) {
+				if !alm.keepSample() {
+					return
+				}
+				fields["sample_rate"] = alm.sampleRate
+			}
+			alm.log.InfoKV(r.Context(), "http request", fields)
+		})
+	}
+}
+
+// isSampled reports whether the request is subject to sampling.
+func (alm *AccessLogMiddleware) isSampled(r *http.Request, status int) bool {
+	if alm.sampleRate >= 1 || status < 200 || status >= 300 {
+		return false
+	}
+	return len(alm.samplePaths) == 0 || slices.Contains(alm.samplePaths, r.URL.Path)
+}
+
+// keepSample decides whether a sampled request is logged. Counting instead
+// of drawing random numbers keeps exactly rate of them, spread evenly.
+func (alm *AccessLogMiddleware) keepSample() bool {
+	n := float64(alm.sampled.Add(1))
+	return math.Floor(n*alm.sampleRate) > math.Floor((n-1)*alm.sampleRate)
+}
@@ -0,0 +1,58 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "info")
+
+	handler := middleware.NewAccessLogMiddleware(log).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", nil))
+
+	out := logBuffer.String()
+	assert.Contains(t, out, `"msg":"http request"`)
+	assert.Contains(t, out, `"method":"POST"`)
+	assert.Contains(t, out, `"path":"/items"`)
+	assert.Contains(t, out, `"status":201`)
+	assert.Contains(t, out, `"bytes":7`)
+}
+
+func TestAccessLogMiddleware_Sampling(t *testing.T) {
+	log := testsupport.NewLogger()
+	handler := middleware.NewAccessLogMiddleware(log, middleware.WithAccessLogSampling(0.25, "/ping")).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	logged := func(target string) int {
+		before := len(log.Entries())
+		for range 100 {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		}
+		return len(log.Entries()) - before
+	}
+
+	assert.Equal(t, 25, logged("/ping"), "a quarter of successful requests")
+	assert.Equal(t, 100, logged("/ping?fail=1"), "every error")
+	assert.Equal(t, 100, logged("/items"), "every request to paths not sampled")
+
+	entry, ok := log.Find("http request")
+	assert.True(t, ok)
+	assert.Equal(t, 0.25, entry.Fields["sample_rate"])
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CoalescingMiddleware collapses concurrent identical GET and HEAD requests
+// into a single handler execution. The first request runs the handler; the
+// others wait for it and receive a copy of its buffered response.
+type CoalescingMiddleware struct {
+	includeAuth bool
+
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+// coalescedCall is an in-flight handler execution shared by identical requests.
+type coalescedCall struct {
+	done     chan struct{}
+	response *bufferedResponse
+	panicked bool
+}
+
+// NewCoalescingMiddleware creates a middleware that keys requests by method,
+// path and query. When includeAuth is true the Authorization and Cookie
+// headers are part of the key too, so only requests presenting the same
+// credentials share a response; leave it false only for responses that do not
+// depend on the caller. Credentials carried anywhere else, such as a custom
+// header or a client certificate, are not part of the key.
+//
+// The shared execution runs with the first request's context, so its
+// cancellation affects every waiter.
+func NewCoalescingMiddleware(includeAuth bool) *CoalescingMiddleware {
+	return &CoalescingMiddleware{
+		includeAuth: includeAuth,
+		calls:       make(map[string]*coalescedCall),
+	}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (cm *CoalescingMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.Method + " " + r.URL.RequestURI()
+			if cm.includeAuth {
+				key += " " + r.Header.Get("Authorization") + " " + strings.Join(r.Header.Values("Cookie"), "; ")
+			}
+
+			cm.mu.Lock()
+			if call, ok := cm.calls[key]; ok {
+				cm.mu.Unlock()
+				select {
+				case <-call.done:
+				case <-r.Context().Done():
+					return
+				}
+				if call.panicked {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+				call.response.writeTo(w)
+				return
+			}
+			call := &coalescedCall{done: make(chan struct{}), response: newBufferedResponse()}
+			cm.calls[key] = call
+			cm.mu.Unlock()
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					call.panicked = true
+					cm.finish(key, call)
+					panic(rec)
+				}
+				cm.finish(key, call)
+				call.response.writeTo(w)
+			}()
+			next.ServeHTTP(call.response, r)
+		})
+	}
+}
+
+// finish removes the call so later requests execute afresh and releases its waiters.
+func (cm *CoalescingMiddleware) finish(key string, call *coalescedCall) {
+	cm.mu.Lock()
+	delete(cm.calls, key)
+	cm.mu.Unlock()
+	close(call.done)
+}
+
+// bufferedResponse captures a response so it can be replayed to several writers.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header)}
+}
+
+func (br *bufferedResponse) Header() http.Header {
+	return br.header
+}
+
+func (br *bufferedResponse) WriteHeader(status int) {
+	if br.status == 0 {
+		br.status = status
+	}
+}
+
+func (br *bufferedResponse) Write(b []byte) (int, error) {
+	if br.status == 0 {
+		br.status = http.StatusOK
+	}
+	return br.body.Write(b)
+}
+
+// writeTo replays the captured response. It is only called once the handler
+// has returned, so concurrent replays only read.
+func (br *bufferedResponse) writeTo(w http.ResponseWriter) {
+	header := w.Header()
+	for k, v := range br.header {
+		header[k] = append([]string(nil), v...)
+	}
+	status := br.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(br.body.Bytes())
+}
@@ -0,0 +1,137 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescingMiddleware_CollapsesConcurrentGETs(t *testing.T) {
+	const requests = 10
+
+	var runs atomic.Int32
+	var arrived sync.WaitGroup
+	arrived.Add(requests)
+	release := make(chan struct{})
+
+	handler := middleware.NewCoalescingMiddleware(false).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runs.Add(1)
+		<-release
+		w.Header().Set("X-Result", "computed")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("expensive"))
+	}))
+	counting := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		handler.ServeHTTP(w, r)
+	})
+
+	recorders := make([]*httptest.ResponseRecorder, requests)
+	var wg sync.WaitGroup
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			counting.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report?year=2024", nil))
+		}(recorders[i])
+	}
+
+	arrived.Wait()
+	// Give the last arrivals time to register as waiters before releasing.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), runs.Load())
+	for _, rec := range recorders {
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "computed", rec.Header().Get("X-Result"))
+		assert.Equal(t, "expensive", rec.Body.String())
+	}
+
+	// Once the shared call has finished, a new request executes afresh.
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report?year=2024", nil))
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestCoalescingMiddleware_UnsafeMethodsAndKeys(t *testing.T) {
+	var runs atomic.Int32
+	handler := middleware.NewCoalescingMiddleware(true).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runs.Add(1)
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", nil))
+	assert.Equal(t, int32(1), runs.Load())
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Authorization", "Bearer a")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, "Bearer a", w.Body.String())
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCoalescingMiddleware_KeysOnCookies(t *testing.T) {
+	var runs atomic.Int32
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	release := make(chan struct{})
+
+	handler := middleware.NewCoalescingMiddleware(true).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runs.Add(1)
+		<-release
+		cookie, _ := r.Cookie("session")
+		_, _ = w.Write([]byte(cookie.Value))
+	}))
+	counting := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		handler.ServeHTTP(w, r)
+	})
+
+	sessions := []string{"alice", "bob"}
+	recorders := make([]*httptest.ResponseRecorder, len(sessions))
+	var wg sync.WaitGroup
+	for i, session := range sessions {
+		recorders[i] = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: session})
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			counting.ServeHTTP(rec, req)
+		}(recorders[i])
+	}
+
+	arrived.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), runs.Load())
+	for i, session := range sessions {
+		assert.Equal(t, session, recorders[i].Body.String())
+	}
+}
+
+func TestCoalescingMiddleware_PanicReleasesWaiters(t *testing.T) {
+	handler := middleware.NewCoalescingMiddleware(false).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", nil))
+	})
+	// The failed call must not be left in flight.
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", nil))
+	})
+}
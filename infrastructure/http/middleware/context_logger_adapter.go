@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// ContextLoggerMiddleware stores a request-scoped logger in the request
+// context. Handlers fetch it with applogger.LoggerFromContext and every entry
+// they log carries the request ID, tenant and user ID (see reqmeta) without
+// passing them explicitly, even when logging with a context detached from
+// the request.
+type ContextLoggerMiddleware struct {
+	log applogger.Logger
+}
+
+// NewContextLoggerMiddleware creates a new context logger middleware deriving
+// request-scoped loggers from log. It must run inside RequestIDMiddleware,
+// and inside any middleware that sets the tenant or user, for their values
+// to be picked up.
+func NewContextLoggerMiddleware(log applogger.Logger) *ContextLoggerMiddleware {
+	log = applogger.OrNop(log)
+	return &ContextLoggerMiddleware{
+		log: log,
+	}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (clm *ContextLoggerMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			log := clm.log
+			if id := reqmeta.RequestID(ctx); id != "" {
+				log = log.WithField("request_id", id)
+			}
+			if tenantID := reqmeta.Tenant(ctx); tenantID != "" {
+				log = log.WithField("tenant_id", tenantID)
+			}
+			if userID := reqmeta.UserID(ctx); userID != "" {
+				log = log.WithField("user_id", userID)
+			}
+
+			next.ServeHTTP(w, r.WithContext(applogger.ContextWithLogger(ctx, log)))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"github.com/next-trace/scg-service-api/application/tenant"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextLoggerMiddleware(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "info")
+
+	setTenant := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := reqmeta.WithUserID(tenant.WithTenant(r.Context(), "acme"), "user-7")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	handler := middleware.NewRequestIDMiddleware().Middleware()(setTenant(
+		middleware.NewContextLoggerMiddleware(log).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			// A detached context still gets the request-scoped fields.
+			applogger.LoggerFromContext(r.Context()).Info(context.Background(), "handled")
+			applogger.LoggerFromContext(r.Context()).Info(r.Context(), "handled with request context")
+		}))))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(logBuffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Contains(t, line, `"request_id":"req-42"`)
+		assert.Contains(t, line, `"tenant_id":"acme"`)
+		assert.Contains(t, line, `"user_id":"user-7"`)
+		assert.Equal(t, 1, strings.Count(line, `"request_id"`), "request_id logged once: %s", line)
+	}
+}
+
+func TestContextLoggerMiddleware_WithoutRequestID(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "info")
+
+	handler := middleware.NewContextLoggerMiddleware(log).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		applogger.LoggerFromContext(r.Context()).Info(r.Context(), "handled")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Contains(t, logBuffer.String(), `"msg":"handled"`)
+	assert.NotContains(t, logBuffer.String(), "request_id")
+}
+
+func TestLoggerFromContext_Default(t *testing.T) {
+	// Without the middleware handlers get a logger that discards entries.
+	log := applogger.LoggerFromContext(context.Background())
+	assert.NotNil(t, log)
+	log.Info(context.Background(), "dropped")
+}
@@ -1,3 +1,6 @@
-// Package middleware hosts HTTP middleware adapters (metrics, tracing, recovery, validation)
-// to compose cross-cutting concerns around net/http handlers.
+// Package middleware hosts HTTP middleware adapters (metrics, tracing, recovery,
+// request ID, request-scoped logging and responder, error rendering, access
+// logging, validation, OpenAPI contract checks, rate limiting, request
+// coalescing, hop counting) to compose cross-cutting concerns around net/http
+// handlers.
 package middleware
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+)
+
+// ErrorHandlerMiddleware adapts apphttp.ErrorHandler functions to
+// http.Handler, rendering every returned error in one place.
+type ErrorHandlerMiddleware struct {
+	responder apphttp.ResponseWriter
+}
+
+// NewErrorHandlerMiddleware creates an adapter rendering handler errors with
+// responder's Error, which maps domain errors to their status codes. A nil
+// responder defers to the one stored by ResponderMiddleware in the request
+// context; with neither, errors are answered with a plain 500.
+func NewErrorHandlerMiddleware(responder apphttp.ResponseWriter) *ErrorHandlerMiddleware {
+	return &ErrorHandlerMiddleware{
+		responder: responder,
+	}
+}
+
+// Handle returns an http.Handler running h and rendering its error, if any.
+func (em *ErrorHandlerMiddleware) Handle(h apphttp.ErrorHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+
+		responder := em.responder
+		if responder == nil {
+			responder = apphttp.Responder(r.Context())
+		}
+		if responder == nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		responder.Error(w, r, err)
+	})
+}
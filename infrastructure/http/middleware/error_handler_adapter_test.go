@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/serializer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandlerMiddleware(t *testing.T) {
+	responder := serializer.NewJSONAdapter()
+	em := middleware.NewErrorHandlerMiddleware(responder)
+
+	notFound := em.Handle(func(http.ResponseWriter, *http.Request) error {
+		return domainerrors.NewNotFound("item", "42")
+	})
+	rec := httptest.NewRecorder()
+	notFound.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "not_found")
+
+	ok := em.Handle(func(w http.ResponseWriter, r *http.Request) error {
+		responder.Respond(w, r, http.StatusOK, map[string]string{"id": "42"})
+		return nil
+	})
+	rec = httptest.NewRecorder()
+	ok.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestErrorHandlerMiddleware_ContextResponder(t *testing.T) {
+	handler := middleware.NewResponderMiddleware(serializer.NewJSONAdapter()).Middleware()(
+		middleware.NewErrorHandlerMiddleware(nil).Handle(func(http.ResponseWriter, *http.Request) error {
+			return domainerrors.NewNotFound("item", "42")
+		}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Without any responder the error still ends the request.
+	rec = httptest.NewRecorder()
+	middleware.NewErrorHandlerMiddleware(nil).Handle(func(http.ResponseWriter, *http.Request) error {
+		return errors.New("boom")
+	}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+)
+
+// HopCountHeader carries the number of service hops a request has made, so
+// call loops between services are cut off instead of running forever.
+const HopCountHeader = "X-Hop-Count"
+
+// HopCountMiddleware enforces a maximum hop count on incoming requests.
+type HopCountMiddleware struct {
+	maxHops int
+}
+
+// NewHopCountMiddleware creates a middleware that answers 508 Loop Detected
+// when a request's HopCountHeader exceeds maxHops, and 400 when the header
+// is not a non-negative integer. Accepted requests carry the incremented
+// count in their context (see reqmeta.HopCount), which HopCountTransport
+// sends on outbound calls. A missing header counts as 0.
+func NewHopCountMiddleware(maxHops int) *HopCountMiddleware {
+	return &HopCountMiddleware{maxHops: maxHops}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (hm *HopCountMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hops := 0
+			if value := r.Header.Get(HopCountHeader); value != "" {
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					http.Error(w, "Invalid "+HopCountHeader, http.StatusBadRequest)
+					return
+				}
+				hops = n
+			}
+			if hops > hm.maxHops {
+				http.Error(w, "Loop Detected", http.StatusLoopDetected)
+				return
+			}
+
+			ctx := reqmeta.WithHopCount(r.Context(), hops+1)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HopCountTransport is an http.RoundTripper that sets HopCountHeader on
+// outbound requests from the hop count in their context, so a service
+// behind HopCountMiddleware passes the count on to the services it calls.
+type HopCountTransport struct {
+	next http.RoundTripper
+}
+
+// NewHopCountTransport wraps next, or http.DefaultTransport when nil.
+func NewHopCountTransport(next http.RoundTripper) *HopCountTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &HopCountTransport{next: next}
+}
+
+// RoundTrip sets HopCountHeader when the request context carries a hop
+// count and forwards the request.
+func (t *HopCountTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	hops := reqmeta.HopCount(r.Context())
+	if hops == 0 {
+		return t.next.RoundTrip(r)
+	}
+	r2 := r.Clone(r.Context())
+	r2.Header.Set(HopCountHeader, strconv.Itoa(hops))
+	return t.next.RoundTrip(r2)
+}
@@ -0,0 +1,75 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHopCountMiddleware(t *testing.T) {
+	// downstream echoes the hop count it received.
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get(middleware.HopCountHeader)))
+	}))
+	t.Cleanup(downstream.Close)
+	client := &http.Client{Transport: middleware.NewHopCountTransport(nil)}
+
+	called := false
+	handler := middleware.NewHopCountMiddleware(3).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("outbound call: %v", err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(w, resp.Body)
+	}))
+
+	t.Run("Increments", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(middleware.HopCountHeader, "2")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Body.String())
+	})
+
+	t.Run("Missing header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, "1", w.Body.String())
+	})
+
+	t.Run("Over the limit", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(middleware.HopCountHeader, "4")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusLoopDetected, w.Code)
+		assert.False(t, called, "handler must not run once the hop limit is exceeded")
+	})
+
+	t.Run("Invalid header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(middleware.HopCountHeader, "-1")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
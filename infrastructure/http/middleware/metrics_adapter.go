@@ -3,20 +3,29 @@ package middleware
 
 import (
 	"bufio"
+	"context"
 	"net"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
 	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// inFlightMetric is the gauge of requests currently being served.
+const inFlightMetric = "http_requests_in_flight"
+
 // MetricsMiddleware provides middleware to collect metrics for HTTP requests.
 type MetricsMiddleware struct {
+	toggle
 	metrics appmetrics.Metrics
 }
 
-// NewMetricsMiddleware creates a new metrics middleware.
+// NewMetricsMiddleware creates a new metrics middleware. It starts enabled;
+// use SetEnabled to stop or resume recording at runtime.
 func NewMetricsMiddleware(metrics appmetrics.Metrics) *MetricsMiddleware {
 	return &MetricsMiddleware{
 		metrics: metrics,
@@ -27,15 +36,31 @@ func NewMetricsMiddleware(metrics appmetrics.Metrics) *MetricsMiddleware {
 func (mm *MetricsMiddleware) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !mm.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Track concurrent requests; the deferred decrement also runs on panic
+			mm.metrics.GaugeInc(inFlightMetric)
+			defer mm.metrics.GaugeDec(inFlightMetric)
+
 			// Create a response writer wrapper to capture the status code
 			rw := newResponseWriterWrapper(w)
 
 			// Start a timer for the request duration
-			stopTimer := mm.metrics.TimerStart("http_request_duration_seconds")
+			stopTimer := mm.startTimer(r)
 
 			// Increment the request counter
 			mm.metrics.CounterInc("http_requests_total")
 
+			// Give the handler somewhere to record its operation name
+			op, ok := r.Context().Value(operationNameKey).(*operationName)
+			if !ok {
+				op = &operationName{}
+				r = r.WithContext(context.WithValue(r.Context(), operationNameKey, op))
+			}
+
 			// Call the next handler
 			next.ServeHTTP(rw, r)
 
@@ -47,6 +72,7 @@ func (mm *MetricsMiddleware) Middleware() func(http.Handler) http.Handler {
 				"method":      r.Method,
 				"path":        r.URL.Path,
 				"status_code": strconv.Itoa(rw.statusCode),
+				"operation":   operationLabel(op, r),
 			}).CounterInc("http_requests_total")
 
 			// Record request size
@@ -65,12 +91,95 @@ func (mm *MetricsMiddleware) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
+// startTimer times the request. When the backend supports exemplars the
+// duration is recorded with the request's trace and request IDs, so a slow
+// bucket can be followed to its trace and log lines.
+func (mm *MetricsMiddleware) startTimer(r *http.Request) func() time.Duration {
+	const name = "http_request_duration_seconds"
+
+	observer, ok := mm.metrics.(appmetrics.ExemplarObserver)
+	if !ok {
+		return mm.metrics.TimerStart(name)
+	}
+	start := time.Now()
+	return func() time.Duration {
+		duration := time.Since(start)
+		observer.HistogramObserveWithExemplar(name, duration.Seconds(), requestExemplar(r.Context()))
+		return duration
+	}
+}
+
+// requestExemplar returns the trace and request IDs found in ctx, or nil.
+func requestExemplar(ctx context.Context) map[string]string {
+	exemplar := map[string]string{}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		exemplar["trace_id"] = sc.TraceID().String()
+	}
+	if id := reqmeta.RequestID(ctx); id != "" {
+		exemplar["request_id"] = id
+	}
+	if len(exemplar) == 0 {
+		return nil
+	}
+	return exemplar
+}
+
 // Metrics provides backward compatibility with the old API.
 // Deprecated: Use NewMetricsMiddleware instead.
 func Metrics(metrics appmetrics.Metrics) func(http.Handler) http.Handler {
 	return NewMetricsMiddleware(metrics).Middleware()
 }
 
+const operationNameKey contextKey = "operation_name"
+
+// operationName is the mutable slot the metrics middleware places in the
+// request context so handlers further down the chain can name themselves.
+type operationName struct {
+	mu   sync.Mutex
+	name string
+}
+
+// WithOperationName labels the current request with an operation name for the
+// http_requests_total metric. When the metrics middleware is in the chain the
+// name is recorded on the request it is observing and ctx is returned as is;
+// otherwise a context carrying the name is returned.
+func WithOperationName(ctx context.Context, name string) context.Context {
+	if op, ok := ctx.Value(operationNameKey).(*operationName); ok {
+		op.mu.Lock()
+		op.name = name
+		op.mu.Unlock()
+		return ctx
+	}
+	return context.WithValue(ctx, operationNameKey, &operationName{name: name})
+}
+
+// OperationName returns the operation name set with WithOperationName, if any.
+func OperationName(ctx context.Context) string {
+	op, ok := ctx.Value(operationNameKey).(*operationName)
+	if !ok {
+		return ""
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.name
+}
+
+// operationLabel picks the operation label: the handler-provided name, then
+// the ServeMux path template, then the raw path.
+func operationLabel(op *operationName, r *http.Request) string {
+	op.mu.Lock()
+	name := op.name
+	op.mu.Unlock()
+	switch {
+	case name != "":
+		return name
+	case r.Pattern != "":
+		return r.Pattern
+	default:
+		return r.URL.Path
+	}
+}
+
 // responseWriterWrapper wraps an http.ResponseWriter to capture the status code and bytes written.
 type responseWriterWrapper struct {
 	http.ResponseWriter
@@ -5,11 +5,13 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
 	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/testsupport"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -125,3 +127,108 @@ func TestMetricsMiddleware_SlowRequest(t *testing.T) {
 	// Should have incremented slow requests
 	assert.Equal(t, 1.0, fm.counters["http_slow_requests_total"])
 }
+
+// labelRecordingMetrics records the label sets used for each counter on top of fakeMetrics.
+type labelRecordingMetrics struct {
+	*fakeMetrics
+	labels  map[string]string
+	counted *[]map[string]string
+}
+
+func (m *labelRecordingMetrics) WithLabels(labels map[string]string) appmetrics.Metrics {
+	return &labelRecordingMetrics{fakeMetrics: m.fakeMetrics, labels: labels, counted: m.counted}
+}
+
+func (m *labelRecordingMetrics) CounterInc(name string) {
+	if m.labels != nil && name == "http_requests_total" {
+		*m.counted = append(*m.counted, m.labels)
+	}
+	m.fakeMetrics.CounterInc(name)
+}
+
+func TestMetricsMiddleware_OperationName(t *testing.T) {
+	var counted []map[string]string
+	fm := &labelRecordingMetrics{fakeMetrics: newFakeMetrics(), counted: &counted}
+	mw := middleware.NewMetricsMiddleware(fm)
+
+	named := mw.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.WithOperationName(r.Context(), "GetItem")
+		w.WriteHeader(http.StatusOK)
+	}))
+	named.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/42", nil))
+
+	unnamed := mw.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	unnamed.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/43", nil))
+
+	if assert.Len(t, counted, 2) {
+		assert.Equal(t, "GetItem", counted[0]["operation"])
+		assert.Equal(t, "/items/42", counted[0]["path"])
+		assert.Equal(t, "/items/43", counted[1]["operation"])
+	}
+}
+
+func TestOperationName_WithoutMiddleware(t *testing.T) {
+	ctx := middleware.WithOperationName(context.Background(), "ListItems")
+	assert.Equal(t, "ListItems", middleware.OperationName(ctx))
+	assert.Empty(t, middleware.OperationName(context.Background()))
+}
+
+func TestMetricsMiddleware_SetEnabled(t *testing.T) {
+	fm := newFakeMetrics()
+	mw := middleware.NewMetricsMiddleware(fm)
+	handler := mw.Middleware()(okHandler())
+	do := func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/widgets", nil))
+	}
+
+	mw.SetEnabled(false)
+	do()
+	assert.Empty(t, fm.counters, "disabled middleware records nothing")
+
+	mw.SetEnabled(true)
+	do()
+	assert.GreaterOrEqual(t, fm.counters["http_requests_total"], 1.0)
+}
+
+func TestMetricsMiddleware_InFlightGauge(t *testing.T) {
+	metrics := testsupport.NewMetrics()
+	const concurrent = 3
+
+	var entered sync.WaitGroup
+	entered.Add(concurrent)
+	release := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		entered.Done()
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.NewMetricsMiddleware(metrics).Middleware()(slow)
+
+	var done sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+		}()
+	}
+
+	entered.Wait()
+	assert.Equal(t, float64(concurrent), metrics.Gauge("http_requests_in_flight", nil))
+
+	close(release)
+	done.Wait()
+	assert.Equal(t, 0.0, metrics.Gauge("http_requests_in_flight", nil))
+
+	// A panicking handler still leaves the gauge balanced.
+	panicking := middleware.NewMetricsMiddleware(metrics).Middleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	func() {
+		defer func() { _ = recover() }()
+		panicking.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	}()
+	assert.Equal(t, 0.0, metrics.Gauge("http_requests_in_flight", nil))
+}
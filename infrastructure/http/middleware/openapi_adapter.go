@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	"github.com/next-trace/scg-service-api/infrastructure/serializer"
+)
+
+// OpenAPIOption customizes the OpenAPI validation middleware.
+type OpenAPIOption func(*OpenAPIMiddleware)
+
+// WithOpenAPIResponder sets the ResponseWriter used to render validation
+// failures. Defaults to the JSON serializer.
+func WithOpenAPIResponder(responder apphttp.ResponseWriter) OpenAPIOption {
+	return func(om *OpenAPIMiddleware) { om.responder = responder }
+}
+
+// WithResponseValidation also validates every response against the spec.
+// A non-conforming response is logged and replaced with a 500, which makes
+// contract drift visible; it buffers whole responses, so enable it in
+// development and testing rather than in production.
+func WithResponseValidation() OpenAPIOption {
+	return func(om *OpenAPIMiddleware) { om.validateResponses = true }
+}
+
+// WithOpenAPIMaxBodyBytes caps the request body read for validation; larger
+// bodies are rejected with 413 Request Entity Too Large. Defaults to
+// appvalidation.DefaultMaxBodyBytes; zero or less removes the limit.
+func WithOpenAPIMaxBodyBytes(n int64) OpenAPIOption {
+	return func(om *OpenAPIMiddleware) { om.maxBodyBytes = n }
+}
+
+// OpenAPIMiddleware validates requests, and optionally responses, against
+// an OpenAPI 3 document.
+type OpenAPIMiddleware struct {
+	toggle
+	engine            *openAPIEngine
+	log               applogger.Logger
+	responder         apphttp.ResponseWriter
+	validateResponses bool
+	maxBodyBytes      int64
+}
+
+// NewOpenAPIMiddleware creates a middleware validating against spec, a JSON
+// or YAML OpenAPI 3 document. Requests that do not conform are rejected
+// with 400 Bad Request; requests for operations the spec does not describe
+// are passed through, so the middleware can wrap a mux that also serves
+// endpoints such as health checks.
+//
+// Validation is done with kin-openapi (see docs/dependencies.md).
+func NewOpenAPIMiddleware(spec []byte, log applogger.Logger, opts ...OpenAPIOption) (*OpenAPIMiddleware, error) {
+	log = applogger.OrNop(log)
+	engine, err := newOpenAPIEngine(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+	om := &OpenAPIMiddleware{
+		engine:       engine,
+		log:          log,
+		responder:    serializer.NewJSONAdapter(),
+		maxBodyBytes: appvalidation.DefaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(om)
+		}
+	}
+	return om, nil
+}
+
+// Middleware returns an http.Handler middleware function.
+func (om *OpenAPIMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !om.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			route, ok := om.engine.findRoute(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if om.maxBodyBytes > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, om.maxBodyBytes)
+			}
+			body, err := readBody(r.Context(), r.Body)
+			if err != nil {
+				status := http.StatusBadRequest
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				om.responder.ErrorWithStatus(w, r, status, fmt.Errorf("failed to read request body: %w", err))
+				return
+			}
+			if err := om.engine.validateRequest(route, r, body); err != nil {
+				om.responder.ErrorWithStatus(w, r, http.StatusBadRequest, fmt.Errorf("request does not match the API specification: %w", err))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if !om.validateResponses {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			response := newBufferedResponse()
+			next.ServeHTTP(response, r)
+			status := response.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if err := om.engine.validateResponse(route, r, status, response.header, response.body.Bytes()); err != nil {
+				om.log.ErrorKV(r.Context(), err, "response does not match the API specification", map[string]interface{}{
+					"method": r.Method,
+					"path":   r.URL.Path,
+					"status": status,
+				})
+				om.responder.ErrorWithStatus(w, r, http.StatusInternalServerError, errors.New("response does not match the API specification"))
+				return
+			}
+			response.writeTo(w)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: items
+  version: "1.0"
+paths:
+  /items:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewItem'
+      responses:
+        201:
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+  /items/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        200:
+          description: ok
+components:
+  schemas:
+    NewItem:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name:
+          type: string
+          minLength: 1
+        tags:
+          type: array
+          items:
+            type: string
+    Item:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+`
+
+func TestOpenAPIMiddleware(t *testing.T) {
+	om, err := middleware.NewOpenAPIMiddleware([]byte(testOpenAPISpec), nil)
+	require.NoError(t, err)
+
+	called := false
+	handler := om.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{"Conforming body", http.MethodPost, "/items", `{"name":"widget","tags":["a"]}`, http.StatusOK},
+		{"Missing required property", http.MethodPost, "/items", `{"tags":["a"]}`, http.StatusBadRequest},
+		{"Wrong property type", http.MethodPost, "/items", `{"name":"widget","tags":[1]}`, http.StatusBadRequest},
+		{"Unknown property", http.MethodPost, "/items", `{"name":"widget","color":"red"}`, http.StatusBadRequest},
+		{"Missing body", http.MethodPost, "/items", ``, http.StatusBadRequest},
+		{"Conforming path parameter", http.MethodGet, "/items/42", ``, http.StatusOK},
+		{"Invalid path parameter", http.MethodGet, "/items/abc", ``, http.StatusBadRequest},
+		{"Undocumented path", http.MethodGet, "/health", ``, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			assert.Equal(t, tt.want == http.StatusOK, called)
+		})
+	}
+}
+
+func TestOpenAPIMiddleware_ResponseValidation(t *testing.T) {
+	log := testsupport.NewLogger()
+	om, err := middleware.NewOpenAPIMiddleware([]byte(testOpenAPISpec), log, middleware.WithResponseValidation())
+	require.NoError(t, err)
+
+	respondWith := func(body string) http.Handler {
+		return om.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":"widget"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	respondWith(`{"id":"1","name":"widget"}`).ServeHTTP(w, newRequest())
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":"1","name":"widget"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	respondWith(`{"name":"widget"}`).ServeHTTP(w, newRequest())
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	log.AssertLogged(t, "response does not match the API specification")
+}
+
+func TestOpenAPIMiddleware_BodyTooLarge(t *testing.T) {
+	om, err := middleware.NewOpenAPIMiddleware([]byte(testOpenAPISpec), nil, middleware.WithOpenAPIMaxBodyBytes(16))
+	require.NoError(t, err)
+	handler := om.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Fatal("handler must not be called for an oversized body")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":"a much longer widget name"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestNewOpenAPIMiddleware_InvalidSpec(t *testing.T) {
+	_, err := middleware.NewOpenAPIMiddleware([]byte(`swagger: "2.0"`), nil)
+	assert.Error(t, err)
+}
@@ -2,49 +2,147 @@
 package middleware
 
 import (
+	"context"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
 	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+	"github.com/next-trace/scg-service-api/application/tenant"
 )
 
+// KeyFunc derives the rate-limit key for a request.
+type KeyFunc func(r *http.Request) string
+
+// RateLimitOption customizes the rate limit middlewares.
+type RateLimitOption func(*rateLimitOptions)
+
+// CostFunc returns the number of tokens a request consumes.
+type CostFunc func(r *http.Request) int
+
+// RateLimitPolicy selects how a request over the limit is handled.
+type RateLimitPolicy int
+
+const (
+	// PolicyReject rejects requests over the limit with 429 Too Many Requests.
+	PolicyReject RateLimitPolicy = iota
+
+	// PolicyWait blocks requests over the limit until tokens are available
+	// or the request context is done.
+	PolicyWait
+)
+
+// PolicyFunc selects the RateLimitPolicy for a request, e.g. by route.
+type PolicyFunc func(r *http.Request) RateLimitPolicy
+
+// Names of the counters recorded when metrics are configured.
+const (
+	rateLimitAllowedMetric  = "rate_limit_allowed_total"
+	rateLimitRejectedMetric = "rate_limit_rejected_total"
+	rateLimitKeysMetric     = "rate_limit_active_keys"
+)
+
+type rateLimitOptions struct {
+	keyFunc    KeyFunc
+	costFunc   CostFunc
+	policyFunc PolicyFunc
+	metrics    appmetrics.Metrics
+	groupFunc  KeyFunc
+}
+
+// WithKeyFunc sets the function used to derive the rate-limit key from the request.
+// It takes precedence over the deprecated appratelimit.Config.KeyFunc.
+func WithKeyFunc(fn KeyFunc) RateLimitOption {
+	return func(o *rateLimitOptions) { o.keyFunc = fn }
+}
+
+// WithCostFunc sets the function used to weigh requests, so expensive endpoints
+// consume more tokens than cheap ones. Without it every request costs one token.
+func WithCostFunc(fn CostFunc) RateLimitOption {
+	return func(o *rateLimitOptions) { o.costFunc = fn }
+}
+
+// WithPolicyFunc lets RateLimitMiddleware choose per request whether to reject
+// or wait, so public routes can fail fast while internal routes are throttled.
+// Without it every request uses PolicyReject.
+func WithPolicyFunc(fn PolicyFunc) RateLimitOption {
+	return func(o *rateLimitOptions) { o.policyFunc = fn }
+}
+
+// WithMetrics counts allowed and rejected requests as rate_limit_allowed_total and
+// rate_limit_rejected_total, labeled with "route". group maps a request to that
+// label and should return a low-cardinality value such as a route pattern or key
+// group; when nil the request path is used. Limiters implementing
+// appratelimit.KeyCounter also report their key count as the
+// rate_limit_active_keys gauge.
+func WithMetrics(metrics appmetrics.Metrics, group KeyFunc) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.metrics = metrics
+		o.groupFunc = group
+	}
+}
+
+func newRateLimitOptions(opts []RateLimitOption) rateLimitOptions {
+	var o rateLimitOptions
+	for _, fn := range opts {
+		if fn != nil {
+			fn(&o)
+		}
+	}
+	return o
+}
+
 // RateLimitMiddleware provides middleware to limit the rate of requests.
 type RateLimitMiddleware struct {
+	toggle
 	limiter appratelimit.Limiter
 	config  appratelimit.Config
 	log     applogger.Logger
+	opts    rateLimitOptions
 }
 
-// NewRateLimitMiddleware creates a new rate limit middleware.
-func NewRateLimitMiddleware(limiter appratelimit.Limiter, config appratelimit.Config, log applogger.Logger) *RateLimitMiddleware {
-	return &RateLimitMiddleware{
+// NewRateLimitMiddleware creates a new rate limit middleware. config.Enabled
+// sets the initial state; use SetEnabled to change it at runtime. Requests
+// whose context carries appratelimit.WithRateLimitBypass are never limited.
+func NewRateLimitMiddleware(limiter appratelimit.Limiter, config appratelimit.Config, log applogger.Logger, opts ...RateLimitOption) *RateLimitMiddleware {
+	log = applogger.OrNop(log)
+	rl := &RateLimitMiddleware{
 		limiter: limiter,
 		config:  config,
 		log:     log,
+		opts:    newRateLimitOptions(opts),
 	}
+	rl.SetEnabled(config.Enabled)
+	return rl
 }
 
 // Middleware returns an http.Handler middleware function.
 func (rl *RateLimitMiddleware) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !rl.config.Enabled {
+			// Disabled, or the caller was marked with appratelimit.WithRateLimitBypass
+			if !rl.Enabled() || appratelimit.BypassFromContext(r.Context()) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Get the key for rate limiting
+			// Get the key and cost for rate limiting
 			key := rl.getKey(r)
+			cost := rl.opts.cost(r)
 
-			// Check if the request is allowed
-			if !rl.limiter.Allow(r.Context(), key) {
-				rl.log.WarnKV(r.Context(), "rate limit exceeded", map[string]interface{}{
-					"key":    key,
-					"path":   r.URL.Path,
-					"method": r.Method,
-				})
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			// Apply the policy selected for this request
+			allowed := false
+			if rl.opts.policy(r) == PolicyWait {
+				allowed = waitForTokens(w, r, rl.limiter, rl.log, key, cost)
+			} else {
+				allowed = rl.allow(w, r, key, cost)
+			}
+			rl.opts.record(r, rl.limiter, allowed)
+			if !allowed {
 				return
 			}
 
@@ -54,11 +152,62 @@ func (rl *RateLimitMiddleware) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
+// allow consumes cost tokens if available and otherwise writes a 429 response.
+func (rl *RateLimitMiddleware) allow(w http.ResponseWriter, r *http.Request, key string, cost int) bool {
+	if rl.limiter.AllowN(r.Context(), key, cost) {
+		return true
+	}
+
+	rl.log.WarnKV(r.Context(), "rate limit exceeded", map[string]interface{}{
+		"key":    key,
+		"cost":   cost,
+		"path":   r.URL.Path,
+		"method": r.Method,
+	})
+	setRetryAfter(w, rl.limiter.PeekN(r.Context(), key, cost))
+	http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	return false
+}
+
+// waitForTokens blocks until cost tokens are available and otherwise writes a 429 response.
+func waitForTokens(w http.ResponseWriter, r *http.Request, limiter appratelimit.Limiter, log applogger.Logger, key string, cost int) bool {
+	err := limiter.WaitN(r.Context(), key, cost)
+	if err == nil {
+		return true
+	}
+
+	log.WarnKV(r.Context(), "rate limit wait failed", map[string]interface{}{
+		"key":    key,
+		"path":   r.URL.Path,
+		"method": r.Method,
+		"error":  err.Error(),
+	})
+	http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	return false
+}
+
 // getKey returns a key for rate limiting based on the request.
-// If the config has a KeyFunc, it will be used. Otherwise, a default key will be generated.
 func (rl *RateLimitMiddleware) getKey(r *http.Request) string {
-	if rl.config.KeyFunc != nil {
-		return rl.config.KeyFunc(r.Context())
+	return rateLimitKey(r, rl.config, rl.opts)
+}
+
+// rateLimitKey returns a key for rate limiting based on the request.
+// The KeyFunc option is used first, then the default key, see defaultRateLimitKey.
+func rateLimitKey(r *http.Request, config appratelimit.Config, opts rateLimitOptions) string {
+	if opts.keyFunc != nil {
+		// Make the config available to TenantKeyFunc(nil).
+		ctx := context.WithValue(r.Context(), rateLimitConfigKey{}, config)
+		return opts.keyFunc(r.WithContext(ctx))
+	}
+	return defaultRateLimitKey(r, config)
+}
+
+// defaultRateLimitKey returns the key used without a KeyFunc option: the
+// deprecated config KeyFunc if set, otherwise a key based on the client's IP
+// address.
+func defaultRateLimitKey(r *http.Request, config appratelimit.Config) string {
+	if config.KeyFunc != nil {
+		return config.KeyFunc(r.Context())
 	}
 
 	// Default key is based on the client's IP address
@@ -66,6 +215,80 @@ func (rl *RateLimitMiddleware) getKey(r *http.Request) string {
 	return "ip:" + ip
 }
 
+// rateLimitConfigKey is the context key under which rateLimitKey passes the
+// middleware's config to a KeyFunc option.
+type rateLimitConfigKey struct{}
+
+// TenantKeyFunc scopes the keys produced by base to the tenant in the request
+// context (see tenant.WithTenant and tenant.Key), giving each tenant its own
+// buckets. A nil base uses the key the middleware would use without
+// WithKeyFunc: the deprecated config KeyFunc if set, otherwise the client IP.
+// Use it with WithKeyFunc.
+func TenantKeyFunc(base KeyFunc) KeyFunc {
+	if base == nil {
+		base = func(r *http.Request) string {
+			config, _ := r.Context().Value(rateLimitConfigKey{}).(appratelimit.Config)
+			return defaultRateLimitKey(r, config)
+		}
+	}
+	return func(r *http.Request) string {
+		return tenant.Key(r.Context(), base(r))
+	}
+}
+
+// cost returns the number of tokens r consumes, at least one.
+func (o rateLimitOptions) cost(r *http.Request) int {
+	if o.costFunc == nil {
+		return 1
+	}
+	if n := o.costFunc(r); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// policy returns the RateLimitPolicy for r.
+func (o rateLimitOptions) policy(r *http.Request) RateLimitPolicy {
+	if o.policyFunc == nil {
+		return PolicyReject
+	}
+	return o.policyFunc(r)
+}
+
+// record increments the allowed or rejected counter for r and, when the
+// limiter reports its key count, sets the active keys gauge. It is a no-op
+// when no metrics are configured.
+func (o rateLimitOptions) record(r *http.Request, limiter appratelimit.Limiter, allowed bool) {
+	if o.metrics == nil {
+		return
+	}
+
+	route := r.URL.Path
+	if o.groupFunc != nil {
+		route = o.groupFunc(r)
+	}
+
+	name := rateLimitRejectedMetric
+	if allowed {
+		name = rateLimitAllowedMetric
+	}
+	o.metrics.WithLabels(map[string]string{"route": route}).CounterInc(name)
+
+	if counter, ok := limiter.(appratelimit.KeyCounter); ok {
+		o.metrics.GaugeSet(rateLimitKeysMetric, float64(counter.Len()))
+	}
+}
+
+// setRetryAfter sets the Retry-After header to wait rounded up to whole seconds.
+// A rejected request always advertises at least one second.
+func setRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int64(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
 // getClientIP returns the client's IP address from the request.
 func getClientIP(r *http.Request) string {
 	// Check for X-Forwarded-For header
@@ -102,41 +325,46 @@ func RateLimit(limiter appratelimit.Limiter, config appratelimit.Config, log app
 // WaitRateLimitMiddleware provides middleware that waits for a token instead of rejecting the request.
 // This is useful for internal services where you want to throttle but not reject requests.
 type WaitRateLimitMiddleware struct {
+	toggle
 	limiter appratelimit.Limiter
 	config  appratelimit.Config
 	log     applogger.Logger
+	opts    rateLimitOptions
 }
 
 // NewWaitRateLimitMiddleware creates a new wait rate limit middleware.
-func NewWaitRateLimitMiddleware(limiter appratelimit.Limiter, config appratelimit.Config, log applogger.Logger) *WaitRateLimitMiddleware {
-	return &WaitRateLimitMiddleware{
+// config.Enabled sets the initial state; use SetEnabled to change it at runtime.
+// Requests whose context carries appratelimit.WithRateLimitBypass never wait.
+func NewWaitRateLimitMiddleware(limiter appratelimit.Limiter, config appratelimit.Config, log applogger.Logger, opts ...RateLimitOption) *WaitRateLimitMiddleware {
+	log = applogger.OrNop(log)
+	wrl := &WaitRateLimitMiddleware{
 		limiter: limiter,
 		config:  config,
 		log:     log,
+		opts:    newRateLimitOptions(opts),
 	}
+	wrl.SetEnabled(config.Enabled)
+	return wrl
 }
 
 // Middleware returns an http.Handler middleware function.
 func (wrl *WaitRateLimitMiddleware) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !wrl.config.Enabled {
+			// Disabled, or the caller was marked with appratelimit.WithRateLimitBypass
+			if !wrl.Enabled() || appratelimit.BypassFromContext(r.Context()) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Get the key for rate limiting
+			// Get the key and cost for rate limiting
 			key := wrl.getKey(r)
+			cost := wrl.opts.cost(r)
 
-			// Wait for a token
-			if err := wrl.limiter.Wait(r.Context(), key); err != nil {
-				wrl.log.WarnKV(r.Context(), "rate limit wait failed", map[string]interface{}{
-					"key":    key,
-					"path":   r.URL.Path,
-					"method": r.Method,
-					"error":  err.Error(),
-				})
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			// Wait for the tokens
+			allowed := waitForTokens(w, r, wrl.limiter, wrl.log, key, cost)
+			wrl.opts.record(r, wrl.limiter, allowed)
+			if !allowed {
 				return
 			}
 
@@ -147,15 +375,8 @@ func (wrl *WaitRateLimitMiddleware) Middleware() func(http.Handler) http.Handler
 }
 
 // getKey returns a key for rate limiting based on the request.
-// If the config has a KeyFunc, it will be used. Otherwise, a default key will be generated.
 func (wrl *WaitRateLimitMiddleware) getKey(r *http.Request) string {
-	if wrl.config.KeyFunc != nil {
-		return wrl.config.KeyFunc(r.Context())
-	}
-
-	// Default key is based on the client's IP address
-	ip := getClientIP(r)
-	return "ip:" + ip
+	return rateLimitKey(r, wrl.config, wrl.opts)
 }
 
 // WaitRateLimit provides backward compatibility with the old API.
@@ -0,0 +1,338 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+	"github.com/next-trace/scg-service-api/application/tenant"
+	"github.com/next-trace/scg-service-api/infrastructure/cache"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/next-trace/scg-service-api/infrastructure/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLimiterConfig returns a config allowing burst requests per key and refilling slowly.
+func newTestLimiterConfig(burst int) appratelimit.Config {
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1
+	cfg.Period = time.Hour
+	cfg.Burst = burst
+	return cfg
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRateLimitMiddleware_KeyFuncFromRequest(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "debug")
+	cfg := newTestLimiterConfig(1)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, log)
+
+	byAPIKey := middleware.WithKeyFunc(func(r *http.Request) string {
+		return "api-key:" + r.Header.Get("X-API-Key")
+	})
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, log, byAPIKey).Middleware()(okHandler())
+
+	do := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Both requests share the same client IP, so only the header separates them.
+	assert.Equal(t, http.StatusOK, do("alpha"))
+	assert.Equal(t, http.StatusTooManyRequests, do("alpha"))
+	assert.Equal(t, http.StatusOK, do("beta"))
+}
+
+func TestRateLimitMiddleware_RetryAfter(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "debug")
+	cfg := newTestLimiterConfig(2)
+	cfg.Rate = 1
+	cfg.Period = 10 * time.Second // one token every 10s
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, log)
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, log).Middleware()(okHandler())
+
+	var last *httptest.ResponseRecorder
+	for range 3 {
+		last = httptest.NewRecorder()
+		handler.ServeHTTP(last, httptest.NewRequest(http.MethodGet, "/items", nil))
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, last.Code)
+	assert.Equal(t, "10", last.Header().Get("Retry-After"))
+
+	// Computing Retry-After must not consume tokens.
+	assert.InDelta(t, 10*time.Second, limiter.Peek(t.Context(), "ip:192.0.2.1"), float64(100*time.Millisecond))
+}
+
+func TestRateLimitMiddleware_CostFunc(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "debug")
+	cfg := newTestLimiterConfig(5)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, log)
+
+	searchCostsThree := middleware.WithCostFunc(func(r *http.Request) int {
+		if r.URL.Path == "/search" {
+			return 3
+		}
+		return 1
+	})
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, log, searchCostsThree).Middleware()(okHandler())
+
+	do := func(path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	// 5 tokens: a search takes 3, leaving 2 for cheap requests.
+	assert.Equal(t, http.StatusOK, do("/search"))
+	assert.Equal(t, http.StatusTooManyRequests, do("/search"))
+	assert.Equal(t, http.StatusOK, do("/health"))
+	assert.Equal(t, http.StatusOK, do("/health"))
+	assert.Equal(t, http.StatusTooManyRequests, do("/health"))
+}
+
+func TestRateLimitMiddleware_PolicyPerRoute(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "debug")
+	cfg := newTestLimiterConfig(1)
+	cfg.Rate = 20
+	cfg.Period = time.Second // one token every 50ms
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, log)
+
+	waitOnInternal := middleware.WithPolicyFunc(func(r *http.Request) middleware.RateLimitPolicy {
+		if strings.HasPrefix(r.URL.Path, "/internal/") {
+			return middleware.PolicyWait
+		}
+		return middleware.PolicyReject
+	})
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, log, waitOnInternal).Middleware()(okHandler())
+
+	do := func(path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	// Exhaust the bucket, then the public route is rejected immediately.
+	assert.Equal(t, http.StatusOK, do("/public/items"))
+	assert.Equal(t, http.StatusTooManyRequests, do("/public/items"))
+
+	// The internal route blocks until a token refills, then proceeds.
+	start := time.Now()
+	assert.Equal(t, http.StatusOK, do("/internal/sync"))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+// routeCounterMetrics records counter increments per "route" label on top of fakeMetrics.
+type routeCounterMetrics struct {
+	*fakeMetrics
+	route  string
+	counts map[string]int
+}
+
+func newRouteCounterMetrics() *routeCounterMetrics {
+	return &routeCounterMetrics{fakeMetrics: newFakeMetrics(), counts: map[string]int{}}
+}
+
+func (m *routeCounterMetrics) WithLabels(labels map[string]string) appmetrics.Metrics {
+	return &routeCounterMetrics{fakeMetrics: m.fakeMetrics, route: labels["route"], counts: m.counts}
+}
+
+func (m *routeCounterMetrics) CounterInc(name string) { m.counts[name+"{route="+m.route+"}"]++ }
+
+func TestRateLimitMiddleware_Metrics(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "debug")
+	cfg := newTestLimiterConfig(1)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, log)
+	metrics := newRouteCounterMetrics()
+
+	group := func(*http.Request) string { return "items" }
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, log, middleware.WithMetrics(metrics, group)).Middleware()(okHandler())
+
+	for _, want := range []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+		assert.Equal(t, want, w.Code)
+	}
+
+	assert.Equal(t, 1, metrics.counts["rate_limit_allowed_total{route=items}"])
+	assert.Equal(t, 2, metrics.counts["rate_limit_rejected_total{route=items}"])
+	assert.Equal(t, 1.0, metrics.gauges["rate_limit_active_keys"])
+}
+
+func TestTenantKeyFunc_IsolatesTenants(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "debug")
+	cfg := newTestLimiterConfig(1)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, log)
+	c := cache.NewMemoryAdapter(appcache.DefaultConfig(), log)
+
+	// The handler caches a per-tenant value under the same logical key.
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, log, middleware.WithKeyFunc(middleware.TenantKeyFunc(nil))).
+		Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := tenant.FromContext(r.Context())
+		_ = c.Set(r.Context(), tenant.Key(r.Context(), "profile"), id, 0)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(tenantID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req = req.WithContext(tenant.WithTenant(req.Context(), tenantID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Same client IP, but each tenant has its own bucket.
+	assert.Equal(t, http.StatusOK, serve("acme"))
+	assert.Equal(t, http.StatusOK, serve("globex"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("acme"))
+
+	ctx := t.Context()
+	acme, _ := c.Get(ctx, tenant.Key(tenant.WithTenant(ctx, "acme"), "profile"))
+	globex, _ := c.Get(ctx, tenant.Key(tenant.WithTenant(ctx, "globex"), "profile"))
+	assert.Equal(t, "acme", acme)
+	assert.Equal(t, "globex", globex)
+}
+
+func TestTenantKeyFunc_NilBaseUsesConfigKeyFunc(t *testing.T) {
+	cfg := newTestLimiterConfig(1)
+	cfg.KeyFunc = func(context.Context) string { return "account:7" }
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, nil)
+	handler := middleware.NewRateLimitMiddleware(limiter, cfg, nil, middleware.WithKeyFunc(middleware.TenantKeyFunc(nil))).
+		Middleware()(okHandler())
+
+	serve := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.RemoteAddr = remoteAddr
+		req = req.WithContext(tenant.WithTenant(req.Context(), "acme"))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Different client IPs share the configured key within the tenant.
+	assert.Equal(t, http.StatusOK, serve("10.0.0.1:1234"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.2:1234"))
+}
+
+func TestRateLimitMiddleware_SetEnabled(t *testing.T) {
+	cfg := newTestLimiterConfig(1)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, nil)
+	cfg.Enabled = false
+	mw := middleware.NewRateLimitMiddleware(limiter, cfg, nil)
+	handler := mw.Middleware()(okHandler())
+	do := func() int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+		return w.Code
+	}
+
+	assert.False(t, mw.Enabled(), "initial state follows config.Enabled")
+	assert.Equal(t, http.StatusOK, do())
+	assert.Equal(t, http.StatusOK, do())
+
+	mw.SetEnabled(true)
+	assert.Equal(t, http.StatusOK, do())
+	assert.Equal(t, http.StatusTooManyRequests, do())
+
+	mw.SetEnabled(false)
+	assert.Equal(t, http.StatusOK, do())
+}
+
+func TestRateLimitMiddleware_Bypass(t *testing.T) {
+	cfg := newTestLimiterConfig(1)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, nil)
+
+	// A stand-in for an auth middleware: it verifies the credential and
+	// records the caller's identity in the context.
+	serviceTokens := map[string]string{"inventory-secret": "svc-inventory"}
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if userID, ok := serviceTokens[token]; ok {
+				r = r.WithContext(reqmeta.WithUserID(r.Context(), userID))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	// Bypass is granted on the verified identity only, never on request data.
+	internalServices := map[string]bool{"svc-inventory": true}
+	markInternal := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if internalServices[reqmeta.UserID(r.Context())] {
+				r = r.WithContext(appratelimit.WithRateLimitBypass(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	handler := authenticate(markInternal(middleware.NewRateLimitMiddleware(limiter, cfg, nil).Middleware()(okHandler())))
+
+	do := func(header, value string) int {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do("", ""))
+	assert.Equal(t, http.StatusTooManyRequests, do("", ""))
+	assert.Equal(t, http.StatusTooManyRequests, do("Authorization", "Bearer forged"))
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, do("Authorization", "Bearer inventory-secret"))
+	}
+}
+
+func TestWaitRateLimitMiddleware_ClientDisconnect(t *testing.T) {
+	cfg := newTestLimiterConfig(1)
+	limiter := ratelimit.NewTokenBucketLimiter(cfg, nil)
+	called := 0
+	handler := middleware.NewWaitRateLimitMiddleware(limiter, cfg, nil).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The next token is an hour away; the client gives up while waiting.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler still parked after the request context was cancelled")
+	}
+	assert.Equal(t, 1, called)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"runtime/debug"
 
@@ -14,6 +15,7 @@ type RecoveryMiddleware struct {
 
 // NewRecoveryMiddleware creates a new recovery middleware.
 func NewRecoveryMiddleware(log applogger.Logger) *RecoveryMiddleware {
+	log = applogger.OrNop(log)
 	return &RecoveryMiddleware{
 		log: log,
 	}
@@ -24,10 +26,10 @@ func (rm *RecoveryMiddleware) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
-				if err := recover(); err != nil {
-					rm.log.ErrorKV(r.Context(), nil, "panic recovered", map[string]interface{}{
+				if v := recover(); v != nil {
+					rm.log.ErrorKV(r.Context(), panicError(v), "panic recovered", map[string]interface{}{
 						"stack": string(debug.Stack()),
-						"error": err,
+						"panic": fmt.Sprintf("%+v", v),
 					})
 					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 				}
@@ -37,6 +39,18 @@ func (rm *RecoveryMiddleware) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
+// panicError turns a recovered panic value into the error that is logged.
+// Errors are wrapped, so errors.Is and errors.As still find the panic's cause;
+// other values are formatted with %+v, so the fields of custom types show.
+// The panic field of the log entry holds the value formatted with %+v as
+// well, which includes the stack trace of errors that carry one.
+func panicError(v interface{}) error {
+	if err, ok := v.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %+v", v)
+}
+
 // Recovery provides backward compatibility with the old API.
 // Deprecated: Use NewRecoveryMiddleware instead.
 func Recovery(log applogger.Logger) func(http.Handler) http.Handler {
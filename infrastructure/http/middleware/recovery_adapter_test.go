@@ -2,12 +2,15 @@ package middleware_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
 	"github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/next-trace/scg-service-api/testsupport"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -117,3 +120,18 @@ func TestRecovery(t *testing.T) {
 		assert.Contains(t, logOutput, "stack")
 	})
 }
+
+func TestRecovery_PreservesErrorChain(t *testing.T) {
+	log := testsupport.NewLogger()
+	cause := errors.New("connection lost")
+	handler := &mockHandler{shouldPanic: true, panicValue: fmt.Errorf("loading item: %w", cause)}
+
+	w := httptest.NewRecorder()
+	middleware.NewRecoveryMiddleware(log).Middleware()(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	entry := log.AssertLogged(t, "panic recovered")
+	assert.ErrorIs(t, entry.Err, cause)
+	assert.Contains(t, entry.Err.Error(), "loading item: connection lost")
+	assert.Contains(t, entry.Fields["stack"], "goroutine")
+}
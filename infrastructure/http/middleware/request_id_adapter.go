@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header a request ID is read from and echoed in.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs; longer or
+// non-printable values are replaced with a generated ID.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware assigns every request an ID, taken from the X-Request-ID
+// header when the client sent a usable one and generated otherwise.
+type RequestIDMiddleware struct{}
+
+// NewRequestIDMiddleware creates a new request ID middleware. The ID is echoed
+// in the response header, available via reqmeta.RequestID, added to the
+// request's log fields and baggage as "request_id" and set as the request.id
+// attribute of the current span. When the tracing middleware runs inside this
+// one it sets that attribute on the span it starts.
+func NewRequestIDMiddleware() *RequestIDMiddleware {
+	return &RequestIDMiddleware{}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (rim *RequestIDMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			ctx := reqmeta.WithRequestID(r.Context(), id)
+			ctx = applogger.ContextWithFields(ctx, map[string]interface{}{"request_id": id})
+			if member, err := baggage.NewMemberRaw("request_id", id); err == nil {
+				if bag, err := baggage.FromContext(ctx).SetMember(member); err == nil {
+					ctx = baggage.ContextWithBaggage(ctx, bag)
+				}
+			}
+			trace.SpanFromContext(ctx).SetAttributes(requestIDAttribute(id))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID assigned by RequestIDMiddleware,
+// or "" if there is none. It is equivalent to reqmeta.RequestID.
+func RequestIDFromContext(ctx context.Context) string {
+	return reqmeta.RequestID(ctx)
+}
+
+// requestIDAttribute is the span attribute carrying the request ID.
+func requestIDAttribute(id string) attribute.KeyValue {
+	return attribute.String("request.id", id)
+}
+
+// validRequestID reports whether a client-supplied ID is safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit hex ID.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen, logged string
+	handler := middleware.NewRequestIDMiddleware().Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = middleware.RequestIDFromContext(r.Context())
+		logged, _ = applogger.FieldsFromContext(r.Context())["request_id"].(string)
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"Client ID kept", "req-123", true},
+		{"Missing ID generated", "", false},
+		{"Unsafe ID replaced", "bad id\n", false},
+		{"Oversized ID replaced", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			echoed := w.Header().Get(middleware.RequestIDHeader)
+			assert.NotEmpty(t, echoed)
+			assert.Equal(t, echoed, seen)
+			assert.Equal(t, echoed, logged)
+			if tt.keep {
+				assert.Equal(t, tt.header, echoed)
+			} else {
+				assert.Len(t, echoed, 32)
+			}
+		})
+	}
+}
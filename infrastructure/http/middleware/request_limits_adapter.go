@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// RequestLimitsMiddleware rejects oversized requests before they reach the handler.
+type RequestLimitsMiddleware struct {
+	maxHeaderBytes int64
+	maxBodyBytes   int64
+}
+
+// NewRequestLimitsMiddleware creates a middleware that limits the total size of request
+// headers and bodies. A limit of 0 or less disables the corresponding check.
+func NewRequestLimitsMiddleware(maxHeaderBytes, maxBodyBytes int64) *RequestLimitsMiddleware {
+	return &RequestLimitsMiddleware{
+		maxHeaderBytes: maxHeaderBytes,
+		maxBodyBytes:   maxBodyBytes,
+	}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (rlm *RequestLimitsMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rlm.maxHeaderBytes > 0 && headerSize(r.Header) > rlm.maxHeaderBytes {
+				http.Error(w, "Request Header Fields Too Large", http.StatusRequestHeaderFieldsTooLarge)
+				return
+			}
+
+			if rlm.maxBodyBytes > 0 {
+				// Reject early when the declared length is already over the limit
+				if r.ContentLength > rlm.maxBodyBytes {
+					http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+					return
+				}
+
+				// Enforce the limit while reading for chunked or misreported bodies
+				r.Body = http.MaxBytesReader(w, r.Body, rlm.maxBodyBytes)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// headerSize approximates the wire size of the header block as "Name: value\r\n" per value.
+func headerSize(header http.Header) int64 {
+	var size int64
+	for name, values := range header {
+		for _, value := range values {
+			size += int64(len(name) + len(value) + 4)
+		}
+	}
+	return size
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLimitsMiddleware(t *testing.T) {
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	wrapped := middleware.NewRequestLimitsMiddleware(256, 10).Middleware()(handler)
+
+	t.Run("Declared length over limit", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("this body is too long"))
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.False(t, called, "handler must not run for oversized requests")
+	})
+
+	t.Run("Undeclared length enforced on read", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("this body is too long"))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("Headers over limit", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("X-Large", strings.Repeat("a", 300))
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, w.Code)
+		assert.False(t, called)
+	})
+
+	t.Run("Within limits", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("small"))
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
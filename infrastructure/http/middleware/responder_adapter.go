@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+	"github.com/next-trace/scg-service-api/infrastructure/serializer"
+)
+
+// ResponderMiddleware makes a ResponseWriter available to handlers through
+// apphttp.Responder, so they render responses consistently without having it
+// passed to them. The http.ResponseWriter is wrapped with
+// serializer.NewGuardedWriter, so a handler that responds twice sends only its
+// first response.
+type ResponderMiddleware struct {
+	responder apphttp.ResponseWriter
+}
+
+// NewResponderMiddleware creates a new responder middleware storing responder
+// in every request's context.
+func NewResponderMiddleware(responder apphttp.ResponseWriter) *ResponderMiddleware {
+	return &ResponderMiddleware{
+		responder: responder,
+	}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (rm *ResponderMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rm.responder == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := apphttp.ContextWithResponder(r.Context(), rm.responder)
+			next.ServeHTTP(serializer.NewGuardedWriter(w), r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/serializer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponderMiddleware(t *testing.T) {
+	responder := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{Envelope: true})
+
+	var got apphttp.ResponseWriter
+	handler := middleware.NewResponderMiddleware(responder).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = apphttp.Responder(r.Context())
+		got.Respond(w, r, http.StatusOK, map[string]string{"hello": "world"})
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Same(t, responder, got)
+	assert.JSONEq(t, `{"data":{"hello":"world"}}`, rec.Body.String())
+}
+
+func TestResponderMiddleware_GuardsSecondWrite(t *testing.T) {
+	responder := serializer.NewJSONAdapter()
+
+	handler := middleware.NewResponderMiddleware(responder).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apphttp.Responder(r.Context()).ErrorWithStatus(w, r, http.StatusBadRequest, errors.New("bad input"))
+		apphttp.Responder(r.Context()).Respond(w, r, http.StatusOK, map[string]string{"hello": "world"})
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hello")
+}
+
+func TestResponder_Missing(t *testing.T) {
+	assert.Nil(t, apphttp.Responder(context.Background()))
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	"github.com/next-trace/scg-service-api/application/tracing"
+)
+
+// StackDeps provides the dependencies of DefaultStack and switches off
+// individual middlewares. A middleware whose dependency is nil is omitted too.
+type StackDeps struct {
+	Logger  applogger.Logger
+	Metrics appmetrics.Metrics
+	Tracer  tracing.Tracer
+
+	// AccessLogOptions customize the access log, e.g. WithAccessLogSampling.
+	AccessLogOptions []AccessLogOption
+
+	DisableRecovery      bool
+	DisableRequestID     bool
+	DisableContextLogger bool
+	DisableTracing       bool
+	DisableAccessLog     bool
+	DisableMetrics       bool
+}
+
+// DefaultStack composes the standard middlewares, outermost first:
+//
+//	request ID -> context logger -> tracing -> access log -> metrics -> recovery -> handler
+//
+// Recovery sits directly around the handler, so a handler panic is turned
+// into a 500 that the access log, metrics and tracing middlewares observe
+// like any other response, and the panic is logged with the request ID. The
+// request ID and span are set up before the access log and metrics run, so
+// their entries can be correlated with the handler's own logs, and handlers
+// get a logger carrying the request ID from applogger.LoggerFromContext.
+func DefaultStack(deps StackDeps) func(http.Handler) http.Handler {
+	var stack []func(http.Handler) http.Handler
+	if !deps.DisableRequestID {
+		stack = append(stack, NewRequestIDMiddleware().Middleware())
+	}
+	if !deps.DisableContextLogger && deps.Logger != nil {
+		stack = append(stack, NewContextLoggerMiddleware(deps.Logger).Middleware())
+	}
+	if !deps.DisableTracing && deps.Tracer != nil {
+		stack = append(stack, NewTracingMiddleware(deps.Tracer).Middleware())
+	}
+	if !deps.DisableAccessLog && deps.Logger != nil {
+		stack = append(stack, NewAccessLogMiddleware(deps.Logger, deps.AccessLogOptions...).Middleware())
+	}
+	if !deps.DisableMetrics && deps.Metrics != nil {
+		stack = append(stack, NewMetricsMiddleware(deps.Metrics).Middleware())
+	}
+	if !deps.DisableRecovery && deps.Logger != nil {
+		stack = append(stack, NewRecoveryMiddleware(deps.Logger).Middleware())
+	}
+
+	return func(next http.Handler) http.Handler {
+		for i := len(stack) - 1; i >= 0; i-- {
+			next = stack[i](next)
+		}
+		return next
+	}
+}
@@ -0,0 +1,134 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apptracing "github.com/next-trace/scg-service-api/application/tracing"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/next-trace/scg-service-api/infrastructure/tracing"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exemplarMetrics records histogram exemplars on top of fakeMetrics.
+type exemplarMetrics struct {
+	*fakeMetrics
+	exemplars []map[string]string
+}
+
+func (m *exemplarMetrics) HistogramObserveWithExemplar(name string, value float64, exemplar map[string]string) {
+	m.HistogramObserve(name, value)
+	m.exemplars = append(m.exemplars, exemplar)
+}
+
+func TestDefaultStack(t *testing.T) {
+	t.Run("Recovery runs inside observability", func(t *testing.T) {
+		var logBuffer bytes.Buffer
+		var counted []map[string]string
+		fm := &labelRecordingMetrics{fakeMetrics: newFakeMetrics(), counted: &counted}
+		stack := middleware.DefaultStack(middleware.StackDeps{Logger: logger.NewSlogAdapter(&logBuffer, "info"), Metrics: fm})
+
+		w := httptest.NewRecorder()
+		stack(&mockHandler{shouldPanic: true, panicValue: "boom"}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		requestID := w.Header().Get(middleware.RequestIDHeader)
+		assert.NotEmpty(t, requestID)
+		out := logBuffer.String()
+		assert.Contains(t, out, "panic recovered")
+		// The 500 passed back out through the access log and metrics middlewares.
+		assert.Contains(t, out, `"msg":"http request"`)
+		assert.Contains(t, out, `"status":500`)
+		assert.Equal(t, 2, strings.Count(out, `"request_id":"`+requestID+`"`))
+		if assert.Len(t, counted, 1) {
+			assert.Equal(t, "500", counted[0]["status_code"])
+		}
+	})
+
+	t.Run("Request ID wraps access log", func(t *testing.T) {
+		var logBuffer bytes.Buffer
+		stack := middleware.DefaultStack(middleware.StackDeps{Logger: logger.NewSlogAdapter(&logBuffer, "info")})
+
+		w := httptest.NewRecorder()
+		stack(&mockHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, logBuffer.String(), `"request_id":"`+w.Header().Get(middleware.RequestIDHeader)+`"`)
+	})
+
+	t.Run("Disabled middlewares are omitted", func(t *testing.T) {
+		var logBuffer bytes.Buffer
+		stack := middleware.DefaultStack(middleware.StackDeps{
+			Logger:           logger.NewSlogAdapter(&logBuffer, "info"),
+			DisableRecovery:  true,
+			DisableRequestID: true,
+		})
+
+		w := httptest.NewRecorder()
+		handler := stack(&mockHandler{shouldPanic: true, panicValue: "boom"})
+		assert.Panics(t, func() { handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil)) })
+		assert.Empty(t, w.Header().Get(middleware.RequestIDHeader))
+	})
+}
+
+func TestDefaultStack_CorrelatesLogsSpansAndExemplars(t *testing.T) {
+	exporter := testsupport.NewSpanExporter()
+	tracer, err := tracing.NewOtelAdapterWithOptions(apptracing.Config{ServiceName: "test"},
+		tracing.WithExporter(exporter), tracing.WithSampler(sdktrace.AlwaysSample()))
+	if err != nil {
+		t.Fatalf("tracer: %v", err)
+	}
+
+	var logBuffer bytes.Buffer
+	log := logger.NewSlogAdapter(&logBuffer, "info")
+	metrics := &exemplarMetrics{fakeMetrics: newFakeMetrics()}
+
+	stack := middleware.DefaultStack(middleware.StackDeps{Logger: log, Metrics: metrics, Tracer: tracer, DisableAccessLog: true})
+	handler := stack(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Info(r.Context(), "handled")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	var entry struct {
+		TraceID   string `json:"trace_id"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(logBuffer.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log line: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	var spanRequestID string
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "request.id" {
+			spanRequestID = attr.Value.AsString()
+		}
+	}
+
+	if assert.Len(t, metrics.exemplars, 1) {
+		exemplar := metrics.exemplars[0]
+		assert.Equal(t, spans[0].SpanContext.TraceID().String(), exemplar["trace_id"])
+		assert.Equal(t, exemplar["trace_id"], entry.TraceID)
+		assert.Equal(t, "req-42", exemplar["request_id"])
+		assert.Equal(t, "req-42", entry.RequestID)
+		assert.Equal(t, "req-42", spanRequestID)
+	}
+}
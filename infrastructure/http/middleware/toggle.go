@@ -0,0 +1,21 @@
+package middleware
+
+import "sync/atomic"
+
+// toggle switches a middleware between its normal behavior and a plain
+// passthrough. It is checked on every request, so a hot-reloaded Config can
+// be applied with SetEnabled without rebuilding the handler chain. The zero
+// value is enabled.
+type toggle struct {
+	disabled atomic.Bool
+}
+
+// SetEnabled turns the middleware on or off for subsequent requests.
+func (t *toggle) SetEnabled(enabled bool) {
+	t.disabled.Store(!enabled)
+}
+
+// Enabled reports whether the middleware is currently active.
+func (t *toggle) Enabled() bool {
+	return !t.disabled.Load()
+}
@@ -2,22 +2,30 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/next-trace/scg-service-api/application/http/reqmeta"
 	"github.com/next-trace/scg-service-api/application/tracing"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 )
 
+// ForceTraceHeader forces the request span to be sampled when set to "1" or
+// "true", regardless of the configured sampling rate. Edge proxies should
+// strip it from untrusted traffic.
+const ForceTraceHeader = "X-Force-Trace"
+
 // TracingMiddleware provides middleware to handle trace propagation.
 type TracingMiddleware struct {
 	tracer     tracing.Tracer
 	propagator propagation.TextMapPropagator
 }
 
-// NewTracingMiddleware creates a new tracing middleware.
+// NewTracingMiddleware creates a new tracing middleware. A nil tracer is
+// replaced with tracing.Nop().
 func NewTracingMiddleware(tracer tracing.Tracer) *TracingMiddleware {
 	return &TracingMiddleware{
-		tracer:     tracer,
+		tracer:     tracing.OrNop(tracer),
 		propagator: otel.GetTextMapPropagator(),
 	}
 }
@@ -28,6 +36,9 @@ func (tm *TracingMiddleware) Middleware() func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Extract trace context from incoming request
 			ctx := tm.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			if forceTrace(r.Header.Get(ForceTraceHeader)) {
+				ctx = tracing.ContextWithForceSample(ctx)
+			}
 
 			// Start a new span
 			spanCtx, endSpan := tm.tracer.Start(ctx, r.URL.Path)
@@ -39,13 +50,22 @@ func (tm *TracingMiddleware) Middleware() func(http.Handler) http.Handler {
 				"http.url":    r.URL.String(),
 				"http.host":   r.Host,
 			})
+			if id := reqmeta.RequestID(spanCtx); id != "" {
+				tm.tracer.SetAttributes(spanCtx, map[string]string{"request.id": id})
+			}
 
-			// Pass the new context with the span down to the next handlers
-			next.ServeHTTP(w, r.WithContext(spanCtx))
+			// Pass the new context with the span down to the next handlers,
+			// along with the tracer for tracing.AddSpanAttributes and friends
+			next.ServeHTTP(w, r.WithContext(tracing.ContextWithTracer(spanCtx, tm.tracer)))
 		})
 	}
 }
 
+// forceTrace reports whether a ForceTraceHeader value requests sampling.
+func forceTrace(value string) bool {
+	return value == "1" || strings.EqualFold(value, "true")
+}
+
 // Tracing provides backward compatibility with the old API.
 // Deprecated: Use NewTracingMiddleware instead.
 func Tracing(serviceName string) func(http.Handler) http.Handler {
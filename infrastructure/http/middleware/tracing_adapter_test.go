@@ -2,13 +2,19 @@ package middleware_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	apptracing "github.com/next-trace/scg-service-api/application/tracing"
 	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/tracing"
+	"github.com/next-trace/scg-service-api/testsupport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -116,3 +122,83 @@ func TestDeprecatedTracing(t *testing.T) {
 		// We can't easily verify the tracing behavior since it uses global state
 	})
 }
+
+func TestTracingMiddleware_HandlerSpanHelpers(t *testing.T) {
+	exporter := testsupport.NewSpanExporter()
+	tracer, err := tracing.NewOtelAdapterWithOptions(apptracing.Config{ServiceName: "test"},
+		tracing.WithExporter(exporter), tracing.WithSampler(sdktrace.AlwaysSample()))
+	if err != nil {
+		t.Fatalf("tracer: %v", err)
+	}
+
+	handler := middleware.NewTracingMiddleware(tracer).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apptracing.AddSpanAttributes(r.Context(), map[string]string{"item.id": "42"})
+		apptracing.RecordSpanError(r.Context(), errors.New("lookup failed"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/42", nil))
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, 1) {
+		return
+	}
+	var itemID string
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "item.id" {
+			itemID = attr.Value.AsString()
+		}
+	}
+	assert.Equal(t, "42", itemID)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Len(t, spans[0].Events, 1, "the error is recorded as a span event")
+}
+
+func TestSpanHelpers_NoTracer(t *testing.T) {
+	// Without the middleware the helpers are no-ops.
+	assert.NotPanics(t, func() {
+		apptracing.AddSpanAttributes(context.Background(), map[string]string{"k": "v"})
+		apptracing.RecordSpanError(context.Background(), errors.New("boom"))
+	})
+}
+
+func TestTracingMiddleware_NilTracer(t *testing.T) {
+	handler := middleware.NewTracingMiddleware(nil).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apptracing.AddSpanAttributes(r.Context(), map[string]string{"k": "v"})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	assert.NotPanics(t, func() { handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil)) })
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestTracingMiddleware_ForceTraceHeader(t *testing.T) {
+	exporter := testsupport.NewSpanExporter()
+	tracer, err := tracing.NewOtelAdapterWithOptions(apptracing.Config{ServiceName: "test"},
+		tracing.WithExporter(exporter), tracing.WithSampler(sdktrace.NeverSample()))
+	if err != nil {
+		t.Fatalf("tracer: %v", err)
+	}
+
+	handler := middleware.NewTracingMiddleware(tracer).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	forced := httptest.NewRequest(http.MethodGet, "/items/forced", nil)
+	forced.Header.Set(middleware.ForceTraceHeader, "1")
+	handler.ServeHTTP(httptest.NewRecorder(), forced)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/sampled-out", nil))
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "/items/forced", spans[0].Name)
+		assert.True(t, spans[0].SpanContext.IsSampled())
+	}
+}
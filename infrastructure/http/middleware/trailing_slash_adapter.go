@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TrailingSlashMode selects how TrailingSlashMiddleware canonicalizes paths.
+type TrailingSlashMode int
+
+const (
+	// TrailingSlashRedirect redirects /items/ to /items. GET and HEAD requests
+	// get a 301; other methods get a 308 so the method and body are preserved.
+	TrailingSlashRedirect TrailingSlashMode = iota
+	// TrailingSlashRewrite strips the trailing slash in place before routing.
+	TrailingSlashRewrite
+)
+
+// TrailingSlashMiddleware makes /items/ and /items reach the same route.
+type TrailingSlashMiddleware struct {
+	mode TrailingSlashMode
+}
+
+// NewTrailingSlashMiddleware creates a middleware that canonicalizes paths to
+// their form without a trailing slash. The root path "/" is left alone.
+func NewTrailingSlashMiddleware(mode TrailingSlashMode) *TrailingSlashMiddleware {
+	return &TrailingSlashMiddleware{mode: mode}
+}
+
+// Middleware returns an http.Handler middleware function.
+func (tsm *TrailingSlashMiddleware) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			u := *r.URL
+			u.Path = canonicalPath(path)
+			if u.RawPath != "" {
+				u.RawPath = canonicalPath(u.RawPath)
+			}
+
+			if tsm.mode == TrailingSlashRewrite {
+				r2 := r.Clone(r.Context())
+				r2.URL = &u
+				r2.RequestURI = u.RequestURI()
+				next.ServeHTTP(w, r2)
+				return
+			}
+
+			status := http.StatusMovedPermanently
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				status = http.StatusPermanentRedirect
+			}
+			http.Redirect(w, r, u.RequestURI(), status)
+		})
+	}
+}
+
+// canonicalPath strips the trailing slashes of p and collapses its leading
+// slashes, and backslashes browsers treat as slashes, into one "/". A path
+// such as //evil.com/ would otherwise redirect to //evil.com, which browsers
+// read as a link to another host.
+func canonicalPath(p string) string {
+	return "/" + strings.TrimLeft(strings.TrimRight(p, "/"), "/\\")
+}
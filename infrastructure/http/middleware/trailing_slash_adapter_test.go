@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func pathEchoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+}
+
+func TestTrailingSlashMiddleware_Redirect(t *testing.T) {
+	handler := middleware.NewTrailingSlashMiddleware(middleware.TrailingSlashRedirect).Middleware()(pathEchoHandler())
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		{"GET with slash", http.MethodGet, "/items/", http.StatusMovedPermanently, "/items"},
+		{"Query preserved", http.MethodGet, "/items//?page=2", http.StatusMovedPermanently, "/items?page=2"},
+		{"POST keeps method", http.MethodPost, "/items/", http.StatusPermanentRedirect, "/items"},
+		{"Canonical path", http.MethodGet, "/items", http.StatusOK, ""},
+		{"Leading slashes collapsed", http.MethodGet, "//evil.com/", http.StatusMovedPermanently, "/evil.com"},
+		{"Leading backslash collapsed", http.MethodGet, "/\\evil.com/", http.StatusMovedPermanently, "/evil.com"},
+		{"Root", http.MethodGet, "/", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestTrailingSlashMiddleware_Rewrite(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /items", pathEchoHandler())
+	handler := middleware.NewTrailingSlashMiddleware(middleware.TrailingSlashRewrite).Middleware()(mux)
+
+	for _, target := range []string{"/items", "/items/", "/items///"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, target)
+		assert.Equal(t, "/items", w.Body.String(), target)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLLimitMiddleware(t *testing.T) {
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+	wrapped := middleware.NewURLLimitMiddleware(64).Middleware()(handler)
+
+	t.Run("Over-long query", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/items?q="+strings.Repeat("a", 100), nil)
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestURITooLong, w.Code)
+		assert.False(t, called, "handler must not run for over-long URIs")
+	})
+
+	t.Run("Normal request", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/items?q=widget", nil)
+		w := httptest.NewRecorder()
+
+		wrapped.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, called)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/items?q="+strings.Repeat("a", 100), nil)
+		w := httptest.NewRecorder()
+
+		middleware.NewURLLimitMiddleware(0).Middleware()(handler).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
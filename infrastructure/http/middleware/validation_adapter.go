@@ -2,15 +2,20 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"reflect"
-	"strings"
+	"slices"
 
+	apphttp "github.com/next-trace/scg-service-api/application/http"
 	applogger "github.com/next-trace/scg-service-api/application/logger"
 	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	"github.com/next-trace/scg-service-api/infrastructure/serializer"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -22,49 +27,47 @@ const (
 	validationKey      contextKey = "validation_model"
 )
 
+// ValidationOption customizes the validation middleware.
+type ValidationOption func(*ValidationMiddleware)
+
+// WithValidationResponder sets the ResponseWriter used to render validation failures,
+// so they share the envelope of every other error response. Defaults to the JSON serializer.
+func WithValidationResponder(responder apphttp.ResponseWriter) ValidationOption {
+	return func(vm *ValidationMiddleware) { vm.responder = responder }
+}
+
 // ValidationMiddleware provides middleware to validate request data.
 type ValidationMiddleware struct {
+	toggle
 	validator appvalidation.Validator
 	config    appvalidation.Config
 	log       applogger.Logger
+	responder apphttp.ResponseWriter
 }
 
-// writeValidationErrorResponse writes validation error response to the response writer
-func (vm *ValidationMiddleware) writeValidationErrorResponse(w http.ResponseWriter, r *http.Request, result appvalidation.ValidationResult) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"error":  "Validation failed",
-		"errors": result.Errors,
-	}); err != nil {
-		vm.log.Error(r.Context(), err, "failed to encode validation errors response")
-	}
-}
-
-// NewValidationMiddleware creates a new validation middleware.
-func NewValidationMiddleware(validator appvalidation.Validator, config appvalidation.Config, log applogger.Logger) *ValidationMiddleware {
-	return &ValidationMiddleware{
+// NewValidationMiddleware creates a new validation middleware. config.Enabled
+// sets the initial state; use SetEnabled to change it at runtime.
+func NewValidationMiddleware(validator appvalidation.Validator, config appvalidation.Config, log applogger.Logger, opts ...ValidationOption) *ValidationMiddleware {
+	log = applogger.OrNop(log)
+	vm := &ValidationMiddleware{
 		validator: validator,
 		config:    config,
 		log:       log,
+		responder: serializer.NewJSONAdapter(),
 	}
+	vm.SetEnabled(config.Enabled)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(vm)
+		}
+	}
+	return vm
 }
 
 // Middleware returns an http.Handler middleware function.
 func (vm *ValidationMiddleware) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !vm.config.Enabled {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			// Skip validation for certain methods
-			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
-				next.ServeHTTP(w, r)
-				return
-			}
-
 			// Get the validation model from the request context
 			model := r.Context().Value(validationKey)
 			if model == nil {
@@ -73,42 +76,7 @@ func (vm *ValidationMiddleware) Middleware() func(http.Handler) http.Handler {
 				return
 			}
 
-			// Parse the request body
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				vm.log.Error(r.Context(), err, "failed to read request body")
-				http.Error(w, "Bad Request: failed to read request body", http.StatusBadRequest)
-				return
-			}
-
-			// Restore the request body for later use
-			r.Body = io.NopCloser(strings.NewReader(string(body)))
-
-			// Create a new instance of the model
-			modelType := reflect.TypeOf(model)
-			if modelType.Kind() == reflect.Ptr {
-				modelType = modelType.Elem()
-			}
-			modelValue := reflect.New(modelType).Interface()
-
-			// Unmarshal the request body into the model
-			if err := json.Unmarshal(body, modelValue); err != nil {
-				vm.log.Error(r.Context(), err, "failed to unmarshal request body")
-				http.Error(w, "Bad Request: invalid JSON", http.StatusBadRequest)
-				return
-			}
-
-			// Validate the model
-			result := vm.validator.Validate(r.Context(), modelValue)
-			if !result.Valid {
-				// Return validation errors
-				vm.writeValidationErrorResponse(w, r, result)
-				return
-			}
-
-			// Store the validated model in the request context
-			ctx := context.WithValue(r.Context(), validationModelKey, modelValue)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			vm.validate(w, r, next, model)
 		})
 	}
 }
@@ -117,55 +85,109 @@ func (vm *ValidationMiddleware) Middleware() func(http.Handler) http.Handler {
 func (vm *ValidationMiddleware) Validate(model interface{}) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !vm.config.Enabled {
-				next.ServeHTTP(w, r)
-				return
-			}
+			vm.validate(w, r, next, model)
+		})
+	}
+}
 
-			// Skip validation for certain methods
-			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
-				next.ServeHTTP(w, r)
-				return
-			}
+// validate decodes the request body into a new instance of model, validates it,
+// and either renders the failure or calls next with the validated model in context.
+func (vm *ValidationMiddleware) validate(w http.ResponseWriter, r *http.Request, next http.Handler, model interface{}) {
+	if !vm.Enabled() {
+		next.ServeHTTP(w, r)
+		return
+	}
 
-			// Parse the request body
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				vm.log.Error(r.Context(), err, "failed to read request body")
-				http.Error(w, "Bad Request: failed to read request body", http.StatusBadRequest)
-				return
-			}
+	// Skip validation for the configured methods
+	if vm.skipMethod(r.Method) {
+		next.ServeHTTP(w, r)
+		return
+	}
 
-			// Restore the request body for later use
-			r.Body = io.NopCloser(strings.NewReader(string(body)))
+	// Parse the request body, bounded by MaxBodyBytes
+	if vm.config.MaxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, vm.config.MaxBodyBytes)
+	}
+	body, err := readBody(r.Context(), r.Body)
+	if err != nil {
+		vm.log.Error(r.Context(), err, "failed to read request body")
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		vm.responder.ErrorWithStatus(w, r, status, fmt.Errorf("failed to read request body: %w", err))
+		return
+	}
 
-			// Create a new instance of the model
-			modelType := reflect.TypeOf(model)
-			if modelType.Kind() == reflect.Ptr {
-				modelType = modelType.Elem()
-			}
-			modelValue := reflect.New(modelType).Interface()
+	// Restore the request body for later use
+	r.Body = io.NopCloser(bytes.NewReader(body))
 
-			// Unmarshal the request body into the model
-			if err := json.Unmarshal(body, modelValue); err != nil {
-				vm.log.Error(r.Context(), err, "failed to unmarshal request body")
-				http.Error(w, "Bad Request: invalid JSON", http.StatusBadRequest)
-				return
-			}
+	// Create a new instance of the model
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	modelValue := reflect.New(modelType).Interface()
 
-			// Validate the model
-			result := vm.validator.Validate(r.Context(), modelValue)
-			if !result.Valid {
-				// Return validation errors
-				vm.writeValidationErrorResponse(w, r, result)
-				return
-			}
+	// Unmarshal the request body into the model
+	if err := json.Unmarshal(body, modelValue); err != nil {
+		vm.log.Error(r.Context(), err, "failed to unmarshal request body")
+		vm.responder.ErrorWithStatus(w, r, http.StatusBadRequest, fmt.Errorf("invalid JSON: %w", err))
+		return
+	}
 
-			// Store the validated model in the request context
-			ctx := context.WithValue(r.Context(), validationModelKey, modelValue)
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+	// Validate the model
+	result := vm.validator.Validate(r.Context(), modelValue)
+	if !result.Valid {
+		// Return validation errors
+		vm.responder.Error(w, r, result.Err())
+		return
+	}
+
+	// Store the validated model in the request context
+	ctx := context.WithValue(r.Context(), validationModelKey, modelValue)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// readBody reads body to the end, giving up when ctx is done so a slow client
+// cannot hold the handler past the request's deadline. The abandoned read
+// ends once the server closes the connection.
+func readBody(ctx context.Context, body io.Reader) ([]byte, error) {
+	type readResult struct {
+		data []byte
+		err  error
+	}
+	done := make(chan readResult, 1)
+	go func() {
+		data, err := io.ReadAll(body)
+		done <- readResult{data: data, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.data, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ValidatedModel returns the model decoded and validated by ValidationMiddleware.
+// The model is stored as a pointer to a new value of the registered type, so T is
+// usually a pointer type, e.g. ValidatedModel[*CreateItemRequest](r.Context()).
+// Handlers should use it instead of decoding the request body again.
+func ValidatedModel[T any](ctx context.Context) (T, bool) {
+	model, ok := ctx.Value(validationModelKey).(T)
+	return model, ok
+}
+
+// skipMethod reports whether requests with the given method bypass validation.
+func (vm *ValidationMiddleware) skipMethod(method string) bool {
+	methods := vm.config.SkipMethods
+	if methods == nil {
+		methods = appvalidation.DefaultSkipMethods()
 	}
+	return slices.Contains(methods, method)
 }
 
 // Validation provides backward compatibility with the old API.
@@ -0,0 +1,199 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	"github.com/next-trace/scg-service-api/infrastructure/http/middleware"
+	"github.com/next-trace/scg-service-api/infrastructure/logger"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type createItemRequest struct {
+	Name string `json:"name"`
+}
+
+// requireNameValidator fails validation when createItemRequest.Name is empty.
+type requireNameValidator struct{}
+
+func (requireNameValidator) Validate(_ context.Context, value interface{}) appvalidation.ValidationResult {
+	if req, ok := value.(*createItemRequest); ok && req.Name == "" {
+		return appvalidation.ValidationResult{
+			Valid:  false,
+			Errors: appvalidation.ValidationErrors{"name": {"name is required"}},
+		}
+	}
+	return appvalidation.ValidationResult{Valid: true}
+}
+
+func (v requireNameValidator) ValidateField(ctx context.Context, value interface{}, _ string) appvalidation.ValidationResult {
+	return v.Validate(ctx, value)
+}
+
+func (requireNameValidator) ValidateMap(_ context.Context, _ map[string]interface{}) appvalidation.ValidationResult {
+	return appvalidation.ValidationResult{Valid: true}
+}
+
+func (requireNameValidator) RegisterCustomRule(_ string, _ appvalidation.CustomRule) error {
+	return nil
+}
+
+func (requireNameValidator) RegisterTagNameFunc(_ func(field reflect.StructField) string) {}
+
+func newValidationMiddleware(t *testing.T, cfg appvalidation.Config) *middleware.ValidationMiddleware {
+	t.Helper()
+	var logBuffer bytes.Buffer
+	return middleware.NewValidationMiddleware(requireNameValidator{}, cfg, logger.NewSlogAdapter(&logBuffer, "debug"))
+}
+
+func TestValidationMiddleware_FailureUsesErrorEnvelope(t *testing.T) {
+	handler := newValidationMiddleware(t, appvalidation.DefaultConfig()).
+		Validate(&createItemRequest{})(okHandler())
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{1}})
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":""}`))
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), spanCtx))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var body struct {
+		Error   string              `json:"error"`
+		TraceID string              `json:"trace_id"`
+		Code    string              `json:"code"`
+		Details map[string][]string `json:"details"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "validation failed", body.Error)
+	assert.Equal(t, "validation_failed", body.Code)
+	assert.Equal(t, traceID.String(), body.TraceID)
+	assert.Equal(t, []string{"name is required"}, body.Details["name"])
+}
+
+func TestValidationMiddleware_ValidRequestPassesThrough(t *testing.T) {
+	handler := newValidationMiddleware(t, appvalidation.DefaultConfig()).
+		Validate(&createItemRequest{})(okHandler())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":"widget"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestValidationMiddleware_BodyLimit(t *testing.T) {
+	cfg := appvalidation.DefaultConfig()
+	cfg.MaxBodyBytes = 32
+	handler := newValidationMiddleware(t, cfg).Validate(&createItemRequest{})(okHandler())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":"widget"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	oversized := `{"name":"` + strings.Repeat("x", 64) + `"}`
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(oversized)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestValidationMiddleware_CancelledBodyRead(t *testing.T) {
+	handler := newValidationMiddleware(t, appvalidation.DefaultConfig()).
+		Validate(&createItemRequest{})(okHandler())
+
+	// The body never delivers data; the read must give up with the context.
+	body, writer := io.Pipe()
+	t.Cleanup(func() { _ = writer.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/items", body).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestValidationMiddleware_SkipMethods(t *testing.T) {
+	invalid := `{"name":""}`
+	do := func(cfg appvalidation.Config, method string) int {
+		handler := newValidationMiddleware(t, cfg).Validate(&createItemRequest{})(okHandler())
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "/items", strings.NewReader(invalid)))
+		return w.Code
+	}
+
+	defaults := appvalidation.DefaultConfig()
+	assert.Equal(t, http.StatusOK, do(defaults, http.MethodGet), "GET is skipped by default")
+	assert.Equal(t, http.StatusBadRequest, do(defaults, http.MethodDelete), "DELETE is validated by default")
+
+	validateGet := appvalidation.DefaultConfig()
+	validateGet.SkipMethods = []string{http.MethodHead, http.MethodOptions, http.MethodDelete}
+	assert.Equal(t, http.StatusBadRequest, do(validateGet, http.MethodGet), "GET is validated when not skipped")
+	assert.Equal(t, http.StatusOK, do(validateGet, http.MethodDelete), "DELETE is skipped when configured")
+}
+
+// recordingValidator remembers the last value it validated.
+type recordingValidator struct {
+	requireNameValidator
+	validated interface{}
+}
+
+func (v *recordingValidator) Validate(ctx context.Context, value interface{}) appvalidation.ValidationResult {
+	v.validated = value
+	return v.requireNameValidator.Validate(ctx, value)
+}
+
+func TestValidatedModel_ReturnsValidatedInstance(t *testing.T) {
+	var logBuffer bytes.Buffer
+	validator := &recordingValidator{}
+	vm := middleware.NewValidationMiddleware(validator, appvalidation.DefaultConfig(), logger.NewSlogAdapter(&logBuffer, "debug"))
+
+	var got *createItemRequest
+	var found bool
+	handler := vm.Validate(&createItemRequest{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, found = middleware.ValidatedModel[*createItemRequest](r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":"widget"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, found)
+	assert.Equal(t, "widget", got.Name)
+	assert.Same(t, validator.validated, got, "handler should receive the instance the middleware validated")
+
+	_, found = middleware.ValidatedModel[*createItemRequest](context.Background())
+	assert.False(t, found)
+}
+
+func TestValidationMiddleware_SetEnabled(t *testing.T) {
+	mw := newValidationMiddleware(t, appvalidation.DefaultConfig())
+	handler := mw.Validate(&createItemRequest{})(okHandler())
+	do := func() int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":""}`)))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do())
+
+	mw.SetEnabled(false)
+	assert.False(t, mw.Enabled())
+	assert.Equal(t, http.StatusOK, do(), "disabled validation passes requests through")
+
+	mw.SetEnabled(true)
+	assert.Equal(t, http.StatusBadRequest, do())
+}
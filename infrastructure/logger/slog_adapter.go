@@ -2,29 +2,68 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
+	"sort"
 
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // slogAdapter implements the logger.Logger interface using Go's slog.
 type slogAdapter struct {
-	log *slog.Logger
+	log    *slog.Logger
+	output io.Writer
+
+	// bound holds the keys added with WithField; context fields with the
+	// same key are skipped so they are not logged twice.
+	bound map[string]struct{}
 }
 
-// NewSlogAdapter creates a concrete logger adapter.
-// If output is nil, it defaults to os.Stdout. Level is one of: debug, info, warn, error.
-func NewSlogAdapter(output io.Writer, level string) applogger.Logger {
-	if output == nil {
-		output = os.Stdout
+// Option configures the logger built by New.
+type Option func(*options)
+
+// options holds the settings New builds the logger from.
+type options struct {
+	output io.Writer
+	level  string
+}
+
+// WithOutput sets where log records are written. Nil means os.Stdout, the
+// default.
+func WithOutput(output io.Writer) Option {
+	return func(o *options) { o.output = output }
+}
+
+// WithLevel sets the minimum level logged: debug, info, warn or error. The
+// default is info.
+func WithLevel(level string) Option {
+	return func(o *options) { o.level = level }
+}
+
+// New creates a concrete logger adapter writing JSON records, configured by
+// opts. Without options it logs at info level to os.Stdout.
+func New(opts ...Option) applogger.Logger {
+	o := options{output: os.Stdout, level: "info"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.output == nil {
+		o.output = os.Stdout
 	}
 	// Use internal logger with provided writer; Pretty=false by default for JSON output
-	h := slog.NewJSONHandler(output, &slog.HandlerOptions{Level: internallogLevel(level)})
+	h := slog.NewJSONHandler(o.output, &slog.HandlerOptions{Level: internallogLevel(o.level)})
 	l := slog.New(h)
-	return &slogAdapter{log: l}
+	return &slogAdapter{log: l, output: o.output}
+}
+
+// NewSlogAdapter provides backward compatibility with the old API.
+// Deprecated: Use New with WithOutput and WithLevel instead.
+func NewSlogAdapter(output io.Writer, level string) applogger.Logger {
+	return New(WithOutput(output), WithLevel(level))
 }
 
 func internallogLevel(level string) slog.Leveler { // helper to avoid import cycle with internal/logger
@@ -40,17 +79,51 @@ func internallogLevel(level string) slog.Leveler { // helper to avoid import cyc
 	}
 }
 
-// withTrace returns logger with OTEL trace/span IDs if present in ctx
+// withTrace returns logger with OTEL trace/span IDs if present in ctx, plus any
+// fields stored with applogger.ContextWithFields.
 func (s *slogAdapter) withTrace(ctx context.Context) *slog.Logger {
+	log := s.log
+	if fields := applogger.FieldsFromContext(ctx); len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			if _, ok := s.bound[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		args := make([]any, 0, len(keys))
+		for _, k := range keys {
+			args = append(args, slog.Any(k, fields[k]))
+		}
+		log = log.With(args...)
+	}
+
 	span := trace.SpanFromContext(ctx)
 	sc := span.SpanContext()
 	if sc.IsValid() {
-		return s.log.With(
+		return log.With(
 			slog.String("trace_id", sc.TraceID().String()),
 			slog.String("span_id", sc.SpanID().String()),
 		)
 	}
-	return s.log
+	return log
+}
+
+// errorAttrs returns the attributes logged for err. A DomainError also
+// contributes its code and details, under the same "code" and "details" keys
+// the serializer uses in error responses.
+func errorAttrs(err error) []any {
+	attrs := []any{slog.Any("error", err)}
+	var domainErr *domainerrors.DomainError
+	if errors.As(err, &domainErr) {
+		if domainErr.Code != "" {
+			attrs = append(attrs, slog.String("code", domainErr.Code))
+		}
+		if len(domainErr.Details) > 0 {
+			attrs = append(attrs, slog.Any("details", domainErr.Details))
+		}
+	}
+	return attrs
 }
 
 // Basic logging methods
@@ -67,12 +140,13 @@ func (s *slogAdapter) Warn(ctx context.Context, msg string) {
 }
 
 func (s *slogAdapter) Error(ctx context.Context, err error, msg string) {
-	s.withTrace(ctx).ErrorContext(ctx, msg, slog.Any("error", err))
+	s.withTrace(ctx).ErrorContext(ctx, msg, errorAttrs(err)...)
 }
 
 func (s *slogAdapter) Fatal(ctx context.Context, err error, msg string) {
 	// slog has no Fatal; we log at Error level and then exit with non-zero code for compatibility
-	s.withTrace(ctx).ErrorContext(ctx, msg, slog.Any("error", err), slog.String("severity", "FATAL"))
+	attrs := append(errorAttrs(err), slog.String("severity", "FATAL"))
+	s.withTrace(ctx).ErrorContext(ctx, msg, attrs...)
 	os.Exit(1)
 }
 
@@ -102,8 +176,7 @@ func (s *slogAdapter) WarnKV(ctx context.Context, msg string, keyValues map[stri
 }
 
 func (s *slogAdapter) ErrorKV(ctx context.Context, err error, msg string, keyValues map[string]interface{}) {
-	attrs := make([]any, 0, len(keyValues)+1)
-	attrs = append(attrs, slog.Any("error", err))
+	attrs := errorAttrs(err)
 	for k, v := range keyValues {
 		attrs = append(attrs, slog.Any(k, v))
 	}
@@ -111,8 +184,7 @@ func (s *slogAdapter) ErrorKV(ctx context.Context, err error, msg string, keyVal
 }
 
 func (s *slogAdapter) FatalKV(ctx context.Context, err error, msg string, keyValues map[string]interface{}) {
-	attrs := make([]any, 0, len(keyValues)+2)
-	attrs = append(attrs, slog.Any("error", err), slog.String("severity", "FATAL"))
+	attrs := append(errorAttrs(err), slog.String("severity", "FATAL"))
 	for k, v := range keyValues {
 		attrs = append(attrs, slog.Any(k, v))
 	}
@@ -122,5 +194,19 @@ func (s *slogAdapter) FatalKV(ctx context.Context, err error, msg string, keyVal
 
 // WithField returns a new logger with the field added to the logger's context
 func (s *slogAdapter) WithField(key string, value interface{}) applogger.Logger {
-	return &slogAdapter{log: s.log.With(slog.Any(key, value))}
+	bound := make(map[string]struct{}, len(s.bound)+1)
+	for k := range s.bound {
+		bound[k] = struct{}{}
+	}
+	bound[key] = struct{}{}
+	return &slogAdapter{log: s.log.With(slog.Any(key, value)), output: s.output, bound: bound}
+}
+
+// Flush flushes the output if it buffers writes (e.g. a *bufio.Writer).
+// slog writes each record synchronously, so for other outputs it is a no-op.
+func (s *slogAdapter) Flush() error {
+	if f, ok := s.output.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
 }
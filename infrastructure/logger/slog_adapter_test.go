@@ -1,11 +1,15 @@
 package logger_test
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	applogger "github.com/next-trace/scg-service-api/application/logger"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
 	"github.com/next-trace/scg-service-api/infrastructure/logger"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +35,38 @@ func TestNewSlogAdapter(t *testing.T) {
 	})
 }
 
+func TestNew(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("Defaults match legacy", func(t *testing.T) {
+		var got, want bytes.Buffer
+		for buf, log := range map[*bytes.Buffer]applogger.Logger{
+			&got:  logger.New(logger.WithOutput(&got)),
+			&want: logger.NewSlogAdapter(&want, "info"),
+		} {
+			log.Debug(ctx, "hidden")
+			log.Info(ctx, "shown")
+			assert.NotContains(t, buf.String(), "hidden")
+			assert.Contains(t, buf.String(), `"msg":"shown"`)
+		}
+		assert.Equal(t, strings.Count(want.String(), "\n"), strings.Count(got.String(), "\n"))
+	})
+
+	t.Run("Default output", func(t *testing.T) {
+		assert.NotNil(t, logger.New())
+		assert.NotNil(t, logger.New(logger.WithOutput(nil)))
+	})
+
+	t.Run("Level option", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.New(logger.WithOutput(&buf), logger.WithLevel("warn"))
+		log.Info(ctx, "hidden")
+		log.Warn(ctx, "shown")
+		assert.NotContains(t, buf.String(), "hidden")
+		assert.Contains(t, buf.String(), `"level":"WARN"`)
+	})
+}
+
 func TestLoggingMethods(t *testing.T) {
 	var buf bytes.Buffer
 	log := logger.NewSlogAdapter(&buf, "debug")
@@ -116,6 +152,15 @@ func TestStructuredLoggingMethods(t *testing.T) {
 		assert.Contains(t, buf.String(), `"action":"login"`)
 	})
 
+	t.Run("ErrorKV with DomainError", func(t *testing.T) {
+		buf.Reset()
+		err := domainerrors.NewNotFound("item", "42").WithDetail("tenant", "acme")
+		log.ErrorKV(ctx, fmt.Errorf("load: %w", err), "error message", keyValues)
+		assert.Contains(t, buf.String(), `"code":"not_found"`)
+		assert.Contains(t, buf.String(), `"details":{"entity":"item","id":"42","tenant":"acme"}`)
+		assert.Contains(t, buf.String(), `"user_id":123`)
+	})
+
 	// We don't test FatalKV because it would exit the program
 }
 
@@ -150,3 +195,36 @@ func TestWithField(t *testing.T) {
 	assert.Contains(t, buf.String(), `"request_id":"abc123"`)
 	assert.Contains(t, buf.String(), `"session_id":"xyz789"`)
 }
+
+func TestContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewSlogAdapter(&buf, "info")
+
+	ctx := applogger.ContextWithFields(t.Context(), map[string]interface{}{"request_id": "abc123"})
+	ctx = applogger.ContextWithFields(ctx, map[string]interface{}{"tenant_id": "acme"})
+
+	log.InfoKV(ctx, "test message", map[string]interface{}{"user_id": 123})
+	assert.Contains(t, buf.String(), `"request_id":"abc123"`)
+	assert.Contains(t, buf.String(), `"tenant_id":"acme"`)
+	assert.Contains(t, buf.String(), `"user_id":123`)
+}
+
+func TestFlush(t *testing.T) {
+	t.Run("Unbuffered output", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := logger.NewSlogAdapter(&buf, "info")
+		assert.NoError(t, log.Flush())
+	})
+
+	t.Run("Buffered output", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		log := logger.NewSlogAdapter(w, "info").WithField("component", "test")
+
+		log.Info(t.Context(), "buffered message")
+		assert.Empty(t, buf.String())
+
+		assert.NoError(t, log.Flush())
+		assert.Contains(t, buf.String(), "buffered message")
+	})
+}
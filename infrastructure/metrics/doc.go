@@ -1,3 +1,4 @@
-// Package metrics provides a Prometheus-like adapter that satisfies application/metrics.
-// It exposes a simple HTTP endpoint and in-memory metrics suitable for tests and examples.
+// Package metrics provides adapters that satisfy application/metrics.
+// The Prometheus-like adapter exposes a simple HTTP endpoint and in-memory metrics suitable for tests and examples;
+// the OpenTelemetry adapter records through a MeterProvider, and NewOTLPMeterProvider builds one that pushes to the OTLP/HTTP collector at Config.OTLPEndpoint.
 package metrics
@@ -0,0 +1,250 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// Ensure otelAdapter implements the appmetrics.Metrics interface.
+var _ appmetrics.Metrics = (*otelAdapter)(nil)
+
+// otelMeterName is the instrumentation scope of the instruments created by
+// the OpenTelemetry adapter.
+const otelMeterName = "github.com/next-trace/scg-service-api/infrastructure/metrics"
+
+// otelAdapter implements the metrics.Metrics interface on top of the
+// OpenTelemetry metrics API. Exporting, e.g. pushing to an OTLP endpoint, is
+// left to the MeterProvider it is given.
+type otelAdapter struct {
+	provider    metric.MeterProvider
+	log         applogger.Logger
+	instruments *otelInstruments
+	labels      map[string]string
+	attrs       attribute.Set
+}
+
+// otelInstruments caches instruments by name and tracks the current value of
+// every gauge series. It is shared by all adapters derived with WithLabels.
+type otelInstruments struct {
+	meter      metric.Meter
+	log        applogger.Logger
+	mu         sync.Mutex
+	counters   map[string]metric.Float64Counter
+	gauges     map[string]metric.Float64Gauge
+	histograms map[string]metric.Float64Histogram
+	values     map[string]float64
+}
+
+// NewOtelAdapter creates a metrics adapter recording through provider, which
+// is typically the OTLP-exporting MeterProvider from NewOTLPMeterProvider. Counters map to Float64Counter, gauges to Float64Gauge,
+// histograms and timers to Float64Histogram (timers with unit "s"), and labels,
+// including config.Labels, to attributes. A nil provider records nothing.
+func NewOtelAdapter(config appmetrics.Config, provider metric.MeterProvider, log applogger.Logger) appmetrics.Metrics {
+	log = applogger.OrNop(log)
+	if provider == nil {
+		provider = noop.NewMeterProvider()
+	}
+
+	a := &otelAdapter{
+		provider: provider,
+		log:      log,
+		instruments: &otelInstruments{
+			meter:      provider.Meter(otelMeterName),
+			log:        log,
+			counters:   make(map[string]metric.Float64Counter),
+			gauges:     make(map[string]metric.Float64Gauge),
+			histograms: make(map[string]metric.Float64Histogram),
+			values:     make(map[string]float64),
+		},
+	}
+	a.setLabels(config.Labels)
+	return a
+}
+
+// setLabels sets the adapter's labels and the matching attribute set.
+func (o *otelAdapter) setLabels(labels map[string]string) {
+	o.labels = make(map[string]string, len(labels))
+	kvs := make([]attribute.KeyValue, 0, len(labels))
+	for k, v := range labels {
+		o.labels[k] = v
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	o.attrs = attribute.NewSet(kvs...)
+}
+
+// WithLabels returns a new Metrics instance with the given labels added.
+func (o *otelAdapter) WithLabels(labels map[string]string) appmetrics.Metrics {
+	merged := make(map[string]string, len(o.labels)+len(labels))
+	for k, v := range o.labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+
+	derived := &otelAdapter{
+		provider:    o.provider,
+		log:         o.log,
+		instruments: o.instruments,
+	}
+	derived.setLabels(merged)
+	return derived
+}
+
+// Serve is a no-op: OpenTelemetry metrics are pushed by the MeterProvider's
+// reader rather than scraped.
+func (o *otelAdapter) Serve(ctx context.Context, addr string) error {
+	o.log.InfoKV(ctx, "OpenTelemetry metrics are pushed by the meter provider; not serving", map[string]interface{}{
+		"address": addr,
+	})
+	return nil
+}
+
+// Shutdown flushes and shuts down the MeterProvider if it supports it, as the
+// SDK MeterProvider does.
+func (o *otelAdapter) Shutdown(ctx context.Context) error {
+	if p, ok := o.provider.(interface{ Shutdown(context.Context) error }); ok {
+		o.log.Info(ctx, "shutting down OpenTelemetry meter provider")
+		return p.Shutdown(ctx)
+	}
+	return nil
+}
+
+// Counter methods
+
+// CounterInc increments the counter by 1.
+func (o *otelAdapter) CounterInc(name string) {
+	o.CounterAdd(name, 1)
+}
+
+// CounterAdd adds the given value to the counter.
+func (o *otelAdapter) CounterAdd(name string, value float64) {
+	o.instruments.counter(name).Add(context.Background(), value, metric.WithAttributeSet(o.attrs))
+}
+
+// Gauge methods
+
+// GaugeSet sets the gauge to the given value.
+func (o *otelAdapter) GaugeSet(name string, value float64) {
+	o.instruments.updateGauge(name, o.attrs, func(float64) float64 { return value })
+}
+
+// GaugeInc increments the gauge by 1.
+func (o *otelAdapter) GaugeInc(name string) {
+	o.GaugeAdd(name, 1)
+}
+
+// GaugeDec decrements the gauge by 1.
+func (o *otelAdapter) GaugeDec(name string) {
+	o.GaugeSub(name, 1)
+}
+
+// GaugeAdd adds the given value to the gauge.
+func (o *otelAdapter) GaugeAdd(name string, value float64) {
+	o.instruments.updateGauge(name, o.attrs, func(current float64) float64 { return current + value })
+}
+
+// GaugeSub subtracts the given value from the gauge.
+func (o *otelAdapter) GaugeSub(name string, value float64) {
+	o.GaugeAdd(name, -value)
+}
+
+// Histogram methods
+
+// HistogramObserve adds a single observation to the histogram.
+func (o *otelAdapter) HistogramObserve(name string, value float64) {
+	o.instruments.histogram(name, "").Record(context.Background(), value, metric.WithAttributeSet(o.attrs))
+}
+
+// observeSeconds records a timer's duration in a histogram with unit "s".
+func (o *otelAdapter) observeSeconds(name string, d time.Duration) {
+	o.instruments.histogram(name, "s").Record(context.Background(), d.Seconds(), metric.WithAttributeSet(o.attrs))
+}
+
+// Timer methods
+
+// TimerObserveDuration measures the duration of the given function call.
+func (o *otelAdapter) TimerObserveDuration(name string, f func()) {
+	start := time.Now()
+	f()
+	o.observeSeconds(name, time.Since(start))
+}
+
+// TimerStart starts a new timer and returns a function to stop it.
+func (o *otelAdapter) TimerStart(name string) func() time.Duration {
+	start := time.Now()
+	return func() time.Duration {
+		duration := time.Since(start)
+		o.observeSeconds(name, duration)
+		return duration
+	}
+}
+
+// counter returns the counter called name, creating it on first use.
+func (i *otelInstruments) counter(name string) metric.Float64Counter {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if c, ok := i.counters[name]; ok {
+		return c
+	}
+	c, err := i.meter.Float64Counter(name)
+	if err != nil {
+		i.log.Error(context.Background(), err, "failed to create OpenTelemetry counter")
+		c = noop.Float64Counter{}
+	}
+	i.counters[name] = c
+	return c
+}
+
+// histogram returns the histogram called name, creating it with unit on first
+// use. An empty unit leaves the instrument without one.
+func (i *otelInstruments) histogram(name, unit string) metric.Float64Histogram {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if h, ok := i.histograms[name]; ok {
+		return h
+	}
+	var opts []metric.Float64HistogramOption
+	if unit != "" {
+		opts = append(opts, metric.WithUnit(unit))
+	}
+	h, err := i.meter.Float64Histogram(name, opts...)
+	if err != nil {
+		i.log.Error(context.Background(), err, "failed to create OpenTelemetry histogram")
+		h = noop.Float64Histogram{}
+	}
+	i.histograms[name] = h
+	return h
+}
+
+// updateGauge applies update to the current value of the gauge series and
+// records the result. The gauge instrument is created on first use.
+func (i *otelInstruments) updateGauge(name string, attrs attribute.Set, update func(current float64) float64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	g, ok := i.gauges[name]
+	if !ok {
+		var err error
+		g, err = i.meter.Float64Gauge(name)
+		if err != nil {
+			i.log.Error(context.Background(), err, "failed to create OpenTelemetry gauge")
+			g = noop.Float64Gauge{}
+		}
+		i.gauges[name] = g
+	}
+
+	key := name + "{" + attrs.Encoded(attribute.DefaultEncoder()) + "}"
+	value := update(i.values[key])
+	i.values[key] = value
+	g.Record(context.Background(), value, metric.WithAttributeSet(attrs))
+}
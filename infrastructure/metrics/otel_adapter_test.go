@@ -0,0 +1,123 @@
+package metrics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	metricsimpl "github.com/next-trace/scg-service-api/infrastructure/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+)
+
+// otelScope is the instrumentation scope the adapter's instruments belong to.
+const otelScope = "github.com/next-trace/scg-service-api/infrastructure/metrics"
+
+// newManualProvider returns an SDK MeterProvider whose measurements are
+// collected on demand through the returned reader.
+func newManualProvider() (*sdkmetric.MeterProvider, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	return sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), reader
+}
+
+// collectMetrics collects everything recorded so far, keyed by metric name,
+// and checks it was all recorded under the adapter's scope.
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	collected := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		assert.Equal(t, otelScope, sm.Scope.Name)
+		for _, m := range sm.Metrics {
+			collected[m.Name] = m
+		}
+	}
+	return collected
+}
+
+func TestOtelAdapter_CounterIncrementIsExported(t *testing.T) {
+	provider, reader := newManualProvider()
+	cfg := appmetrics.DefaultConfig()
+	cfg.Labels = map[string]string{"service": "api"}
+	m := metricsimpl.NewOtelAdapter(cfg, provider, nil)
+
+	m.WithLabels(map[string]string{"method": "GET"}).CounterInc("requests_total")
+
+	collected := collectMetrics(t, reader)
+	require.Len(t, collected, 1)
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name: "requests_total",
+		Data: metricdata.Sum[float64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[float64]{{
+				Attributes: attribute.NewSet(attribute.String("service", "api"), attribute.String("method", "GET")),
+				Value:      1,
+			}},
+		},
+	}, collected["requests_total"], metricdatatest.IgnoreTimestamp())
+}
+
+func TestOtelAdapter_GaugesAndHistograms(t *testing.T) {
+	provider, reader := newManualProvider()
+	m := metricsimpl.NewOtelAdapter(appmetrics.DefaultConfig(), provider, nil)
+
+	m.GaugeSet("queue", 5)
+	m.GaugeInc("queue")
+	m.GaugeSub("queue", 3)
+	m.WithLabels(map[string]string{"a": "b"}).GaugeInc("queue")
+	m.HistogramObserve("size", 42)
+	m.TimerObserveDuration("op", func() { time.Sleep(time.Millisecond) })
+
+	collected := collectMetrics(t, reader)
+	require.Len(t, collected, 3)
+
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name: "queue",
+		Data: metricdata.Gauge[float64]{
+			DataPoints: []metricdata.DataPoint[float64]{
+				{Attributes: attribute.NewSet(), Value: 3},
+				{Attributes: attribute.NewSet(attribute.String("a", "b")), Value: 1},
+			},
+		},
+	}, collected["queue"], metricdatatest.IgnoreTimestamp())
+
+	size := collected["size"]
+	assert.Empty(t, size.Unit)
+	sizeData, ok := size.Data.(metricdata.Histogram[float64])
+	require.True(t, ok, "size should be a histogram, got %T", size.Data)
+	require.Len(t, sizeData.DataPoints, 1)
+	assert.Equal(t, uint64(1), sizeData.DataPoints[0].Count)
+	assert.Equal(t, 42.0, sizeData.DataPoints[0].Sum)
+
+	op := collected["op"]
+	assert.Equal(t, "s", op.Unit)
+	opData, ok := op.Data.(metricdata.Histogram[float64])
+	require.True(t, ok, "op should be a histogram, got %T", op.Data)
+	require.Len(t, opData.DataPoints, 1)
+	assert.Equal(t, uint64(1), opData.DataPoints[0].Count)
+	assert.Greater(t, opData.DataPoints[0].Sum, 0.0)
+	assert.Less(t, opData.DataPoints[0].Sum, 1.0)
+}
+
+func TestOtelAdapter_ServeAndShutdown(t *testing.T) {
+	provider, reader := newManualProvider()
+	m := metricsimpl.NewOtelAdapter(appmetrics.DefaultConfig(), provider, nil)
+
+	assert.NoError(t, m.Serve(context.Background(), ":0"))
+	assert.NoError(t, m.Shutdown(context.Background()))
+	var rm metricdata.ResourceMetrics
+	assert.ErrorIs(t, reader.Collect(context.Background(), &rm), sdkmetric.ErrReaderShutdown)
+
+	// A nil provider records nothing and shuts down cleanly.
+	nop := metricsimpl.NewOtelAdapter(appmetrics.DefaultConfig(), nil, nil)
+	nop.CounterInc("c")
+	assert.NoError(t, nop.Shutdown(context.Background()))
+}
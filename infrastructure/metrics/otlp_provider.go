@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// otlpDefaultPath is the OTLP/HTTP metrics path used when the configured
+// endpoint has none.
+const otlpDefaultPath = "/v1/metrics"
+
+// ErrNoOTLPEndpoint is returned by NewOTLPMeterProvider when
+// config.OTLPEndpoint is empty.
+var ErrNoOTLPEndpoint = errors.New("metrics: no OTLP endpoint configured")
+
+// NewOTLPMeterProvider creates an SDK MeterProvider whose periodic reader
+// pushes to the OTLP/HTTP collector at config.OTLPEndpoint every
+// config.ExportInterval. Pass it to NewOtelAdapter; the adapter's Shutdown
+// flushes and stops it.
+func NewOTLPMeterProvider(ctx context.Context, config appmetrics.Config) (*sdkmetric.MeterProvider, error) {
+	if config.OTLPEndpoint == "" {
+		return nil, ErrNoOTLPEndpoint
+	}
+	endpoint, err := url.Parse(config.OTLPEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("metrics: parse OTLP endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("metrics: OTLP endpoint %q must use http or https", endpoint.Redacted())
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = otlpDefaultPath
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(endpoint.String()))
+	if err != nil {
+		return nil, fmt.Errorf("metrics: create OTLP exporter: %w", err)
+	}
+
+	var readerOpts []sdkmetric.PeriodicReaderOption
+	if config.ExportInterval > 0 {
+		readerOpts = append(readerOpts, sdkmetric.WithInterval(config.ExportInterval))
+	}
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, readerOpts...)),
+	), nil
+}
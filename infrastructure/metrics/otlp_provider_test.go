@@ -0,0 +1,95 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+	metricsimpl "github.com/next-trace/scg-service-api/infrastructure/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver is an in-process OTLP/HTTP metrics collector that records the
+// export requests it receives.
+type otlpReceiver struct {
+	paths    chan string
+	requests chan *colmetricpb.ExportMetricsServiceRequest
+}
+
+func newOTLPReceiver(t *testing.T) (*otlpReceiver, *httptest.Server) {
+	t.Helper()
+	r := &otlpReceiver{
+		paths:    make(chan string, 16),
+		requests: make(chan *colmetricpb.ExportMetricsServiceRequest, 16),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var export colmetricpb.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(body, &export); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.paths <- req.URL.Path
+		r.requests <- &export
+
+		resp, _ := proto.Marshal(&colmetricpb.ExportMetricsServiceResponse{})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+func TestOTLPMeterProvider_ExportsToCollector(t *testing.T) {
+	receiver, srv := newOTLPReceiver(t)
+
+	cfg := appmetrics.DefaultConfig()
+	cfg.OTLPEndpoint = srv.URL
+	cfg.Labels = map[string]string{"service": "api"}
+	provider, err := metricsimpl.NewOTLPMeterProvider(context.Background(), cfg)
+	require.NoError(t, err)
+
+	m := metricsimpl.NewOtelAdapter(cfg, provider, nil)
+	m.CounterAdd("requests_total", 3)
+	require.NoError(t, m.Shutdown(context.Background()))
+
+	require.Len(t, receiver.requests, 1)
+	assert.Equal(t, "/v1/metrics", <-receiver.paths)
+	export := <-receiver.requests
+
+	require.Len(t, export.GetResourceMetrics(), 1)
+	scopes := export.GetResourceMetrics()[0].GetScopeMetrics()
+	require.Len(t, scopes, 1)
+	assert.Equal(t, otelScope, scopes[0].GetScope().GetName())
+	require.Len(t, scopes[0].GetMetrics(), 1)
+
+	exported := scopes[0].GetMetrics()[0]
+	assert.Equal(t, "requests_total", exported.GetName())
+	points := exported.GetSum().GetDataPoints()
+	require.Len(t, points, 1)
+	assert.InDelta(t, 3.0, points[0].GetAsDouble(), 0)
+	require.Len(t, points[0].GetAttributes(), 1)
+	assert.Equal(t, "service", points[0].GetAttributes()[0].GetKey())
+	assert.Equal(t, "api", points[0].GetAttributes()[0].GetValue().GetStringValue())
+}
+
+func TestOTLPMeterProvider_RejectsMissingOrInvalidEndpoint(t *testing.T) {
+	cfg := appmetrics.DefaultConfig()
+	_, err := metricsimpl.NewOTLPMeterProvider(context.Background(), cfg)
+	assert.True(t, errors.Is(err, metricsimpl.ErrNoOTLPEndpoint))
+
+	cfg.OTLPEndpoint = "grpc://collector:4317"
+	_, err = metricsimpl.NewOTLPMeterProvider(context.Background(), cfg)
+	assert.Error(t, err)
+}
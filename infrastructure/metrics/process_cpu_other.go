@@ -0,0 +1,8 @@
+//go:build !unix
+
+package metrics
+
+// processCPUSeconds reports that CPU time is unavailable on this platform.
+func processCPUSeconds() (float64, bool) {
+	return 0, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package metrics
+
+import "syscall"
+
+// processCPUSeconds returns the user and system CPU time consumed by the process.
+func processCPUSeconds() (float64, bool) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, false
+	}
+	return timevalSeconds(ru.Utime) + timevalSeconds(ru.Stime), true
+}
+
+func timevalSeconds(tv syscall.Timeval) float64 {
+	return float64(tv.Sec) + float64(tv.Usec)/1e6
+}
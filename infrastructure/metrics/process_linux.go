@@ -0,0 +1,163 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// userHZ is the unit of the clock tick counts in /proc, which the kernel
+// reports in USER_HZ regardless of its internal tick rate.
+const userHZ = 100
+
+// processStartTime returns when the process started, computed like the
+// Prometheus process collector from the boot time in /proc/stat and the
+// process's start time in /proc/self/stat.
+func processStartTime() (time.Time, bool) {
+	boot, err := bootTime()
+	if err != nil {
+		return time.Time{}, false
+	}
+	ticks, err := startTicks()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(boot, 0).Add(time.Duration(ticks) * time.Second / userHZ), true
+}
+
+// bootTime reads the system boot time, in seconds since the epoch, from the
+// btime line of /proc/stat.
+func bootTime() (int64, error) {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "btime "); ok {
+			return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	return 0, errors.New("btime not found in /proc/stat")
+}
+
+// startTicks reads the process start time, in clock ticks after boot, from
+// field 22 of /proc/self/stat.
+func startTicks() (uint64, error) {
+	fields, err := selfStat()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(fields[statStartTime], 10, 64)
+}
+
+// Indexes into the fields returned by selfStat of the fields proc(5)
+// numbers 22 to 24.
+const (
+	statStartTime = iota + 22 - 3
+	statVSize
+	statRSS
+)
+
+// selfStat returns the fields of /proc/self/stat from field 3, the state,
+// through at least field 24, the resident set size. Fields are counted after
+// the command name, which is in parentheses and may itself contain spaces or
+// parentheses.
+func selfStat() ([]string, error) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return nil, err
+	}
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return nil, errors.New("malformed /proc/self/stat")
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) <= statRSS {
+		return nil, errors.New("malformed /proc/self/stat")
+	}
+	return fields, nil
+}
+
+// processMemory returns the virtual memory size and the resident set size of
+// the process in bytes, from /proc/self/stat.
+func processMemory() (virtual, resident float64, ok bool) {
+	fields, err := selfStat()
+	if err != nil {
+		return 0, 0, false
+	}
+	vsize, err := strconv.ParseUint(fields[statVSize], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	rss, err := strconv.ParseUint(fields[statRSS], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return float64(vsize), float64(rss) * float64(os.Getpagesize()), true
+}
+
+// processOpenFDs returns the number of open file descriptors, the entries of
+// /proc/self/fd.
+func processOpenFDs() (float64, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	return float64(len(entries)), true
+}
+
+// processLimits returns the soft limits on open file descriptors and on the
+// address space. An unlimited address space is reported as the largest
+// rlimit value, as the Prometheus process collector does.
+func processLimits() (maxFDs, maxVirtual float64, ok bool) {
+	var fds, as syscall.Rlimit
+	if syscall.Getrlimit(syscall.RLIMIT_NOFILE, &fds) != nil || syscall.Getrlimit(syscall.RLIMIT_AS, &as) != nil {
+		return 0, 0, false
+	}
+	return float64(fds.Cur), float64(as.Cur), true
+}
+
+// processNetwork returns the bytes received and sent in the process's network
+// namespace, the InOctets and OutOctets of the IpExt lines of
+// /proc/self/net/netstat.
+func processNetwork() (received, transmitted float64, ok bool) {
+	data, err := os.ReadFile("/proc/self/net/netstat")
+	if err != nil {
+		return 0, 0, false
+	}
+	var header []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields, found := strings.CutPrefix(line, "IpExt: ")
+		if !found {
+			continue
+		}
+		if header == nil {
+			header = strings.Fields(fields)
+			continue
+		}
+		values := strings.Fields(fields)
+		var in, out float64
+		var gotIn, gotOut bool
+		for i, name := range header {
+			if i >= len(values) {
+				break
+			}
+			switch name {
+			case "InOctets":
+				in, err = strconv.ParseFloat(values[i], 64)
+				gotIn = err == nil
+			case "OutOctets":
+				out, err = strconv.ParseFloat(values[i], 64)
+				gotOut = err == nil
+			}
+		}
+		return in, out, gotIn && gotOut
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,30 @@
+//go:build !linux
+
+package metrics
+
+import "time"
+
+// processStartTime reports that the start time is unavailable without /proc.
+func processStartTime() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// processMemory reports that memory usage is unavailable without /proc.
+func processMemory() (virtual, resident float64, ok bool) {
+	return 0, 0, false
+}
+
+// processOpenFDs reports that open descriptors are unavailable without /proc.
+func processOpenFDs() (float64, bool) {
+	return 0, false
+}
+
+// processLimits reports that the limits are not collected off Linux.
+func processLimits() (maxFDs, maxVirtual float64, ok bool) {
+	return 0, 0, false
+}
+
+// processNetwork reports that network traffic is unavailable without /proc.
+func processNetwork() (received, transmitted float64, ok bool) {
+	return 0, 0, false
+}
@@ -1,15 +1,27 @@
 // Package metrics provides metrics collection functionality.
 //
-// Note: This package requires the following dependencies:
-// - github.com/prometheus/client_golang
+// The Prometheus adapter writes the text exposition format itself. By default
+// its Go and process series come from built-in collectors with no external
+// dependencies. Building with the promclient tag (go build -tags promclient)
+// uses the collectors of github.com/prometheus/client_golang instead, which
+// must then be required in go.mod. The public API is identical in both
+// configurations.
 //
 // See docs/dependencies.md for more information.
 package metrics
 
 import (
+	"bytes"
 	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	applogger "github.com/next-trace/scg-service-api/application/logger"
@@ -19,42 +31,74 @@ import (
 // Ensure prometheusAdapter implements the appmetrics.Metrics interface.
 var _ appmetrics.Metrics = (*prometheusAdapter)(nil)
 
-// prometheusAdapter implements the metrics.Metrics interface using Prometheus.
-// In a real implementation, this would use the Prometheus client library.
-// For now, we'll provide a simple implementation that can be replaced later.
+// Ensure prometheusAdapter supports exemplars.
+var _ appmetrics.ExemplarObserver = (*prometheusAdapter)(nil)
+
+// Ensure prometheusAdapter supports deferred readiness.
+var _ appmetrics.ReadySignaler = (*prometheusAdapter)(nil)
+
+// prometheusAdapter implements the metrics.Metrics interface in the Prometheus
+// text format. Series are kept in memory rather than in a client_golang
+// registry, because the port allows any label set for a name and a registry
+// requires fixed label names per metric.
 type prometheusAdapter struct {
-	config     appmetrics.Config
-	log        applogger.Logger
+	config appmetrics.Config
+	log    applogger.Logger
+	series *prometheusSeries
+	labels map[string]string
+	ready  *atomic.Bool
+
+	// serverMu guards server and serverDone, which is closed once the
+	// serving goroutine has returned.
+	serverMu   sync.Mutex
+	server     *http.Server
+	serverDone chan struct{}
+}
+
+// prometheusSeries holds the recorded values. It is shared by all adapters
+// derived with WithLabels, so mu guards it for all of them.
+type prometheusSeries struct {
+	mu         sync.RWMutex
 	counters   map[string]float64
 	gauges     map[string]float64
 	histograms map[string][]float64
-	labels     map[string]string
-	server     *http.Server
-	mu         sync.RWMutex
+	exemplars  map[string]map[string]string
 }
 
 // NewPrometheusAdapter creates a new Prometheus metrics adapter.
 func NewPrometheusAdapter(config appmetrics.Config, log applogger.Logger) appmetrics.Metrics {
+	log = applogger.OrNop(log)
+	ready := &atomic.Bool{}
+	ready.Store(!config.DeferReady)
 	return &prometheusAdapter{
-		config:     config,
-		log:        log,
-		counters:   make(map[string]float64),
-		gauges:     make(map[string]float64),
-		histograms: make(map[string][]float64),
-		labels:     config.Labels,
+		config: config,
+		log:    log,
+		series: &prometheusSeries{
+			counters:   make(map[string]float64),
+			gauges:     make(map[string]float64),
+			histograms: make(map[string][]float64),
+			exemplars:  make(map[string]map[string]string),
+		},
+		labels: config.Labels,
+		ready:  ready,
 	}
 }
 
-// WithLabels returns a new Metrics instance with the given labels.
+// MarkReady opens the metrics endpoint when Config.DeferReady is set.
+func (p *prometheusAdapter) MarkReady() {
+	p.ready.Store(true)
+}
+
+// WithLabels returns a new Metrics instance with the given labels. It
+// records into the same series as p and is safe to use concurrently with it.
 func (p *prometheusAdapter) WithLabels(labels map[string]string) appmetrics.Metrics {
 	// Create a new adapter with the same configuration
 	newAdapter := &prometheusAdapter{
-		config:     p.config,
-		log:        p.log,
-		counters:   p.counters,
-		gauges:     p.gauges,
-		histograms: p.histograms,
-		labels:     make(map[string]string),
+		config: p.config,
+		log:    p.log,
+		series: p.series,
+		labels: make(map[string]string, len(p.labels)+len(labels)),
+		ready:  p.ready,
 	}
 
 	// Copy existing labels
@@ -70,40 +114,39 @@ func (p *prometheusAdapter) WithLabels(labels map[string]string) appmetrics.Metr
 	return newAdapter
 }
 
-// Serve starts the metrics server on the given address.
+// Serve starts the metrics server on the given address. The address is
+// bound before Serve returns, so listen errors are reported to the caller.
 func (p *prometheusAdapter) Serve(ctx context.Context, addr string) error {
-	// In a real implementation, this would start a Prometheus HTTP server
-	// that exposes metrics on the /metrics endpoint.
+	p.serverMu.Lock()
+	defer p.serverMu.Unlock()
+
+	if p.server != nil {
+		return appmetrics.ErrServerRunning
+	}
+
 	p.log.InfoKV(ctx, "starting metrics server", map[string]interface{}{
 		"address": addr,
 	})
 
-	// Create a new HTTP server
-	p.server = &http.Server{
-		Addr: addr,
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// In a real implementation, this would use the Prometheus handler
-			// to expose metrics in the Prometheus format.
-			w.Header().Set("Content-Type", "text/plain")
-			if _, err := w.Write([]byte("# HELP example_metric Example metric\n")); err != nil {
-				p.log.Error(r.Context(), err, "failed to write metrics response")
-				return
-			}
-			if _, err := w.Write([]byte("# TYPE example_metric gauge\n")); err != nil {
-				p.log.Error(r.Context(), err, "failed to write metrics response")
-				return
-			}
-			if _, err := w.Write([]byte("example_metric 42\n")); err != nil {
-				p.log.Error(r.Context(), err, "failed to write metrics response")
-				return
-			}
-		}),
+	lc := &net.ListenConfig{}
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           p.authenticate(http.HandlerFunc(p.serveMetrics)),
 		ReadHeaderTimeout: 10 * time.Second, // Prevent Slowloris attacks
 	}
+	done := make(chan struct{})
+	p.server = server
+	p.serverDone = done
 
 	// Start the server in a goroutine
 	go func() {
-		if err := p.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		defer close(done)
+		if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
 			p.log.Error(ctx, err, "metrics server error")
 		}
 	}()
@@ -111,13 +154,109 @@ func (p *prometheusAdapter) Serve(ctx context.Context, addr string) error {
 	return nil
 }
 
+// serveMetrics writes the metrics exposition. Until the adapter is ready it
+// answers 503 so that scrapes never record a partial set of series.
+func (p *prometheusAdapter) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	if !p.ready.Load() {
+		http.Error(w, "metrics not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	var buf bytes.Buffer
+	series := p.series
+	series.mu.RLock()
+	writeSeries(&buf, "counter", series.counters)
+	writeSeries(&buf, "gauge", series.gauges)
+	for _, name := range sortedKeys(series.histograms) {
+		var sum float64
+		for _, v := range series.histograms[name] {
+			sum += v
+		}
+		fmt.Fprintf(&buf, "# TYPE %s summary\n", name)
+		fmt.Fprintf(&buf, "%s_sum %s\n", name, formatValue(sum))
+		fmt.Fprintf(&buf, "%s_count %d\n", name, len(series.histograms[name]))
+	}
+	series.mu.RUnlock()
+
+	if p.config.EnableGoMetrics {
+		writeGoMetrics(&buf)
+	}
+	if p.config.EnableProcessMetrics {
+		writeProcessMetrics(&buf)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		p.log.Error(r.Context(), err, "failed to write metrics response")
+	}
+}
+
+// writeSeries writes one sample per name, sorted by name.
+func writeSeries(buf *bytes.Buffer, kind string, series map[string]float64) {
+	for _, name := range sortedKeys(series) {
+		fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+		fmt.Fprintf(buf, "%s %s\n", name, formatValue(series[name]))
+	}
+}
+
+// formatValue renders v the way the Prometheus text format expects.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// authenticate guards next with the configured bearer token.
+// When no MetricsAuthToken is configured, next is returned unchanged.
+func (p *prometheusAdapter) authenticate(next http.Handler) http.Handler {
+	token := p.config.MetricsAuthToken
+	if token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Shutdown gracefully shuts down the metrics server.
+// It waits for the serving goroutine to return, and allows Serve to be
+// called again afterwards.
 func (p *prometheusAdapter) Shutdown(ctx context.Context) error {
-	if p.server != nil {
-		p.log.Info(ctx, "shutting down metrics server")
-		return p.server.Shutdown(ctx)
+	p.serverMu.Lock()
+	server, done := p.server, p.serverDone
+	p.server, p.serverDone = nil, nil
+	p.serverMu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	p.log.Info(ctx, "shutting down metrics server")
+	if err := server.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return nil
 }
 
 // Counter methods
@@ -129,22 +268,20 @@ func (p *prometheusAdapter) CounterInc(name string) {
 
 // CounterAdd adds the given value to the counter.
 func (p *prometheusAdapter) CounterAdd(name string, value float64) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.series.mu.Lock()
+	defer p.series.mu.Unlock()
 
-	// In a real implementation, this would use the Prometheus Counter.
-	p.counters[name] += value
+	p.series.counters[name] += value
 }
 
 // Gauge methods
 
 // GaugeSet sets the gauge to the given value.
 func (p *prometheusAdapter) GaugeSet(name string, value float64) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.series.mu.Lock()
+	defer p.series.mu.Unlock()
 
-	// In a real implementation, this would use the Prometheus Gauge.
-	p.gauges[name] = value
+	p.series.gauges[name] = value
 }
 
 // GaugeInc increments the gauge by 1.
@@ -159,31 +296,40 @@ func (p *prometheusAdapter) GaugeDec(name string) {
 
 // GaugeAdd adds the given value to the gauge.
 func (p *prometheusAdapter) GaugeAdd(name string, value float64) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.series.mu.Lock()
+	defer p.series.mu.Unlock()
 
-	// In a real implementation, this would use the Prometheus Gauge.
-	p.gauges[name] += value
+	p.series.gauges[name] += value
 }
 
 // GaugeSub subtracts the given value from the gauge.
 func (p *prometheusAdapter) GaugeSub(name string, value float64) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.series.mu.Lock()
+	defer p.series.mu.Unlock()
 
-	// In a real implementation, this would use the Prometheus Gauge.
-	p.gauges[name] -= value
+	p.series.gauges[name] -= value
 }
 
 // Histogram methods
 
 // HistogramObserve adds a single observation to the histogram.
 func (p *prometheusAdapter) HistogramObserve(name string, value float64) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.series.mu.Lock()
+	defer p.series.mu.Unlock()
+
+	p.series.histograms[name] = append(p.series.histograms[name], value)
+}
+
+// HistogramObserveWithExemplar adds an observation and keeps exemplar as the
+// histogram's latest exemplar.
+func (p *prometheusAdapter) HistogramObserveWithExemplar(name string, value float64, exemplar map[string]string) {
+	p.series.mu.Lock()
+	defer p.series.mu.Unlock()
 
-	// In a real implementation, this would use the Prometheus Histogram.
-	p.histograms[name] = append(p.histograms[name], value)
+	p.series.histograms[name] = append(p.series.histograms[name], value)
+	if len(exemplar) > 0 {
+		p.series.exemplars[name] = exemplar
+	}
 }
 
 // Timer methods
@@ -3,6 +3,14 @@ package metrics_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -39,3 +47,344 @@ func TestPrometheusAdapter_BasicCalls(t *testing.T) {
 		t.Fatalf("shutdown error: %v", err)
 	}
 }
+
+// freeAddr returns a loopback address with a currently unused port.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	lc := &net.ListenConfig{}
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return addr
+}
+
+// scrape issues a GET against the metrics server, retrying until it is listening.
+func scrape(t *testing.T, addr, token string) *http.Response {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr+"/metrics", nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			return resp
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("scrape: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPrometheusAdapter_AuthToken(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "info")
+
+	cfg := appmetrics.DefaultConfig()
+	cfg.MetricsAuthToken = "s3cret"
+	m := metricsimpl.NewPrometheusAdapter(cfg, log)
+
+	addr := freeAddr(t)
+	if err := m.Serve(ctx, addr); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Shutdown(ctx) })
+
+	resp := scrape(t, addr, "")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	resp = scrape(t, addr, "wrong")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with wrong token, got %d", resp.StatusCode)
+	}
+
+	resp = scrape(t, addr, "s3cret")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", resp.StatusCode)
+	}
+}
+
+func TestPrometheusAdapter_NilLogger(t *testing.T) {
+	ctx := context.Background()
+	m := metricsimpl.NewPrometheusAdapter(appmetrics.DefaultConfig(), nil)
+
+	// Serve and Shutdown both log.
+	if err := m.Serve(ctx, "127.0.0.1:0"); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestPrometheusAdapter_ServeTwice(t *testing.T) {
+	ctx := context.Background()
+	m := metricsimpl.NewPrometheusAdapter(appmetrics.DefaultConfig(), nil)
+
+	addr := freeAddr(t)
+	if err := m.Serve(ctx, addr); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	if err := m.Serve(ctx, freeAddr(t)); !errors.Is(err, appmetrics.ErrServerRunning) {
+		t.Fatalf("expected ErrServerRunning from second Serve, got %v", err)
+	}
+
+	resp := scrape(t, addr, "")
+	_ = resp.Body.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := m.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 200*time.Millisecond)
+	if err == nil {
+		_ = conn.Close()
+		t.Fatalf("expected metrics server to stop listening after Shutdown")
+	}
+
+	// Once shut down, the adapter can serve again.
+	if err := m.Serve(ctx, addr); err != nil {
+		t.Fatalf("serve after shutdown: %v", err)
+	}
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("second shutdown: %v", err)
+	}
+}
+
+func TestPrometheusAdapter_ServeListenError(t *testing.T) {
+	ctx := context.Background()
+	m := metricsimpl.NewPrometheusAdapter(appmetrics.DefaultConfig(), nil)
+
+	if err := m.Serve(ctx, "not-an-address"); err == nil {
+		_ = m.Shutdown(ctx)
+		t.Fatalf("expected listen error")
+	}
+	// A failed Serve leaves the adapter free to serve.
+	if err := m.Serve(ctx, "127.0.0.1:0"); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	_ = m.Shutdown(ctx)
+}
+
+func TestPrometheusAdapter_RegisteredBeforeServe(t *testing.T) {
+	ctx := context.Background()
+	m := metricsimpl.NewPrometheusAdapter(appmetrics.DefaultConfig(), nil)
+
+	// Register series up front with zero values.
+	m.CounterAdd("jobs_processed_total", 0)
+	m.GaugeSet("queue_depth", 0)
+	m.HistogramObserve("job_duration_seconds", 0.5)
+
+	addr := freeAddr(t)
+	if err := m.Serve(ctx, addr); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Shutdown(ctx) })
+
+	resp := scrape(t, addr, "")
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	for _, want := range []string{
+		"jobs_processed_total 0\n",
+		"queue_depth 0\n",
+		"job_duration_seconds_count 1\n",
+		"job_duration_seconds_sum 0.5\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("expected %q in first scrape, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestPrometheusAdapter_DeferReady(t *testing.T) {
+	ctx := context.Background()
+	cfg := appmetrics.DefaultConfig()
+	cfg.DeferReady = true
+	m := metricsimpl.NewPrometheusAdapter(cfg, nil)
+
+	addr := freeAddr(t)
+	if err := m.Serve(ctx, addr); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Shutdown(ctx) })
+
+	resp := scrape(t, addr, "")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before MarkReady, got %d", resp.StatusCode)
+	}
+
+	m.CounterAdd("late_total", 0)
+	readier, ok := m.(appmetrics.ReadySignaler)
+	if !ok {
+		t.Fatalf("expected adapter to implement ReadySignaler")
+	}
+	readier.MarkReady()
+
+	resp = scrape(t, addr, "")
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 after MarkReady, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "late_total 0\n") {
+		t.Fatalf("expected late_total in scrape, got:\n%s", body)
+	}
+}
+
+func TestPrometheusAdapter_RuntimeCollectors(t *testing.T) {
+	ctx := context.Background()
+	scrapeBody := func(cfg appmetrics.Config) string {
+		m := metricsimpl.NewPrometheusAdapter(cfg, nil)
+		addr := freeAddr(t)
+		if err := m.Serve(ctx, addr); err != nil {
+			t.Fatalf("serve: %v", err)
+		}
+		defer func() { _ = m.Shutdown(ctx) }()
+
+		resp := scrape(t, addr, "")
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return string(body)
+	}
+
+	enabled := scrapeBody(appmetrics.DefaultConfig())
+	// Names and types match the Prometheus Go and process collectors.
+	for _, want := range []string{
+		"\ngo_goroutines ",
+		"go_memstats_alloc_bytes ",
+		"# TYPE go_gc_duration_seconds summary\n",
+		"go_gc_duration_seconds{quantile=\"0.5\"} ",
+		"\ngo_gc_duration_seconds_count ",
+		"\ngo_threads ",
+	} {
+		if !strings.Contains(enabled, want) {
+			t.Fatalf("expected %q with collectors enabled, got:\n%s", want, enabled)
+		}
+	}
+	for _, unwanted := range []string{"go_gc_cycles_total", "go_gc_pause_seconds_total"} {
+		if strings.Contains(enabled, unwanted) {
+			t.Fatalf("expected no %s, which the Prometheus collectors do not export", unwanted)
+		}
+	}
+	standard := []string{
+		"go_gc_duration_seconds", "go_gc_gogc_percent", "go_gc_gomemlimit_bytes", "go_goroutines", "go_info",
+		"go_memstats_alloc_bytes", "go_memstats_alloc_bytes_total", "go_memstats_buck_hash_sys_bytes",
+		"go_memstats_frees_total", "go_memstats_gc_sys_bytes", "go_memstats_heap_alloc_bytes",
+		"go_memstats_heap_idle_bytes", "go_memstats_heap_inuse_bytes", "go_memstats_heap_objects",
+		"go_memstats_heap_released_bytes", "go_memstats_heap_sys_bytes", "go_memstats_last_gc_time_seconds",
+		"go_memstats_mallocs_total", "go_memstats_mcache_inuse_bytes", "go_memstats_mcache_sys_bytes",
+		"go_memstats_mspan_inuse_bytes", "go_memstats_mspan_sys_bytes", "go_memstats_next_gc_bytes",
+		"go_memstats_other_sys_bytes", "go_memstats_stack_inuse_bytes", "go_memstats_stack_sys_bytes",
+		"go_memstats_sys_bytes", "go_sched_gomaxprocs_threads", "go_threads",
+	}
+	if runtime.GOOS == "linux" {
+		standard = append(standard,
+			"process_cpu_seconds_total", "process_max_fds", "process_network_receive_bytes_total",
+			"process_network_transmit_bytes_total", "process_open_fds", "process_resident_memory_bytes",
+			"process_start_time_seconds", "process_virtual_memory_bytes", "process_virtual_memory_max_bytes",
+		)
+	}
+	// The default series of the Prometheus Go and process collectors.
+	for _, name := range standard {
+		if !strings.Contains(enabled, "# TYPE "+name+" ") {
+			t.Fatalf("expected standard series %s, got:\n%s", name, enabled)
+		}
+	}
+	if runtime.GOOS == "linux" {
+		if open := scrapeValue(t, enabled, "process_open_fds"); open < 1 {
+			t.Fatalf("expected open file descriptors, got %v", open)
+		}
+		if rss := scrapeValue(t, enabled, "process_resident_memory_bytes"); rss <= 0 {
+			t.Fatalf("expected a resident memory size, got %v", rss)
+		}
+		// The start time comes from /proc, so it predates this test.
+		start := scrapeValue(t, enabled, "process_start_time_seconds")
+		if now := float64(time.Now().UnixNano()) / 1e9; start > now || start < now-3600 {
+			t.Fatalf("expected a recent process start time, got %v (now %v)", start, now)
+		}
+	}
+
+	cfg := appmetrics.DefaultConfig()
+	cfg.EnableGoMetrics = false
+	cfg.EnableProcessMetrics = false
+	disabled := scrapeBody(cfg)
+	if strings.Contains(disabled, "go_goroutines") || strings.Contains(disabled, "process_") {
+		t.Fatalf("expected no runtime series with collectors disabled, got:\n%s", disabled)
+	}
+}
+
+// scrapeValue returns the value of the unlabeled sample name in body.
+func scrapeValue(t *testing.T, body, name string) float64 {
+	t.Helper()
+	for _, line := range strings.Split(body, "\n") {
+		if value, ok := strings.CutPrefix(line, name+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("parse %s: %v", name, err)
+			}
+			return v
+		}
+	}
+	t.Fatalf("expected %s in scrape, got:\n%s", name, body)
+	return 0
+}
+
+func TestPrometheusAdapter_WithLabelsConcurrent(t *testing.T) {
+	ctx := context.Background()
+	m := metricsimpl.NewPrometheusAdapter(appmetrics.DefaultConfig(), nil)
+
+	const workers, iterations = 8, 100
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Go(func() {
+			for j := range iterations {
+				derived := m.WithLabels(map[string]string{"worker": strconv.Itoa(i)})
+				derived = derived.WithLabels(map[string]string{"iteration": strconv.Itoa(j)})
+				derived.CounterInc("requests_total")
+				derived.GaugeAdd("in_flight", 1)
+				derived.HistogramObserve("latency_seconds", 0.1)
+				m.CounterInc("requests_total")
+			}
+		})
+	}
+	wg.Wait()
+
+	addr := freeAddr(t)
+	if err := m.Serve(ctx, addr); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Shutdown(ctx) })
+
+	resp := scrape(t, addr, "")
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	for _, want := range []string{
+		"requests_total 1600\n",
+		"in_flight 800\n",
+		"latency_seconds_count 800\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("expected %q after concurrent recording, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+//go:build !promclient
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// gcPauseQuantiles are the quantiles of go_gc_duration_seconds, as exported
+// by the Prometheus Go collector.
+var gcPauseQuantiles = []string{"0", "0.25", "0.5", "0.75", "1"}
+
+// writeGoMetrics writes the Go runtime series exported by default by the
+// Prometheus Go collector (collectors.NewGoCollector), with the same names
+// and types.
+func writeGoMetrics(buf *bytes.Buffer) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	threads, _ := runtime.ThreadCreateProfile(nil)
+
+	writeGCDuration(buf)
+	writeGCSettings(buf)
+	fmt.Fprintf(buf, "# TYPE go_info gauge\ngo_info{version=%q} 1\n", runtime.Version())
+	writeSample(buf, "go_goroutines", "gauge", float64(runtime.NumGoroutine()))
+	writeSample(buf, "go_threads", "gauge", float64(threads))
+	writeSample(buf, "go_sched_gomaxprocs_threads", "gauge", float64(runtime.GOMAXPROCS(0)))
+	for _, s := range []struct {
+		name, kind string
+		value      uint64
+	}{
+		{"go_memstats_alloc_bytes", "gauge", ms.Alloc},
+		{"go_memstats_alloc_bytes_total", "counter", ms.TotalAlloc},
+		{"go_memstats_buck_hash_sys_bytes", "gauge", ms.BuckHashSys},
+		{"go_memstats_frees_total", "counter", ms.Frees},
+		{"go_memstats_gc_sys_bytes", "gauge", ms.GCSys},
+		{"go_memstats_heap_alloc_bytes", "gauge", ms.HeapAlloc},
+		{"go_memstats_heap_idle_bytes", "gauge", ms.HeapIdle},
+		{"go_memstats_heap_inuse_bytes", "gauge", ms.HeapInuse},
+		{"go_memstats_heap_objects", "gauge", ms.HeapObjects},
+		{"go_memstats_heap_released_bytes", "gauge", ms.HeapReleased},
+		{"go_memstats_heap_sys_bytes", "gauge", ms.HeapSys},
+		{"go_memstats_mallocs_total", "counter", ms.Mallocs},
+		{"go_memstats_mcache_inuse_bytes", "gauge", ms.MCacheInuse},
+		{"go_memstats_mcache_sys_bytes", "gauge", ms.MCacheSys},
+		{"go_memstats_mspan_inuse_bytes", "gauge", ms.MSpanInuse},
+		{"go_memstats_mspan_sys_bytes", "gauge", ms.MSpanSys},
+		{"go_memstats_next_gc_bytes", "gauge", ms.NextGC},
+		{"go_memstats_other_sys_bytes", "gauge", ms.OtherSys},
+		{"go_memstats_stack_inuse_bytes", "gauge", ms.StackInuse},
+		{"go_memstats_stack_sys_bytes", "gauge", ms.StackSys},
+		{"go_memstats_sys_bytes", "gauge", ms.Sys},
+	} {
+		writeSample(buf, s.name, s.kind, float64(s.value))
+	}
+	writeSample(buf, "go_memstats_last_gc_time_seconds", "gauge", float64(ms.LastGC)/1e9)
+}
+
+// writeGCSettings writes go_gc_gogc_percent and go_gc_gomemlimit_bytes, the
+// GOGC and GOMEMLIMIT settings, from runtime/metrics.
+func writeGCSettings(buf *bytes.Buffer) {
+	samples := []metrics.Sample{{Name: "/gc/gogc:percent"}, {Name: "/gc/gomemlimit:bytes"}}
+	metrics.Read(samples)
+	for i, name := range []string{"go_gc_gogc_percent", "go_gc_gomemlimit_bytes"} {
+		if samples[i].Value.Kind() == metrics.KindUint64 {
+			writeSample(buf, name, "gauge", float64(samples[i].Value.Uint64()))
+		}
+	}
+}
+
+// writeGCDuration writes go_gc_duration_seconds: a summary of recent GC pause
+// durations with the count and total of all pauses.
+func writeGCDuration(buf *bytes.Buffer) {
+	stats := debug.GCStats{PauseQuantiles: make([]time.Duration, len(gcPauseQuantiles))}
+	debug.ReadGCStats(&stats)
+
+	buf.WriteString("# TYPE go_gc_duration_seconds summary\n")
+	for i, q := range gcPauseQuantiles {
+		fmt.Fprintf(buf, "go_gc_duration_seconds{quantile=%q} %s\n", q, formatValue(stats.PauseQuantiles[i].Seconds()))
+	}
+	fmt.Fprintf(buf, "go_gc_duration_seconds_sum %s\n", formatValue(stats.PauseTotal.Seconds()))
+	fmt.Fprintf(buf, "go_gc_duration_seconds_count %d\n", stats.NumGC)
+}
+
+// writeProcessMetrics writes the series exported by the Prometheus process
+// collector (collectors.NewProcessCollector). Series the platform does not
+// report are omitted: process_cpu_seconds_total needs getrusage and the
+// others need /proc.
+func writeProcessMetrics(buf *bytes.Buffer) {
+	if cpu, ok := processCPUSeconds(); ok {
+		writeSample(buf, "process_cpu_seconds_total", "counter", cpu)
+	}
+	if start, ok := processStartTime(); ok {
+		writeSample(buf, "process_start_time_seconds", "gauge", float64(start.UnixNano())/1e9)
+	}
+	if virtual, resident, ok := processMemory(); ok {
+		writeSample(buf, "process_virtual_memory_bytes", "gauge", virtual)
+		writeSample(buf, "process_resident_memory_bytes", "gauge", resident)
+	}
+	if open, ok := processOpenFDs(); ok {
+		writeSample(buf, "process_open_fds", "gauge", open)
+	}
+	if maxFDs, maxVirtual, ok := processLimits(); ok {
+		writeSample(buf, "process_max_fds", "gauge", maxFDs)
+		writeSample(buf, "process_virtual_memory_max_bytes", "gauge", maxVirtual)
+	}
+	if received, transmitted, ok := processNetwork(); ok {
+		writeSample(buf, "process_network_receive_bytes_total", "counter", received)
+		writeSample(buf, "process_network_transmit_bytes_total", "counter", transmitted)
+	}
+}
+
+// writeSample writes a single unlabeled sample with its TYPE line.
+func writeSample(buf *bytes.Buffer, name, kind string, value float64) {
+	fmt.Fprintf(buf, "# TYPE %s %s\n%s %s\n", name, kind, name, formatValue(value))
+}
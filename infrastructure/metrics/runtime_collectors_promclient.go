@@ -0,0 +1,46 @@
+//go:build promclient
+
+package metrics
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/common/expfmt"
+)
+
+// runtimeRegistries holds the client_golang Go and process collectors, each
+// in its own registry so that the two can be enabled separately.
+var runtimeRegistries = sync.OnceValues(func() (*prometheus.Registry, *prometheus.Registry) {
+	goRegistry := prometheus.NewRegistry()
+	goRegistry.MustRegister(collectors.NewGoCollector())
+	processRegistry := prometheus.NewRegistry()
+	processRegistry.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return goRegistry, processRegistry
+})
+
+// writeGoMetrics writes the series of the Prometheus Go collector
+// (collectors.NewGoCollector) when building with the promclient tag.
+func writeGoMetrics(buf *bytes.Buffer) {
+	goRegistry, _ := runtimeRegistries()
+	writeGathered(buf, goRegistry)
+}
+
+// writeProcessMetrics writes the series of the Prometheus process collector
+// (collectors.NewProcessCollector) when building with the promclient tag.
+func writeProcessMetrics(buf *bytes.Buffer) {
+	_, processRegistry := runtimeRegistries()
+	writeGathered(buf, processRegistry)
+}
+
+// writeGathered writes every metric family in registry in the text format.
+// Families that fail to gather are skipped, as the process collector does for
+// series the platform does not report.
+func writeGathered(buf *bytes.Buffer, registry *prometheus.Registry) {
+	families, _ := registry.Gather()
+	for _, mf := range families {
+		_, _ = expfmt.MetricFamilyToText(buf, mf)
+	}
+}
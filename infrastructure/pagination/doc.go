@@ -1,3 +1,4 @@
 // Package pagination provides a simple adapter that satisfies application/pagination
-// for offset/cursor helpers.
+// for offset/cursor helpers. BindItemFilter turns list query parameters into a
+// validated repository.ItemFilter.
 package pagination
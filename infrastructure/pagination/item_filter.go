@@ -0,0 +1,105 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	apppagination "github.com/next-trace/scg-service-api/application/pagination"
+	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/next-trace/scg-service-api/domain/repository"
+	"github.com/next-trace/scg-service-api/domain/service"
+)
+
+// BindItemFilter builds an ItemFilter from the query string of a list request:
+//
+//	page       1-based page number, default 1
+//	page_size  items per page, default DefaultPageSize, at most MaxPageSize
+//	sort       a field from allowedSorts, prefixed with "-" for descending order
+//	status     "active" or "inactive"; soft-deleted items stay hidden unless
+//	           the caller opts in with ItemFilter.WithDeleted
+//	tags       comma-separated tags, or the parameter repeated
+//
+// Pass the search results to NewItemPage for the page links. Malformed or
+// out-of-range values, including a sort field not in
+// allowedSorts, return a domainerrors.ErrInvalidInput error.
+func BindItemFilter(r *http.Request, allowedSorts []string) (repository.ItemFilter, error) {
+	query := r.URL.Query()
+	filter := repository.NewItemFilter()
+
+	page, err := intParam(query.Get("page"), 1, 1, 0)
+	if err != nil {
+		return repository.ItemFilter{}, domainerrors.NewInvalidInput("page " + err.Error())
+	}
+	pageSize, err := intParam(query.Get("page_size"), apppagination.DefaultPageSize, 1, apppagination.MaxPageSize)
+	if err != nil {
+		return repository.ItemFilter{}, domainerrors.NewInvalidInput("page_size " + err.Error())
+	}
+	if page-1 > math.MaxInt/pageSize {
+		return repository.ItemFilter{}, domainerrors.NewInvalidInput("page is out of range")
+	}
+	filter = filter.WithPagination((page-1)*pageSize, pageSize)
+
+	if sort := query.Get("sort"); sort != "" {
+		if !slices.Contains(allowedSorts, strings.TrimPrefix(sort, "-")) {
+			return repository.ItemFilter{}, domainerrors.NewInvalidInput(fmt.Sprintf("cannot sort by %q", strings.TrimPrefix(sort, "-"))).
+				WithDetail("allowed_sorts", allowedSorts)
+		}
+		filter = filter.WithSort(sort)
+	}
+
+	if status := entity.ItemStatus(query.Get("status")); status != "" {
+		switch status {
+		case entity.ItemStatusActive, entity.ItemStatusInactive:
+			filter = filter.WithStatus(status)
+		case entity.ItemStatusDeleted:
+			return repository.ItemFilter{}, domainerrors.NewInvalidInput(fmt.Sprintf("cannot filter by status %q", status))
+		default:
+			return repository.ItemFilter{}, domainerrors.NewInvalidInput(fmt.Sprintf("unknown status %q", status))
+		}
+	}
+
+	var tags []string
+	for _, value := range query["tags"] {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if len(tags) > 0 {
+		filter = filter.WithTags(tags)
+	}
+
+	return filter, nil
+}
+
+// NewItemPage renders a page of search results with links to the other
+// pages of r's URL, keeping its filter and sort parameters.
+func NewItemPage(r *http.Request, result *service.ItemPage) Page[*entity.Item] {
+	return NewPage(result.Items, result.TotalItems, result.Page, result.PageSize, r.URL.RequestURI())
+}
+
+// intParam parses an integer query parameter, returning def when it is
+// empty. A maximum of 0 means no upper bound.
+func intParam(value string, def, minimum, maximum int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("must be an integer")
+	}
+	if n < minimum || (maximum > 0 && n > maximum) {
+		if maximum > 0 {
+			return 0, fmt.Errorf("must be between %d and %d", minimum, maximum)
+		}
+		return 0, fmt.Errorf("must be at least %d", minimum)
+	}
+	return n, nil
+}
@@ -0,0 +1,92 @@
+package pagination_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apppagination "github.com/next-trace/scg-service-api/application/pagination"
+	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/next-trace/scg-service-api/domain/service"
+	"github.com/next-trace/scg-service-api/infrastructure/pagination"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var itemSorts = []string{"name", "created_at"}
+
+func TestBindItemFilter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items?page=3&page_size=10&sort=-created_at&status=inactive&tags=a,b&tags=c", nil)
+
+	filter, err := pagination.BindItemFilter(req, itemSorts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, filter.Offset)
+	assert.Equal(t, 10, filter.Limit)
+	assert.Equal(t, "-created_at", filter.Sort)
+	assert.Equal(t, entity.ItemStatusInactive, filter.Status)
+	assert.Equal(t, []string{"a", "b", "c"}, filter.Tags)
+}
+
+func TestBindItemFilter_Defaults(t *testing.T) {
+	filter, err := pagination.BindItemFilter(httptest.NewRequest(http.MethodGet, "/items", nil), itemSorts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, filter.Offset)
+	assert.Equal(t, apppagination.DefaultPageSize, filter.Limit)
+	assert.Empty(t, filter.Sort)
+	assert.Empty(t, filter.Status)
+	assert.Empty(t, filter.Tags)
+	assert.False(t, filter.IncludeDeleted)
+}
+
+func TestBindItemFilter_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Unknown sort field", "sort=password"},
+		{"Unknown descending sort field", "sort=-password"},
+		{"Non-numeric page", "page=two"},
+		{"Zero page", "page=0"},
+		{"Page size too large", "page_size=1000"},
+		{"Offset overflows", "page=92233720368547760&page_size=100"},
+		{"Unknown status", "status=archived"},
+		{"Deleted status bypasses opt-in", "status=deleted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pagination.BindItemFilter(httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil), itemSorts)
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidInput)
+		})
+	}
+}
+
+func TestNewItemPage(t *testing.T) {
+	var items []*entity.Item
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		items = append(items, &entity.Item{ID: id, Name: "item " + id, Status: entity.ItemStatusActive})
+	}
+	svc := service.NewItemService(testsupport.NewItemRepository(items...))
+
+	req := httptest.NewRequest(http.MethodGet, "/items?status=active&page=2&page_size=2", nil)
+	filter, err := pagination.BindItemFilter(req, itemSorts)
+	require.NoError(t, err)
+	result, err := svc.SearchItems(req.Context(), filter)
+	require.NoError(t, err)
+
+	page := pagination.NewItemPage(req, result)
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, 3, page.TotalPages)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "3", page.Items[0].ID)
+	assert.Equal(t, map[string]string{
+		"self":  "/items?page=2&page_size=2&status=active",
+		"first": "/items?page=1&page_size=2&status=active",
+		"prev":  "/items?page=1&page_size=2&status=active",
+		"next":  "/items?page=3&page_size=2&status=active",
+		"last":  "/items?page=3&page_size=2&status=active",
+	}, page.Links)
+}
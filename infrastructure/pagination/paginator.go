@@ -1,8 +1,9 @@
 package pagination
 
 import (
-	"fmt"
 	"math"
+	"net/url"
+	"strconv"
 )
 
 // Page represents a standardized paginated API response.
@@ -15,7 +16,9 @@ type Page[T any] struct {
 	Links      map[string]string `json:"links,omitempty"`
 }
 
-// NewPage creates a paginated response object.
+// NewPage creates a paginated response object. Links are built from baseURL,
+// whose other query parameters, such as search criteria, are kept; page and
+// page_size parameters already on it are replaced.
 func NewPage[T any](items []T, totalItems int64, page, pageSize int, baseURL string) Page[T] {
 	totalPages := 0
 	if pageSize > 0 {
@@ -31,14 +34,27 @@ func NewPage[T any](items []T, totalItems int64, page, pageSize int, baseURL str
 		Links:      make(map[string]string),
 	}
 
-	p.Links["self"] = fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, page, pageSize)
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		base = &url.URL{Path: baseURL}
+	}
+	link := func(n int) string {
+		u := *base
+		query := u.Query()
+		query.Set("page", strconv.Itoa(n))
+		query.Set("page_size", strconv.Itoa(pageSize))
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	p.Links["self"] = link(page)
 	if page > 1 {
-		p.Links["first"] = fmt.Sprintf("%s?page=1&page_size=%d", baseURL, pageSize)
-		p.Links["prev"] = fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, page-1, pageSize)
+		p.Links["first"] = link(1)
+		p.Links["prev"] = link(page - 1)
 	}
 	if page < totalPages {
-		p.Links["next"] = fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, page+1, pageSize)
-		p.Links["last"] = fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, totalPages, pageSize)
+		p.Links["next"] = link(page + 1)
+		p.Links["last"] = link(totalPages)
 	}
 
 	return p
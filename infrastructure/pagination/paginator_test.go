@@ -117,3 +117,11 @@ func TestNewPage(t *testing.T) {
 		assert.NotContains(t, page.Links, "last")
 	})
 }
+
+func TestNewPage_ReplacesPageParameters(t *testing.T) {
+	page := pagination.NewPage([]TestItem{}, 10, 2, 3, "/api/items?page=2&page_size=3&q=widget")
+
+	assert.Equal(t, "/api/items?page=2&page_size=3&q=widget", page.Links["self"])
+	assert.Equal(t, "/api/items?page=1&page_size=3&q=widget", page.Links["prev"])
+	assert.Equal(t, "/api/items?page=3&page_size=3&q=widget", page.Links["next"])
+}
@@ -0,0 +1,123 @@
+//go:build !xrate
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+)
+
+// rateLimiter is the built-in token bucket used when the xrate build tag is
+// not set. It mirrors the subset of golang.org/x/time/rate the limiter uses.
+type rateLimiter struct {
+	limit  float64
+	burst  int
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
+}
+
+// newRateLimiter creates a full bucket refilling at limit tokens per second.
+func newRateLimiter(limit float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// advanceLocked refills the bucket for the time elapsed since it was last used.
+// r.mu must be held.
+func (r *rateLimiter) advanceLocked(now time.Time) {
+	elapsed := now.Sub(r.last).Seconds()
+	r.last = now
+	r.tokens += elapsed * r.limit
+	if r.tokens > float64(r.burst) {
+		r.tokens = float64(r.burst)
+	}
+}
+
+// AllowN takes n tokens if they are all available.
+func (r *rateLimiter) AllowN(n int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advanceLocked(time.Now())
+	if r.tokens < float64(n) {
+		return false
+	}
+	r.tokens -= float64(n)
+	return true
+}
+
+// ReserveN takes n tokens, letting the bucket go into debt when fewer are
+// available, and returns how long the caller must wait for the debt to be
+// repaid. Callers that give up before then hand the tokens back with cancel.
+func (r *rateLimiter) ReserveN(n int) (time.Duration, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advanceLocked(time.Now())
+	r.tokens -= float64(n)
+	cancel := func() { r.cancelN(n) }
+	if r.tokens >= 0 {
+		return 0, cancel
+	}
+	waitTime := -r.tokens / r.limit
+	return time.Duration(waitTime * float64(time.Second)), cancel
+}
+
+// cancelN returns n reserved tokens to the bucket.
+func (r *rateLimiter) cancelN(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens += float64(n)
+	if r.tokens > float64(r.burst) {
+		r.tokens = float64(r.burst)
+	}
+}
+
+// PeekN returns how long until n tokens are available, without taking them.
+func (r *rateLimiter) PeekN(n int) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Since(r.last).Seconds()
+	tokens := r.tokens + elapsed*r.limit
+	if tokens > float64(r.burst) {
+		tokens = float64(r.burst)
+	}
+	if tokens >= float64(n) {
+		return 0
+	}
+	tokensNeeded := float64(n) - tokens
+	waitTime := tokensNeeded / r.limit
+	return time.Duration(waitTime * float64(time.Second))
+}
+
+// Stats returns a snapshot of the bucket.
+func (r *rateLimiter) Stats() appratelimit.LimiterStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Since(r.last).Seconds()
+	tokens := r.tokens + elapsed*r.limit
+	if tokens > float64(r.burst) {
+		tokens = float64(r.burst)
+	}
+	stats := appratelimit.LimiterStats{Tokens: tokens, Burst: r.burst}
+	if tokens < 1 {
+		stats.NextToken = time.Duration((1 - tokens) / r.limit * float64(time.Second))
+	}
+	return stats
+}
+
+// idleSince reports whether the limiter has not been used since cutoff and
+// has refilled to its burst, so dropping it cannot loosen the limit.
+func (r *rateLimiter) idleSince(cutoff time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last.After(cutoff) {
+		return false
+	}
+	return r.tokens+time.Since(r.last).Seconds()*r.limit >= float64(r.burst)
+}
@@ -0,0 +1,89 @@
+//go:build xrate
+
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+)
+
+// rateLimiter wraps a rate.Limiter when building with the xrate tag.
+type rateLimiter struct {
+	lim *rate.Limiter
+
+	// last is when the limiter was last used, for idle eviction; guarded by mu.
+	last time.Time
+	mu   sync.Mutex
+}
+
+// newRateLimiter creates a full bucket refilling at limit tokens per second.
+func newRateLimiter(limit float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		lim:  rate.NewLimiter(rate.Limit(limit), burst),
+		last: time.Now(),
+	}
+}
+
+// touch records a use of the limiter and returns the current time.
+func (r *rateLimiter) touch() time.Time {
+	now := time.Now()
+	r.mu.Lock()
+	r.last = now
+	r.mu.Unlock()
+	return now
+}
+
+// AllowN takes n tokens if they are all available.
+func (r *rateLimiter) AllowN(n int) bool {
+	return r.lim.AllowN(r.touch(), n)
+}
+
+// ReserveN takes n tokens, letting the bucket go into debt when fewer are
+// available, and returns how long the caller must wait for the debt to be
+// repaid along with a func that hands the tokens back. Unlike the built-in
+// bucket, rate.Limiter refuses reservations above the burst: those reserve
+// nothing and report rate.InfDuration.
+func (r *rateLimiter) ReserveN(n int) (time.Duration, func()) {
+	now := r.touch()
+	res := r.lim.ReserveN(now, n)
+	if !res.OK() {
+		return rate.InfDuration, func() {}
+	}
+	return res.DelayFrom(now), res.Cancel
+}
+
+// PeekN returns how long until n tokens are available, without taking them.
+func (r *rateLimiter) PeekN(n int) time.Duration {
+	tokens := r.lim.Tokens()
+	if tokens >= float64(n) {
+		return 0
+	}
+	waitTime := (float64(n) - tokens) / float64(r.lim.Limit())
+	return time.Duration(waitTime * float64(time.Second))
+}
+
+// Stats returns a snapshot of the bucket.
+func (r *rateLimiter) Stats() appratelimit.LimiterStats {
+	tokens := r.lim.Tokens()
+	stats := appratelimit.LimiterStats{Tokens: tokens, Burst: r.lim.Burst()}
+	if tokens < 1 {
+		stats.NextToken = time.Duration((1 - tokens) / float64(r.lim.Limit()) * float64(time.Second))
+	}
+	return stats
+}
+
+// idleSince reports whether the limiter has not been used since cutoff and
+// has refilled to its burst, so dropping it cannot loosen the limit.
+func (r *rateLimiter) idleSince(cutoff time.Time) bool {
+	r.mu.Lock()
+	last := r.last
+	r.mu.Unlock()
+	if last.After(cutoff) {
+		return false
+	}
+	return r.lim.Tokens() >= float64(r.lim.Burst())
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+)
+
+// timeoutLimiter bounds every call of the wrapped limiter with a default
+// timeout when the caller's context carries no deadline of its own.
+type timeoutLimiter struct {
+	appratelimit.Limiter
+	timeout time.Duration
+}
+
+// WithDefaultTimeout wraps next so that each call runs under
+// context.WithTimeout(ctx, timeout) unless ctx already has a deadline, in
+// which case the caller's deadline is kept. This mostly matters for Wait and
+// WaitN, which otherwise block for as long as the key needs to refill. A
+// non-positive timeout returns next unchanged. Optional capabilities such as
+// KeyCounter are not forwarded.
+func WithDefaultTimeout(next appratelimit.Limiter, timeout time.Duration) appratelimit.Limiter {
+	if timeout <= 0 {
+		return next
+	}
+	return &timeoutLimiter{Limiter: next, timeout: timeout}
+}
+
+// bound returns ctx with the default timeout applied if it has no deadline.
+func (l *timeoutLimiter) bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, l.timeout)
+}
+
+// Allow runs the wrapped Allow under the default timeout.
+func (l *timeoutLimiter) Allow(ctx context.Context, key string) bool {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.Allow(ctx, key)
+}
+
+// AllowN runs the wrapped AllowN under the default timeout.
+func (l *timeoutLimiter) AllowN(ctx context.Context, key string, n int) bool {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.AllowN(ctx, key, n)
+}
+
+// Wait runs the wrapped Wait under the default timeout.
+func (l *timeoutLimiter) Wait(ctx context.Context, key string) error {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.Wait(ctx, key)
+}
+
+// WaitN runs the wrapped WaitN under the default timeout.
+func (l *timeoutLimiter) WaitN(ctx context.Context, key string, n int) error {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.WaitN(ctx, key, n)
+}
+
+// Reserve runs the wrapped Reserve under the default timeout.
+func (l *timeoutLimiter) Reserve(ctx context.Context, key string) time.Duration {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.Reserve(ctx, key)
+}
+
+// ReserveN runs the wrapped ReserveN under the default timeout.
+func (l *timeoutLimiter) ReserveN(ctx context.Context, key string, n int) time.Duration {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.ReserveN(ctx, key, n)
+}
+
+// Peek runs the wrapped Peek under the default timeout.
+func (l *timeoutLimiter) Peek(ctx context.Context, key string) time.Duration {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.Peek(ctx, key)
+}
+
+// PeekN runs the wrapped PeekN under the default timeout.
+func (l *timeoutLimiter) PeekN(ctx context.Context, key string, n int) time.Duration {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.PeekN(ctx, key, n)
+}
+
+// Stats runs the wrapped Stats under the default timeout.
+func (l *timeoutLimiter) Stats(ctx context.Context, key string) (appratelimit.LimiterStats, bool) {
+	ctx, cancel := l.bound(ctx)
+	defer cancel()
+	return l.Limiter.Stats(ctx, key)
+}
@@ -0,0 +1,51 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+	limiterimpl "github.com/next-trace/scg-service-api/infrastructure/ratelimit"
+)
+
+func TestWithDefaultTimeout_BoundsWait(t *testing.T) {
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1
+	cfg.Burst = 1
+	cfg.Period = time.Hour
+	l := limiterimpl.WithDefaultTimeout(limiterimpl.NewTokenBucketLimiter(cfg, nil), 20*time.Millisecond)
+
+	ctx := context.Background()
+	if !l.Allow(ctx, "k") {
+		t.Fatal("expected the first request to be allowed")
+	}
+
+	start := time.Now()
+	err := l.Wait(ctx, "k")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the default timeout to end Wait, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected Wait to stop near the default timeout, took %v", elapsed)
+	}
+}
+
+func TestWithDefaultTimeout_KeepsCallerDeadline(t *testing.T) {
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1
+	cfg.Burst = 1
+	cfg.Period = 50 * time.Millisecond
+	l := limiterimpl.WithDefaultTimeout(limiterimpl.NewTokenBucketLimiter(cfg, nil), time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if !l.Allow(ctx, "k") {
+		t.Fatal("expected the first request to be allowed")
+	}
+	// The refill takes longer than the default timeout but fits the caller's deadline.
+	if err := l.Wait(ctx, "k"); err != nil {
+		t.Fatalf("expected the caller deadline to be respected, got %v", err)
+	}
+}
@@ -1,7 +1,9 @@
 // Package ratelimit provides rate limiting functionality.
 //
-// Note: This package requires the following dependencies:
-// - golang.org/x/time/rate
+// By default the package uses a built-in token bucket with no external
+// dependencies. Building with the xrate tag (go build -tags xrate) switches
+// the bucket to golang.org/x/time/rate, which must then be required in
+// go.mod. The public API is identical in both configurations.
 //
 // See docs/dependencies.md for more information.
 package ratelimit
@@ -15,128 +17,43 @@ import (
 	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
 )
 
-// Type aliases for rate limiter types
-// These are placeholders for the actual types from the rate package
-// They will be replaced with the actual types when the dependencies are added
-type (
-	// rateLimiter represents a token bucket rate limiter
-	rateLimiter struct {
-		limit  float64
-		burst  int
-		tokens float64
-		last   time.Time
-		mu     sync.Mutex
-	}
-)
-
-// Mock methods for the rateLimiter type
-func newRateLimiter(limit float64, burst int) *rateLimiter {
-	return &rateLimiter{
-		limit:  limit,
-		burst:  burst,
-		tokens: float64(burst),
-		last:   time.Now(),
-	}
-}
+// rateLimiter, the per-key token bucket, is defined in limiter_builtin.go or,
+// with the xrate build tag, limiter_xrate.go. Both provide newRateLimiter,
+// AllowN, ReserveN, PeekN, Stats and idleSince; the methods below build on those.
 
+// Allow takes one token if it is available.
 func (r *rateLimiter) Allow() bool {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	now := time.Now()
-	elapsed := now.Sub(r.last).Seconds()
-	r.last = now
-	r.tokens += elapsed * r.limit
-	if r.tokens > float64(r.burst) {
-		r.tokens = float64(r.burst)
-	}
-	if r.tokens < 1 {
-		return false
-	}
-	r.tokens--
-	return true
-}
-
-func (r *rateLimiter) AllowN(n int) bool {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	now := time.Now()
-	elapsed := now.Sub(r.last).Seconds()
-	r.last = now
-	r.tokens += elapsed * r.limit
-	if r.tokens > float64(r.burst) {
-		r.tokens = float64(r.burst)
-	}
-	if r.tokens < float64(n) {
-		return false
-	}
-	r.tokens -= float64(n)
-	return true
+	return r.AllowN(1)
 }
 
+// Reserve takes one token and returns how long to wait until it is available.
 func (r *rateLimiter) Reserve() time.Duration {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	now := time.Now()
-	elapsed := now.Sub(r.last).Seconds()
-	r.last = now
-	r.tokens += elapsed * r.limit
-	if r.tokens > float64(r.burst) {
-		r.tokens = float64(r.burst)
-	}
-	if r.tokens >= 1 {
-		r.tokens--
-		return 0
-	}
-	tokensNeeded := 1 - r.tokens
-	waitTime := tokensNeeded / r.limit
-	return time.Duration(waitTime * float64(time.Second))
-}
-
-func (r *rateLimiter) ReserveN(n int) time.Duration {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	now := time.Now()
-	elapsed := now.Sub(r.last).Seconds()
-	r.last = now
-	r.tokens += elapsed * r.limit
-	if r.tokens > float64(r.burst) {
-		r.tokens = float64(r.burst)
-	}
-	if r.tokens >= float64(n) {
-		r.tokens -= float64(n)
-		return 0
-	}
-	tokensNeeded := float64(n) - r.tokens
-	waitTime := tokensNeeded / r.limit
-	return time.Duration(waitTime * float64(time.Second))
+	wait, _ := r.ReserveN(1)
+	return wait
 }
 
+// Wait blocks until one token is available.
 func (r *rateLimiter) Wait(ctx context.Context) error {
-	waitTime := r.Reserve()
-	if waitTime == 0 {
-		return nil
-	}
-	timer := time.NewTimer(waitTime)
-	select {
-	case <-timer.C:
-		return nil
-	case <-ctx.Done():
-		timer.Stop()
-		return ctx.Err()
-	}
+	return r.WaitN(ctx, 1)
 }
 
+// WaitN reserves n tokens and blocks until they are available. If ctx is
+// done first, the reservation is cancelled so the tokens go to later callers.
 func (r *rateLimiter) WaitN(ctx context.Context, n int) error {
-	waitTime := r.ReserveN(n)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	waitTime, cancel := r.ReserveN(n)
 	if waitTime == 0 {
 		return nil
 	}
 	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
 	select {
 	case <-timer.C:
 		return nil
 	case <-ctx.Done():
-		timer.Stop()
+		cancel()
 		return ctx.Err()
 	}
 }
@@ -147,14 +64,25 @@ type tokenBucketLimiter struct {
 	limiters map[string]*rateLimiter
 	mu       sync.RWMutex
 	log      applogger.Logger
+
+	// lastSweep is when idle keys were last evicted; guarded by mu.
+	lastSweep time.Time
 }
 
+// Ensure tokenBucketLimiter reports its key count.
+var _ appratelimit.KeyCounter = (*tokenBucketLimiter)(nil)
+
+// Ensure tokenBucketLimiter can cancel its reservations.
+var _ appratelimit.Reserver = (*tokenBucketLimiter)(nil)
+
 // NewTokenBucketLimiter creates a new token bucket rate limiter.
 func NewTokenBucketLimiter(config appratelimit.Config, log applogger.Logger) appratelimit.Limiter {
+	log = applogger.OrNop(log)
 	return &tokenBucketLimiter{
-		config:   config,
-		limiters: make(map[string]*rateLimiter),
-		log:      log,
+		config:    config,
+		limiters:  make(map[string]*rateLimiter),
+		log:       log,
+		lastSweep: time.Now(),
 	}
 }
 
@@ -177,6 +105,8 @@ func (t *tokenBucketLimiter) getLimiter(key string) *rateLimiter {
 		return limiter
 	}
 
+	t.evictIdleLocked()
+
 	// Calculate rate as tokens per second
 	rate := float64(t.config.Rate) / t.config.Period.Seconds()
 	limiter = newRateLimiter(rate, t.config.Burst)
@@ -184,6 +114,34 @@ func (t *tokenBucketLimiter) getLimiter(key string) *rateLimiter {
 	return limiter
 }
 
+// evictIdleLocked drops limiters idle for longer than Config.IdleTimeout. It
+// runs when a new key is added, at most once per IdleTimeout, so the cost of
+// the scan is spread over key creations. t.mu must be held for writing.
+func (t *tokenBucketLimiter) evictIdleLocked() {
+	if t.config.IdleTimeout <= 0 {
+		return
+	}
+	now := time.Now()
+	if now.Sub(t.lastSweep) < t.config.IdleTimeout {
+		return
+	}
+	t.lastSweep = now
+
+	cutoff := now.Add(-t.config.IdleTimeout)
+	for key, limiter := range t.limiters {
+		if limiter.idleSince(cutoff) {
+			delete(t.limiters, key)
+		}
+	}
+}
+
+// Len returns the number of keys with limiter state.
+func (t *tokenBucketLimiter) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.limiters)
+}
+
 // Allow checks if a request is allowed based on the key.
 func (t *tokenBucketLimiter) Allow(ctx context.Context, key string) bool {
 	_ = ctx
@@ -227,23 +185,75 @@ func (t *tokenBucketLimiter) WaitN(ctx context.Context, key string, n int) error
 }
 
 // Reserve reserves a token and returns the time to wait before the token is available.
+// It returns -1 without reserving anything when ctx is already done.
 func (t *tokenBucketLimiter) Reserve(ctx context.Context, key string) time.Duration {
-	_ = ctx
 	if !t.config.Enabled {
 		return 0
 	}
+	if ctx.Err() != nil {
+		return -1
+	}
 
 	limiter := t.getLimiter(key)
 	return limiter.Reserve()
 }
 
 // ReserveN reserves n tokens and returns the time to wait before the tokens are available.
+// It returns -1 without reserving anything when ctx is already done.
 func (t *tokenBucketLimiter) ReserveN(ctx context.Context, key string, n int) time.Duration {
+	if !t.config.Enabled {
+		return 0
+	}
+	if ctx.Err() != nil {
+		return -1
+	}
+
+	limiter := t.getLimiter(key)
+	wait, _ := limiter.ReserveN(n)
+	return wait
+}
+
+// ReserveCancelable reserves n tokens like ReserveN and returns a reservation
+// whose Cancel gives them back to the bucket.
+func (t *tokenBucketLimiter) ReserveCancelable(ctx context.Context, key string, n int) appratelimit.Reservation {
+	if !t.config.Enabled {
+		return appratelimit.Reservation{Cancel: func() {}}
+	}
+	if ctx.Err() != nil {
+		return appratelimit.Reservation{Delay: -1, Cancel: func() {}}
+	}
+
+	limiter := t.getLimiter(key)
+	wait, cancel := limiter.ReserveN(n)
+	return appratelimit.Reservation{Delay: wait, Cancel: sync.OnceFunc(cancel)}
+}
+
+// Peek returns the time to wait before a token is available without consuming it.
+func (t *tokenBucketLimiter) Peek(ctx context.Context, key string) time.Duration {
+	return t.PeekN(ctx, key, 1)
+}
+
+// PeekN returns the time to wait before n tokens are available without consuming them.
+func (t *tokenBucketLimiter) PeekN(ctx context.Context, key string, n int) time.Duration {
 	_ = ctx
 	if !t.config.Enabled {
 		return 0
 	}
 
 	limiter := t.getLimiter(key)
-	return limiter.ReserveN(n)
+	return limiter.PeekN(n)
+}
+
+// Stats returns a snapshot of the limiter state for key without consuming tokens.
+// Unknown keys report false instead of creating a limiter.
+func (t *tokenBucketLimiter) Stats(ctx context.Context, key string) (appratelimit.LimiterStats, bool) {
+	_ = ctx
+	t.mu.RLock()
+	limiter, exists := t.limiters[key]
+	t.mu.RUnlock()
+
+	if !exists {
+		return appratelimit.LimiterStats{}, false
+	}
+	return limiter.Stats(), true
 }
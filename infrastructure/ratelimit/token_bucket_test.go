@@ -39,3 +39,167 @@ func TestTokenBucketLimiter_AllowAndWait(t *testing.T) {
 		t.Fatalf("expected non-negative reserve, got %v", d)
 	}
 }
+
+func TestTokenBucketLimiter_Stats(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&buf, "debug")
+
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1
+	cfg.Period = time.Hour
+	cfg.Burst = 3
+
+	lim := limiterimpl.NewTokenBucketLimiter(cfg, log)
+
+	if _, ok := lim.Stats(ctx, "unknown"); ok {
+		t.Fatalf("expected no stats for an unseen key")
+	}
+
+	if !lim.Allow(ctx, "k") {
+		t.Fatalf("expected first allow")
+	}
+	stats, ok := lim.Stats(ctx, "k")
+	if !ok {
+		t.Fatalf("expected stats after first request")
+	}
+	if stats.Burst != 3 {
+		t.Fatalf("expected burst 3, got %d", stats.Burst)
+	}
+	if stats.Tokens < 1.9 || stats.Tokens > 2.1 {
+		t.Fatalf("expected about 2 tokens, got %v", stats.Tokens)
+	}
+	if stats.NextToken != 0 {
+		t.Fatalf("expected a token to be available now, got wait %v", stats.NextToken)
+	}
+
+	if !lim.AllowN(ctx, "k", 2) {
+		t.Fatalf("expected remaining burst to be allowed")
+	}
+	after, _ := lim.Stats(ctx, "k")
+	if after.Tokens >= stats.Tokens || after.Tokens >= 1 {
+		t.Fatalf("expected tokens to decrease below one, got %v", after.Tokens)
+	}
+	if after.NextToken <= 0 {
+		t.Fatalf("expected a positive wait for the next token, got %v", after.NextToken)
+	}
+
+	// Stats must not consume tokens.
+	again, _ := lim.Stats(ctx, "k")
+	if again.Tokens < after.Tokens {
+		t.Fatalf("expected Stats to be read-only, tokens went from %v to %v", after.Tokens, again.Tokens)
+	}
+}
+
+func TestTokenBucketLimiter_NilLogger(t *testing.T) {
+	l := limiterimpl.NewTokenBucketLimiter(appratelimit.DefaultConfig(), nil)
+	if !l.Allow(context.Background(), "k") {
+		t.Fatalf("expected first request to be allowed")
+	}
+}
+
+func TestTokenBucketLimiter_LenAndIdleEviction(t *testing.T) {
+	ctx := context.Background()
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1000
+	cfg.Period = time.Second
+	cfg.Burst = 1
+	cfg.IdleTimeout = 20 * time.Millisecond
+
+	lim := limiterimpl.NewTokenBucketLimiter(cfg, nil)
+	counter, ok := lim.(appratelimit.KeyCounter)
+	if !ok {
+		t.Fatalf("expected limiter to implement KeyCounter")
+	}
+
+	for i, key := range []string{"a", "b", "c"} {
+		lim.Allow(ctx, key)
+		if got := counter.Len(); got != i+1 {
+			t.Fatalf("expected Len %d after %q, got %d", i+1, key, got)
+		}
+	}
+	lim.Allow(ctx, "a")
+	if got := counter.Len(); got != 3 {
+		t.Fatalf("expected a known key not to grow Len, got %d", got)
+	}
+
+	// Once the keys have been idle past IdleTimeout, adding a new key evicts them.
+	time.Sleep(2 * cfg.IdleTimeout)
+	lim.Allow(ctx, "d")
+	if got := counter.Len(); got != 1 {
+		t.Fatalf("expected idle keys to be evicted, got Len %d", got)
+	}
+}
+
+func TestTokenBucketLimiter_ReserveHonorsContext(t *testing.T) {
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1
+	cfg.Period = time.Minute
+	cfg.Burst = 1
+
+	lim := limiterimpl.NewTokenBucketLimiter(cfg, nil)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if d := lim.Reserve(cancelled, "k"); d >= 0 {
+		t.Fatalf("expected negative reserve for a cancelled context, got %v", d)
+	}
+	if d := lim.Peek(context.Background(), "k"); d != 0 {
+		t.Fatalf("expected the cancelled reserve to leave the token, got wait %v", d)
+	}
+
+	if !lim.Allow(context.Background(), "k") {
+		t.Fatalf("expected first allow")
+	}
+
+	// A wait abandoned part way through returns promptly and gives its
+	// reservation back, so the next caller waits for one token, not two.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := lim.Wait(ctx, "k"); err == nil {
+		t.Fatalf("expected wait to fail when the context is done")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected wait to return promptly, took %v", elapsed)
+	}
+	if d := lim.Peek(context.Background(), "k"); d > time.Minute {
+		t.Fatalf("expected the abandoned reservation to be returned, got wait %v", d)
+	}
+}
+
+func TestTokenBucketLimiter_ReserveCancelable(t *testing.T) {
+	cfg := appratelimit.DefaultConfig()
+	cfg.Rate = 1
+	cfg.Period = time.Minute
+	cfg.Burst = 2
+	ctx := context.Background()
+
+	lim := limiterimpl.NewTokenBucketLimiter(cfg, nil)
+	reserver, ok := lim.(appratelimit.Reserver)
+	if !ok {
+		t.Fatalf("expected limiter to implement Reserver")
+	}
+
+	if !lim.Allow(ctx, "k") {
+		t.Fatalf("expected first allow")
+	}
+	reservation := reserver.ReserveCancelable(ctx, "k", 2)
+	if reservation.Delay <= 0 {
+		t.Fatalf("expected to wait for the second token, got delay %v", reservation.Delay)
+	}
+
+	// Cancelling gives the tokens back once, however often it is called.
+	reservation.Cancel()
+	reservation.Cancel()
+	stats, _ := lim.Stats(ctx, "k")
+	if stats.Tokens < 0.9 || stats.Tokens > 1.1 {
+		t.Fatalf("expected the one remaining token after cancelling, got %v", stats.Tokens)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if r := reserver.ReserveCancelable(cancelled, "k", 1); r.Delay >= 0 || r.Cancel == nil {
+		t.Fatalf("expected a negative delay and a no-op cancel for a cancelled context, got %+v", r)
+	}
+}
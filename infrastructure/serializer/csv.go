@@ -0,0 +1,73 @@
+package serializer
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// CSVContentType is the Content-Type written by RespondCSV.
+const CSVContentType = "text/csv; charset=utf-8"
+
+// CSVColumn is one column of a CSV export: its header and how to render a
+// row's cell.
+type CSVColumn[T any] struct {
+	Header string
+	Value  func(row T) string
+}
+
+// RespondCSV streams rows as a CSV download named filename: a header row
+// followed by one record per row, with columns in the given order. The
+// status is always 200; once the header row is written an error cannot be
+// turned into an error response, so it is returned for the caller to log.
+//
+// Cells that a spreadsheet would evaluate as a formula, those starting with
+// '=', '+', '-', '@', a tab or a carriage return, are prefixed with a single
+// quote so that exported data cannot inject formulas (CSV injection). This
+// applies to negative numbers too.
+func RespondCSV[T any](w http.ResponseWriter, r *http.Request, filename string, rows []T, columns []CSVColumn[T]) error {
+	_ = r
+	w.Header().Set("Content-Type", CSVContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.Header
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		for i, column := range columns {
+			record[i] = escapeCSVFormula(column.Value(row))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// plainNumber matches a signed decimal number such as "-5", "+3" or "-1.5e3",
+// which a spreadsheet reads as a value rather than a formula.
+var plainNumber = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// escapeCSVFormula prefixes value with a single quote if a spreadsheet would
+// otherwise treat it as a formula. A leading "+" or "-" is only escaped when
+// value is not a plain number, so negative amounts stay numeric.
+func escapeCSVFormula(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if (value[0] == '+' || value[0] == '-') && plainNumber.MatchString(value) {
+		return value
+	}
+	return "'" + value
+}
@@ -0,0 +1,64 @@
+package serializer_test
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/infrastructure/serializer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespondCSV(t *testing.T) {
+	items := []TestData{{ID: 1, Name: "Widget"}, {ID: 2, Name: "Gadget, large"}}
+	columns := []serializer.CSVColumn[TestData]{
+		{Header: "id", Value: func(d TestData) string { return strconv.Itoa(d.ID) }},
+		{Header: "name", Value: func(d TestData) string { return d.Name }},
+	}
+
+	w := httptest.NewRecorder()
+	err := serializer.RespondCSV(w, httptest.NewRequest(http.MethodGet, "/items.csv", nil), "items.csv", items, columns)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, serializer.CSVContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=items.csv`, w.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "name"},
+		{"1", "Widget"},
+		{"2", "Gadget, large"},
+	}, records)
+}
+
+func TestRespondCSV_EscapesFormulas(t *testing.T) {
+	names := []string{"=HYPERLINK(\"http://evil.example\")", "+1", "-2", "-1.5e3", "-2+3", "+A1", "-", "@SUM(A1)", "\tcmd", "\rcmd", "a=b"}
+	columns := []serializer.CSVColumn[string]{
+		{Header: "name", Value: func(name string) string { return name }},
+	}
+
+	w := httptest.NewRecorder()
+	err := serializer.RespondCSV(w, httptest.NewRequest(http.MethodGet, "/items.csv", nil), "items.csv", names, columns)
+	assert.NoError(t, err)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"name"},
+		{"'=HYPERLINK(\"http://evil.example\")"},
+		{"+1"},
+		{"-2"},
+		{"-1.5e3"},
+		{"'-2+3"},
+		{"'+A1"},
+		{"'-"},
+		{"'@SUM(A1)"},
+		{"'\tcmd"},
+		{"'\rcmd"},
+		{"a=b"},
+	}, records)
+}
@@ -1,3 +1,5 @@
 // Package serializer contains adapters for request/response serialization.
 // The JSON adapter implements both RequestDecoder and ResponseWriter for convenience.
+// NewGuardedWriter makes only the first terminal write of a request count.
+// RespondCSV streams list payloads as CSV downloads for reporting consumers.
 package serializer
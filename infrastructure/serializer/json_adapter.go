@@ -6,14 +6,85 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"reflect"
+	"strings"
 
 	apphttp "github.com/next-trace/scg-service-api/application/http"
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // JSONAdapter implements both RequestDecoder and ResponseWriter interfaces.
-type JSONAdapter struct{}
+type JSONAdapter struct {
+	opts JSONOptions
+}
+
+// JSONOptions configures a JSONAdapter.
+type JSONOptions struct {
+	// Envelope wraps success payloads as {"data": ..., "meta": ...}.
+	// Error responses are never wrapped.
+	Envelope bool
+
+	// SortKeys writes every JSON object with its keys in lexical order, at
+	// every depth. encoding/json already sorts plain maps, but not the output
+	// of json.Marshaler values such as json.RawMessage, and struct fields keep
+	// declaration order; with SortKeys the body depends only on its content,
+	// so bodies can be compared byte for byte or hashed.
+	SortKeys bool
+
+	// UnixTime lets Decode accept integer Unix seconds for time.Time fields,
+	// in addition to RFC 3339 strings.
+	UnixTime bool
+
+	// TimeLayouts lists extra time.Parse layouts Decode accepts for
+	// time.Time fields, tried in order after RFC 3339.
+	TimeLayouts []string
+
+	// HideInternalErrors replaces the message of 5xx error responses with a
+	// generic one derived from the status, e.g. "internal server error", so
+	// wrapped internal errors do not leak to clients. Enable it in production
+	// and leave it off in development to see the underlying message. The
+	// trace_id is still written and the span still records the real error.
+	// 4xx errors always show their message.
+	HideInternalErrors bool
+
+	// Log receives a warning when a handler writes a second response to a
+	// writer wrapped by NewGuardedWriter, e.g. Respond after Error. The
+	// second write is ignored either way; a nil Log drops the warning.
+	Log applogger.Logger
+}
+
+// Envelope is the body written by Respond in envelope mode.
+// Pass it to Respond (see WithMeta) to attach metadata to the payload.
+type Envelope struct {
+	Data interface{} `json:"data"`
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+// WithMeta pairs a payload with metadata such as pagination details.
+// In envelope mode the meta is written next to the data; otherwise only data is written.
+func WithMeta(data, meta interface{}) Envelope {
+	return Envelope{Data: data, Meta: meta}
+}
+
+// EncodeError reports that a response body could not be encoded as JSON.
+// Respond renders it as a 500 error response instead of the payload.
+type EncodeError struct {
+	Err error
+}
+
+// Error returns the error message.
+func (e *EncodeError) Error() string {
+	return "failed to encode response: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying encoding error.
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
 
 // Ensure JSONAdapter implements the apphttp.RequestDecoder interface
 var _ apphttp.RequestDecoder = (*JSONAdapter)(nil)
@@ -26,63 +97,252 @@ func NewJSONAdapter() *JSONAdapter {
 	return &JSONAdapter{}
 }
 
+// NewJSONAdapterWithOptions creates a new adapter for JSON serialization with custom options.
+func NewJSONAdapterWithOptions(opts JSONOptions) *JSONAdapter {
+	return &JSONAdapter{opts: opts}
+}
+
+// Decode decodes the JSON request body into v. With UnixTime or TimeLayouts
+// set, time.Time fields (at any depth) also accept those formats.
 func (a *JSONAdapter) Decode(r *http.Request, v interface{}) error {
-	return json.NewDecoder(r.Body).Decode(v)
+	if !a.opts.UnixTime && len(a.opts.TimeLayouts) == 0 {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(a.normalizeTimes(doc, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
 }
 
-func (a *JSONAdapter) Respond(w http.ResponseWriter, _ *http.Request, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	if data == nil {
-		w.WriteHeader(statusCode)
+// Respond writes data as the JSON response body with the given status code.
+// If data cannot be encoded nothing is written for it; a 500 error response
+// carrying an EncodeError is sent instead.
+func (a *JSONAdapter) Respond(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) {
+	if a.ignoreRewrite(w, r, statusCode) {
+		return
+	}
+	if err := a.write(w, statusCode, a.wrap(data)); err != nil {
+		a.Error(w, r, &EncodeError{Err: err})
+	}
+}
+
+// NoContent writes only the status code: no Content-Type and no body.
+func (a *JSONAdapter) NoContent(w http.ResponseWriter, r *http.Request, statusCode int) {
+	if a.ignoreRewrite(w, r, statusCode) {
 		return
 	}
+	w.WriteHeader(statusCode)
+}
+
+// ignoreRewrite reports whether a guarded writer already carries a response,
+// logging a warning so the handler's double write can be found and fixed.
+func (a *JSONAdapter) ignoreRewrite(w http.ResponseWriter, r *http.Request, statusCode int) bool {
+	if !alreadyWritten(w) {
+		return false
+	}
+	applogger.OrNop(a.opts.Log).WarnKV(r.Context(), "response already written; ignoring second write", map[string]interface{}{
+		"method": r.Method,
+		"path":   r.URL.Path,
+		"status": statusCode,
+	})
+	return true
+}
+
+// wrap applies the envelope to a success payload when envelope mode is enabled.
+func (a *JSONAdapter) wrap(data interface{}) interface{} {
+	env, isEnvelope := data.(Envelope)
+	if !a.opts.Envelope {
+		if isEnvelope {
+			return env.Data
+		}
+		return data
+	}
+	if data == nil || isEnvelope {
+		return data
+	}
+	return Envelope{Data: data}
+}
+
+// write encodes data as the JSON response body. If encoding fails it returns
+// the error without writing anything, so the caller can still send an error response.
+func (a *JSONAdapter) write(w http.ResponseWriter, statusCode int, data interface{}) error {
 	// Encode first into a buffer to avoid sending partial/incorrect responses
 	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
-	if err := enc.Encode(data); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-		return
+	if data != nil {
+		if err := json.NewEncoder(&buf).Encode(data); err != nil {
+			return err
+		}
+		if a.opts.SortKeys {
+			if err := sortKeys(&buf); err != nil {
+				return err
+			}
+		}
 	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(statusCode)
-	_, _ = w.Write(buf.Bytes())
+	if buf.Len() > 0 {
+		_, _ = w.Write(buf.Bytes())
+	}
+	return nil
+}
+
+// sortKeys re-encodes the JSON document in buf with object keys sorted.
+// Decoding into interface{} turns every object into a map, which
+// encoding/json writes in key order; UseNumber keeps numbers exact.
+func sortKeys(buf *bytes.Buffer) error {
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	buf.Reset()
+	return json.NewEncoder(buf).Encode(v)
 }
 
 // Error writes a standardized error response with appropriate status code.
 // It maps different error types to appropriate HTTP status codes.
 func (a *JSONAdapter) Error(w http.ResponseWriter, r *http.Request, err error) {
-	type errorResponse struct {
-		Error   string `json:"error"`
-		TraceID string `json:"trace_id,omitempty"`
-		Code    string `json:"code,omitempty"`
-	}
-
-	// Extract trace ID if available
-	span := trace.SpanFromContext(r.Context())
-	traceID := ""
-	if span.SpanContext().IsValid() {
-		traceID = span.SpanContext().TraceID().String()
-	}
+	statusCode, errorCode := mapError(err)
+	a.writeError(w, r, statusCode, errorCode, err)
+}
 
-	// Default status code and error code
-	statusCode := http.StatusInternalServerError
-	errorCode := "internal_error"
+// ErrorWithStatus writes a standardized error response with the given status code.
+// The error code in the body is derived from the status rather than from err.
+func (a *JSONAdapter) ErrorWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	a.writeError(w, r, statusCode, errorCodeForStatus(statusCode), err)
+}
 
-	// Map error types to appropriate status codes
-	// This can be extended with custom error types
+// mapError maps an error to an HTTP status code and a machine-readable error code.
+// This can be extended with custom error types.
+func mapError(err error) (int, string) {
+	var encodeErr *EncodeError
 	switch {
+	case errors.As(err, &encodeErr):
+		return http.StatusInternalServerError, "encode_failed"
+	case errors.Is(err, appvalidation.ErrValidationFailed):
+		return http.StatusBadRequest, "validation_failed"
 	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
-		statusCode = http.StatusGatewayTimeout
-		errorCode = "timeout"
+		return http.StatusGatewayTimeout, "timeout"
 	case errors.Is(err, http.ErrBodyNotAllowed), errors.Is(err, http.ErrMissingFile),
 		errors.Is(err, http.ErrNotMultipart), errors.Is(err, http.ErrNoCookie):
-		statusCode = http.StatusBadRequest
-		errorCode = "invalid_request"
+		return http.StatusBadRequest, "invalid_request"
 	case errors.Is(err, http.ErrHandlerTimeout):
-		statusCode = http.StatusServiceUnavailable
-		errorCode = "service_timeout"
+		return http.StatusServiceUnavailable, "service_timeout"
 	case errors.Is(err, http.ErrAbortHandler):
-		statusCode = http.StatusInternalServerError
-		errorCode = "request_aborted"
+		return http.StatusInternalServerError, "request_aborted"
+	}
+
+	if statusCode, errorCode, ok := mapDomainError(err); ok {
+		return statusCode, errorCode
+	}
+
+	// Default status code and error code
+	return http.StatusInternalServerError, "internal_error"
+}
+
+// domainStatuses maps the domain error sentinels to HTTP statuses and error codes.
+var domainStatuses = []struct {
+	err        error
+	statusCode int
+	errorCode  string
+}{
+	{domainerrors.ErrNotFound, http.StatusNotFound, "not_found"},
+	{domainerrors.ErrAlreadyExists, http.StatusConflict, "already_exists"},
+	{domainerrors.ErrConflict, http.StatusConflict, "conflict"},
+	{domainerrors.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed"},
+	{domainerrors.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{domainerrors.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{domainerrors.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{domainerrors.ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests"},
+	{domainerrors.ErrTimeout, http.StatusGatewayTimeout, "timeout"},
+	{domainerrors.ErrUnavailable, http.StatusServiceUnavailable, "unavailable"},
+	{domainerrors.ErrInternal, http.StatusInternalServerError, "internal_error"},
+}
+
+// mapDomainError maps errors wrapping a domain error sentinel. The error code
+// of a DomainError takes precedence over the default code of its sentinel.
+func mapDomainError(err error) (int, string, bool) {
+	for _, m := range domainStatuses {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+		errorCode := m.errorCode
+		var domainErr *domainerrors.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code != "" {
+			errorCode = domainErr.Code
+		}
+		return m.statusCode, errorCode, true
+	}
+	return 0, "", false
+}
+
+// errorCodeForStatus derives an error code from the status text, joining
+// its words with underscores: 422 becomes "unprocessable_entity", 414
+// "request_uri_too_long" and 418 "i_m_a_teapot".
+func errorCodeForStatus(statusCode int) string {
+	words := strings.FieldsFunc(strings.ToLower(http.StatusText(statusCode)), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	if len(words) == 0 {
+		return "error"
+	}
+	return strings.Join(words, "_")
+}
+
+// errorDetails extracts structured details for the error envelope, if err carries any:
+// the field errors of a validation failure, or the Details of a DomainError.
+func errorDetails(err error) interface{} {
+	var validationErr *appvalidation.ResultError
+	if errors.As(err, &validationErr) && len(validationErr.Errors) > 0 {
+		return validationErr.Errors
+	}
+	var domainErr *domainerrors.DomainError
+	if errors.As(err, &domainErr) && len(domainErr.Details) > 0 {
+		return domainErr.Details
+	}
+	return nil
+}
+
+// errorFields returns the per-field errors of a validation failure with their
+// codes, so clients can localize messages, or nil if err carries none. They
+// complement the messages under details, which keep their shape.
+func errorFields(err error) interface{} {
+	var validationErr *appvalidation.ResultError
+	if errors.As(err, &validationErr) && len(validationErr.Fields) > 0 {
+		return validationErr.Fields
+	}
+	return nil
+}
+
+// writeError writes the error envelope and records the error on the request span.
+func (a *JSONAdapter) writeError(w http.ResponseWriter, r *http.Request, statusCode int, errorCode string, err error) {
+	if a.ignoreRewrite(w, r, statusCode) {
+		return
+	}
+	type errorResponse struct {
+		Error   string      `json:"error"`
+		TraceID string      `json:"trace_id,omitempty"`
+		Code    string      `json:"code,omitempty"`
+		Details interface{} `json:"details,omitempty"`
+		Fields  interface{} `json:"fields,omitempty"`
+	}
+
+	// Extract trace ID if available
+	span := trace.SpanFromContext(r.Context())
+	traceID := ""
+	if span.SpanContext().IsValid() {
+		traceID = span.SpanContext().TraceID().String()
 	}
 
 	// Create the error response
@@ -90,6 +350,13 @@ func (a *JSONAdapter) Error(w http.ResponseWriter, r *http.Request, err error) {
 		Error:   err.Error(),
 		TraceID: traceID,
 		Code:    errorCode,
+		Details: errorDetails(err),
+		Fields:  errorFields(err),
+	}
+	if a.opts.HideInternalErrors && statusCode >= http.StatusInternalServerError {
+		resp.Error = strings.ToLower(http.StatusText(statusCode))
+		resp.Details = nil
+		resp.Fields = nil
 	}
 
 	// Record the error in the span if available
@@ -98,5 +365,8 @@ func (a *JSONAdapter) Error(w http.ResponseWriter, r *http.Request, err error) {
 		span.SetStatus(codes.Error, err.Error())
 	}
 
-	a.Respond(w, r, statusCode, resp)
+	if err := a.write(w, statusCode, resp); err != nil {
+		// Only unencodable details can get here; fall back to a plain-text response
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
 }
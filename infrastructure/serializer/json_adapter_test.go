@@ -1,16 +1,28 @@
 package serializer_test
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
-
+	"time"
+
+	apphttp "github.com/next-trace/scg-service-api/application/http"
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/next-trace/scg-service-api/domain/service"
+	infraLogger "github.com/next-trace/scg-service-api/infrastructure/logger"
 	"github.com/next-trace/scg-service-api/infrastructure/serializer"
+	"github.com/next-trace/scg-service-api/testsupport"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type TestData struct {
@@ -120,6 +132,36 @@ func TestJSONAdapter_Respond(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Empty(t, string(body))
 	})
+
+	t.Run("Respond with unencodable data", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+
+		// Channels cannot be marshaled to JSON
+		adapter.Respond(w, req, http.StatusOK, map[string]interface{}{"id": 1, "updates": make(chan int)})
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "encode_failed", body["code"])
+		assert.NotContains(t, body, "id", "partial payload must not be written")
+	})
+}
+
+func TestJSONAdapter_NoContent(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusNotModified} {
+		w := httptest.NewRecorder()
+		serializer.NewJSONAdapter().NoContent(w, httptest.NewRequest(http.MethodGet, "/test", nil), status)
+
+		assert.Equal(t, status, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Body.String())
+	}
 }
 
 func TestJSONAdapter_Error(t *testing.T) {
@@ -160,3 +202,385 @@ func TestJSONAdapter_Error(t *testing.T) {
 		// Note: TraceID will be empty in tests unless we mock the trace context
 	})
 }
+
+func TestJSONAdapter_ErrorWithStatus(t *testing.T) {
+	adapter := serializer.NewJSONAdapter()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/items", nil)
+
+	adapter.ErrorWithStatus(w, req, http.StatusUnprocessableEntity, errors.New("name is reserved"))
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	var errorResp struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&errorResp))
+	assert.Equal(t, "name is reserved", errorResp.Error)
+	assert.Equal(t, "unprocessable_entity", errorResp.Code)
+}
+
+func TestJSONAdapter_ErrorCodeForStatusText(t *testing.T) {
+	adapter := serializer.NewJSONAdapter()
+	tests := map[int]string{
+		http.StatusRequestURITooLong:           "request_uri_too_long",
+		http.StatusTeapot:                      "i_m_a_teapot",
+		http.StatusNonAuthoritativeInfo:        "non_authoritative_information",
+		http.StatusHTTPVersionNotSupported:     "http_version_not_supported",
+		http.StatusRequestHeaderFieldsTooLarge: "request_header_fields_too_large",
+		599:                                    "error",
+	}
+	for status, want := range tests {
+		w := httptest.NewRecorder()
+		adapter.ErrorWithStatus(w, httptest.NewRequest(http.MethodGet, "/", nil), status, errors.New("failed"))
+
+		var errorResp struct {
+			Code string `json:"code"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&errorResp))
+		assert.Equal(t, want, errorResp.Code, "status %d", status)
+	}
+}
+
+func TestJSONAdapter_SecondWriteIgnored(t *testing.T) {
+	log := testsupport.NewLogger()
+	adapter := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{Log: log})
+
+	rec := httptest.NewRecorder()
+	w := serializer.NewGuardedWriter(rec)
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+
+	adapter.Error(w, req, domainerrors.ErrNotFound)
+	adapter.Respond(w, req, http.StatusOK, map[string]string{"id": "1"})
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var errorResp struct {
+		Code string `json:"code"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errorResp), "body must hold only the error response")
+	assert.Equal(t, "not_found", errorResp.Code)
+
+	entry := log.AssertLogged(t, "response already written; ignoring second write")
+	assert.Equal(t, "warn", entry.Level)
+	assert.Equal(t, http.StatusOK, entry.Fields["status"])
+
+	adapter.NoContent(w, req, http.StatusNoContent)
+	adapter.ErrorWithStatus(w, req, http.StatusConflict, errors.New("conflict"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Len(t, log.Entries(), 3)
+}
+
+// hijackRecorder is a ResponseRecorder that also supports hijacking.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+// outerWriter stands in for a middleware wrapper that exposes Unwrap.
+type outerWriter struct {
+	http.ResponseWriter
+}
+
+func (o outerWriter) Unwrap() http.ResponseWriter {
+	return o.ResponseWriter
+}
+
+func TestGuardedWriter_PassesThroughFlushAndHijack(t *testing.T) {
+	log := testsupport.NewLogger()
+	adapter := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{Log: log})
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	w := serializer.NewGuardedWriter(rec)
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+
+	rc := http.NewResponseController(outerWriter{w})
+	assert.NoError(t, rc.Flush())
+	assert.True(t, rec.Flushed)
+
+	_, _, err := rc.Hijack()
+	assert.NoError(t, err)
+	assert.True(t, rec.hijacked)
+
+	// The connection is gone, so the adapter must not try to respond on it.
+	adapter.Respond(w, req, http.StatusOK, map[string]string{"id": "1"})
+	assert.Empty(t, rec.Body.String())
+	log.AssertLogged(t, "response already written; ignoring second write")
+}
+
+func TestJSONAdapter_SecondWriteIgnoredThroughWrapper(t *testing.T) {
+	log := testsupport.NewLogger()
+	adapter := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{Log: log})
+
+	rec := httptest.NewRecorder()
+	w := outerWriter{serializer.NewGuardedWriter(rec)}
+	assert.Equal(t, w, serializer.NewGuardedWriter(w), "a wrapped guard must not be guarded again")
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+
+	adapter.Error(w, req, domainerrors.ErrNotFound)
+	adapter.Respond(w, req, http.StatusOK, map[string]string{"id": "1"})
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	log.AssertLogged(t, "response already written; ignoring second write")
+}
+
+func TestJSONAdapter_Envelope(t *testing.T) {
+	adapter := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{Envelope: true})
+
+	t.Run("Data only", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		adapter.Respond(w, httptest.NewRequest(http.MethodGet, "/test", nil), http.StatusOK, TestData{ID: 1, Name: "a"})
+
+		assert.JSONEq(t, `{"data":{"id":1,"name":"a"}}`, w.Body.String())
+	})
+
+	t.Run("Data with meta", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		body := serializer.WithMeta([]TestData{{ID: 1, Name: "a"}}, map[string]int{"total": 1})
+		adapter.Respond(w, httptest.NewRequest(http.MethodGet, "/test", nil), http.StatusOK, body)
+
+		assert.JSONEq(t, `{"data":[{"id":1,"name":"a"}],"meta":{"total":1}}`, w.Body.String())
+	})
+
+	t.Run("Errors are not wrapped", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		adapter.Error(w, httptest.NewRequest(http.MethodGet, "/test", nil), errors.New("boom"))
+
+		assert.JSONEq(t, `{"error":"boom","code":"internal_error"}`, w.Body.String())
+	})
+
+	t.Run("Unwrapped by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		body := serializer.WithMeta(TestData{ID: 1, Name: "a"}, map[string]int{"total": 1})
+		serializer.NewJSONAdapter().Respond(w, httptest.NewRequest(http.MethodGet, "/test", nil), http.StatusOK, body)
+
+		assert.JSONEq(t, `{"id":1,"name":"a"}`, w.Body.String())
+	})
+}
+
+func TestJSONAdapter_SortKeys(t *testing.T) {
+	adapter := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{SortKeys: true})
+	encode := func(data interface{}) string {
+		w := httptest.NewRecorder()
+		adapter.Respond(w, httptest.NewRequest(http.MethodGet, "/test", nil), http.StatusOK, data)
+		return w.Body.String()
+	}
+
+	first := encode(map[string]interface{}{
+		"zeta":  json.RawMessage(`{"b":2,"a":1}`),
+		"alpha": TestData{ID: 1, Name: "a"},
+		"big":   json.Number("12345678901234567890"),
+	})
+	second := encode(map[string]interface{}{
+		"big":   json.Number("12345678901234567890"),
+		"alpha": TestData{ID: 1, Name: "a"},
+		"zeta":  json.RawMessage(`{"a":1,"b":2}`),
+	})
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, `{"alpha":{"id":1,"name":"a"},"big":12345678901234567890,"zeta":{"a":1,"b":2}}`+"\n", first)
+}
+
+func TestJSONAdapter_DecodeTimeFormats(t *testing.T) {
+	type event struct {
+		At     time.Time            `json:"at"`
+		Seen   *time.Time           `json:"seen"`
+		Others []time.Time          `json:"others"`
+		ByName map[string]time.Time `json:"by_name"`
+	}
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	adapter := serializer.NewJSONAdapterWithOptions(serializer.JSONOptions{
+		UnixTime:    true,
+		TimeLayouts: []string{"2006-01-02 15:04:05"},
+	})
+	decode := func(body string) (event, error) {
+		var got event
+		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+		return got, adapter.Decode(req, &got)
+	}
+
+	t.Run("RFC3339 string", func(t *testing.T) {
+		got, err := decode(`{"at":"2024-01-02T03:04:05Z"}`)
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(got.At))
+	})
+
+	t.Run("Unix integer", func(t *testing.T) {
+		got, err := decode(`{"at":1704164645,"seen":1704164645,"others":[1704164645],"by_name":{"a":1704164645}}`)
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(got.At))
+		if assert.NotNil(t, got.Seen) {
+			assert.True(t, want.Equal(*got.Seen))
+		}
+		assert.Len(t, got.Others, 1)
+		assert.True(t, want.Equal(got.ByName["a"]))
+	})
+
+	t.Run("custom layout", func(t *testing.T) {
+		got, err := decode(`{"at":"2024-01-02 03:04:05"}`)
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(got.At))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := decode(`{"at":"yesterday"}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unix integer rejected by default", func(t *testing.T) {
+		var got event
+		req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(`{"at":1704164645}`))
+		assert.Error(t, serializer.NewJSONAdapter().Decode(req, &got))
+	})
+}
+
+func TestJSONAdapter_HideInternalErrors(t *testing.T) {
+	internal := fmt.Errorf("%w: db connection to 10.0.0.5 refused", domainerrors.ErrInternal)
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{1}})
+
+	render := func(opts serializer.JSONOptions, status int, err error) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		r = r.WithContext(trace.ContextWithSpanContext(r.Context(), spanCtx))
+		serializer.NewJSONAdapterWithOptions(opts).ErrorWithStatus(w, r, status, err)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	t.Run("production hides internal errors", func(t *testing.T) {
+		code, body := render(serializer.JSONOptions{HideInternalErrors: true}, http.StatusInternalServerError, internal)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, "internal server error", body["error"])
+		assert.Equal(t, traceID.String(), body["trace_id"])
+		assert.NotContains(t, fmt.Sprint(body), "10.0.0.5")
+	})
+
+	t.Run("development shows internal errors", func(t *testing.T) {
+		_, body := render(serializer.JSONOptions{}, http.StatusInternalServerError, internal)
+		assert.Equal(t, internal.Error(), body["error"])
+		assert.Equal(t, traceID.String(), body["trace_id"])
+	})
+
+	t.Run("client errors always show their message", func(t *testing.T) {
+		notFound := fmt.Errorf("%w: item 42", domainerrors.ErrNotFound)
+		code, body := render(serializer.JSONOptions{HideInternalErrors: true}, http.StatusNotFound, notFound)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, notFound.Error(), body["error"])
+	})
+}
+
+func TestJSONAdapter_DomainErrorDetailsInLogsAndResponses(t *testing.T) {
+	var logs bytes.Buffer
+	log := infraLogger.NewSlogAdapter(&logs, "info")
+	err := domainerrors.NewInvalidInput("name is too long").
+		WithDetail("field", "name").
+		WithDetail("max_length", 100)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/items", nil)
+	log.ErrorKV(r.Context(), err, "create item failed", nil)
+	serializer.NewJSONAdapter().ErrorWithStatus(w, r, http.StatusBadRequest, err)
+
+	var logged, body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &logged))
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	want := map[string]interface{}{"field": "name", "max_length": float64(100), "reason": "name is too long"}
+	assert.Equal(t, want, logged["details"])
+	assert.Equal(t, want, body["details"])
+	assert.Equal(t, "invalid_input", logged["code"])
+}
+
+func TestJSONAdapter_DomainErrorStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", domainerrors.NewNotFound("item", "42"), http.StatusNotFound, "not_found"},
+		{"conflict", domainerrors.NewConflict("item", "42", "stale version"), http.StatusConflict, "conflict"},
+		{"version mismatch", domainerrors.NewVersionMismatch("item", "42", "1", "2"), http.StatusPreconditionFailed, "version_mismatch"},
+		{"too many requests", domainerrors.NewTooManyRequests(time.Second), http.StatusTooManyRequests, "too_many_requests"},
+		{"wrapped sentinel", fmt.Errorf("save: %w", domainerrors.ErrConflict), http.StatusConflict, "conflict"},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			serializer.NewJSONAdapter().Error(w, httptest.NewRequest(http.MethodGet, "/items/42", nil), tt.err)
+
+			var body map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.code, body["code"])
+		})
+	}
+}
+
+func TestJSONAdapter_ValidationFieldCodes(t *testing.T) {
+	result := appvalidation.ValidationResult{
+		Errors: appvalidation.ValidationErrors{"name": {"name is required"}},
+		Fields: appvalidation.FieldErrors{
+			"name": {appvalidation.ValidationError{Field: "name", Tag: "required", Message: "name is required"}.FieldError()},
+			"qty":  {{Code: "min", Message: "qty must be at least 1", Param: "1"}},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	serializer.NewJSONAdapter().Error(w, httptest.NewRequest(http.MethodPost, "/items", nil), result.Err())
+
+	var body struct {
+		Details map[string][]string                   `json:"details"`
+		Fields  map[string][]appvalidation.FieldError `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []string{"name is required"}, body.Details["name"])
+	assert.Equal(t, []appvalidation.FieldError{{Code: "required", Message: "name is required"}}, body.Fields["name"])
+	assert.Equal(t, []appvalidation.FieldError{{Code: "min", Message: "qty must be at least 1", Param: "1"}}, body.Fields["qty"])
+}
+
+func TestJSONAdapter_IfMatchUpdate(t *testing.T) {
+	item, err := entity.NewItem("widget", "", nil)
+	if err != nil {
+		t.Fatalf("NewItem: %v", err)
+	}
+	svc := service.NewItemService(testsupport.NewItemRepository(item))
+	adapter := serializer.NewJSONAdapter()
+
+	// update renames the item, conditionally on the request's If-Match.
+	update := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		updated, err := svc.UpdateItemIfVersion(r.Context(), item.ID, apphttp.IfMatch(r), "renamed", "", nil, "")
+		if err != nil {
+			adapter.Error(w, r, err)
+			return
+		}
+		w.Header().Set("ETag", apphttp.ETag(updated.Version()))
+		adapter.Respond(w, r, http.StatusOK, map[string]string{"name": updated.Name})
+	})
+
+	stale := httptest.NewRequest(http.MethodPut, "/items/"+item.ID, nil)
+	stale.Header.Set("If-Match", apphttp.ETag("1"))
+	w := httptest.NewRecorder()
+	update.ServeHTTP(w, stale)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	current := httptest.NewRequest(http.MethodPut, "/items/"+item.ID, nil)
+	current.Header.Set("If-Match", apphttp.ETag("1")+", "+apphttp.ETag(item.Version()))
+	w = httptest.NewRecorder()
+	update.ServeHTTP(w, current)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, apphttp.ETag(item.Version()), w.Header().Get("ETag"))
+}
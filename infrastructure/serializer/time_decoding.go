@@ -0,0 +1,111 @@
+package serializer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// normalizeTimes rewrites, in a document decoded with UseNumber, every value
+// that will land in a time.Time field of t into the RFC 3339 form
+// encoding/json expects. Values in other formats are left for json to reject.
+func (a *JSONAdapter) normalizeTimes(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return a.normalizeTime(value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			for key, field := range v {
+				if ft, ok := jsonFieldType(t, key); ok {
+					v[key] = a.normalizeTimes(field, ft)
+				}
+			}
+		case reflect.Map:
+			for key, field := range v {
+				v[key] = a.normalizeTimes(field, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, elem := range v {
+				v[i] = a.normalizeTimes(elem, t.Elem())
+			}
+		}
+	}
+	return value
+}
+
+// normalizeTime converts a Unix timestamp or a string in one of the
+// configured layouts to RFC 3339.
+func (a *JSONAdapter) normalizeTime(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if !a.opts.UnixTime {
+			return value
+		}
+		if secs, err := v.Int64(); err == nil {
+			return time.Unix(secs, 0).UTC().Format(time.RFC3339Nano)
+		}
+	case string:
+		if _, err := time.Parse(time.RFC3339, v); err == nil {
+			return value
+		}
+		for _, layout := range a.opts.TimeLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return parsed.Format(time.RFC3339Nano)
+			}
+		}
+	}
+	return value
+}
+
+// jsonFieldType finds the type of the struct field that encoding/json would
+// decode key into: an exact tag or name match first, then a case-insensitive
+// one. Fields of untagged embedded structs are promoted.
+func jsonFieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	var fold reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if found, ok := jsonFieldType(ft, key); ok {
+					return found, true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return f.Type, true
+		}
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = f.Type
+		}
+	}
+	return fold, fold != nil
+}
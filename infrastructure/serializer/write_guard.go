@@ -0,0 +1,85 @@
+package serializer
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// guardedWriter records whether a response has been started, so the adapter
+// can ignore a second terminal write to the same request.
+type guardedWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+// NewGuardedWriter wraps w so that only the first of the JSONAdapter's
+// terminal writes (Respond, NoContent, Error, ErrorWithStatus) reaches the
+// client; later ones are logged and dropped instead of writing headers twice.
+// Wrapping a writer that already has a guard in its Unwrap chain returns it
+// unchanged.
+func NewGuardedWriter(w http.ResponseWriter) http.ResponseWriter {
+	if findGuard(w) != nil {
+		return w
+	}
+	return &guardedWriter{ResponseWriter: w}
+}
+
+// WriteHeader marks the response as written and forwards the status.
+func (g *guardedWriter) WriteHeader(statusCode int) {
+	g.written = true
+	g.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write marks the response as written and forwards the bytes.
+func (g *guardedWriter) Write(b []byte) (int, error) {
+	g.written = true
+	return g.ResponseWriter.Write(b)
+}
+
+// Flush implements the http.Flusher interface if the underlying response writer supports it.
+func (g *guardedWriter) Flush() {
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface if the underlying response writer supports it.
+// A hijacked connection counts as written, since the adapter can no longer respond on it.
+func (g *guardedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := g.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil {
+		g.written = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying response writer for http.ResponseController.
+func (g *guardedWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// findGuard follows w's Unwrap chain, as http.ResponseController does, and
+// returns the first guard it finds, or nil.
+func findGuard(w http.ResponseWriter) *guardedWriter {
+	for {
+		switch t := w.(type) {
+		case *guardedWriter:
+			return t
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// alreadyWritten reports whether w is guarded and its response has started.
+func alreadyWritten(w http.ResponseWriter) bool {
+	g := findGuard(w)
+	return g != nil && g.written
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	applogger "github.com/next-trace/scg-service-api/application/logger"
 	apptracing "github.com/next-trace/scg-service-api/application/tracing"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -17,6 +18,13 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// Default span limits applied when the corresponding Config field is zero.
+const (
+	DefaultAttributeCountLimit       = 128
+	DefaultAttributeValueLengthLimit = 4096
+	DefaultEventCountLimit           = 128
+)
+
 // Ensure otelAdapter implements the apptracing.Tracer interface.
 var _ apptracing.Tracer = (*otelAdapter)(nil)
 
@@ -26,18 +34,43 @@ var _ apptracing.Tracer = (*otelAdapter)(nil)
 type Option func(*options)
 
 type options struct {
-	exporter sdktrace.SpanExporter
-	res      *resource.Resource
-	sampler  sdktrace.Sampler
+	exporter        sdktrace.SpanExporter
+	exporterFactory ExporterFactory
+	res             *resource.Resource
+	sampler         sdktrace.Sampler
+	fallbackToNoop  bool
+	log             applogger.Logger
 }
 
+// ExporterFactory creates the span exporter for a configuration, e.g. an
+// OTLP exporter that dials cfg.ExporterEndpoint.
+type ExporterFactory func(cfg apptracing.Config) (sdktrace.SpanExporter, error)
+
 // WithExporter allows providing a custom SpanExporter (e.g., an in-memory test exporter).
 func WithExporter(exp sdktrace.SpanExporter) Option { return func(o *options) { o.exporter = exp } }
 
+// WithExporterFactory replaces the default stdout exporter with one created
+// by factory. It is ignored when WithExporter is also given.
+func WithExporterFactory(factory ExporterFactory) Option {
+	return func(o *options) { o.exporterFactory = factory }
+}
+
+// WithFallbackToNoop makes a failed exporter creation non-fatal: instead of
+// returning the error, NewOtelAdapterWithOptions logs a warning to log and
+// returns apptracing.Nop(), so an unreachable collector does not block
+// service startup.
+func WithFallbackToNoop(log applogger.Logger) Option {
+	return func(o *options) {
+		o.fallbackToNoop = true
+		o.log = applogger.OrNop(log)
+	}
+}
+
 // WithResource allows overriding the OpenTelemetry resource.
 func WithResource(r *resource.Resource) Option { return func(o *options) { o.res = r } }
 
 // WithSampler allows overriding the sampler (defaults to TraceIDRatioBased based on cfg.SamplingRate).
+// Spans from contexts marked with apptracing.ContextWithForceSample are sampled either way.
 func WithSampler(s sdktrace.Sampler) Option { return func(o *options) { o.sampler = s } }
 
 // otelAdapter implements the apptracing.Tracer interface using OpenTelemetry.
@@ -71,8 +104,15 @@ func NewOtelAdapterWithOptions(cfg apptracing.Config, opts ...Option) (apptracin
 	}
 
 	// Exporter: use injected exporter when provided, otherwise create default
-	exporter, err := createExporter(cfg, o.exporter)
+	exporter, err := createExporter(cfg, o.exporter, o.exporterFactory)
 	if err != nil {
+		if o.fallbackToNoop {
+			o.log.WarnKV(context.Background(), "tracing exporter unavailable, falling back to no-op tracer", map[string]interface{}{
+				"service": cfg.ServiceName,
+				"error":   err.Error(),
+			})
+			return apptracing.Nop(), nil
+		}
 		return nil, fmt.Errorf("failed to create exporter: %w", err)
 	}
 
@@ -94,9 +134,10 @@ func NewOtelAdapterWithOptions(cfg apptracing.Config, opts ...Option) (apptracin
 
 	// Create tracer provider
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithBatcher(exporter, batcherOptions(cfg)...),
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sampler),
+		sdktrace.WithSampler(forceSampler{base: sampler}),
+		sdktrace.WithSpanLimits(configureSpanLimits(cfg)),
 	)
 
 	// Set global tracer provider and propagator
@@ -113,11 +154,15 @@ func NewOtelAdapterWithOptions(cfg apptracing.Config, opts ...Option) (apptracin
 }
 
 // createExporter creates a span exporter based on the provided configuration.
-// If exp is provided, it will be used directly; otherwise a default exporter is created.
-func createExporter(cfg apptracing.Config, exp sdktrace.SpanExporter) (sdktrace.SpanExporter, error) {
+// If exp is provided, it will be used directly; otherwise factory creates it,
+// falling back to a default stdout exporter when factory is nil.
+func createExporter(cfg apptracing.Config, exp sdktrace.SpanExporter, factory ExporterFactory) (sdktrace.SpanExporter, error) {
 	if exp != nil {
 		return exp, nil
 	}
+	if factory != nil {
+		return factory(cfg)
+	}
 	// Only default stdout exporter is supported by default;
 	output := cfg.Output
 	if output == nil {
@@ -130,14 +175,21 @@ func createExporter(cfg apptracing.Config, exp sdktrace.SpanExporter) (sdktrace.
 }
 
 // createResource creates a resource with service information.
+// Entries from cfg.ResourceAttributes are added alongside the service attributes;
+// the service attributes take precedence on key collisions.
 func createResource(cfg apptracing.Config) (*resource.Resource, error) {
+	attrs := convertToAttributes(cfg.ResourceAttributes)
+	attrs = append(attrs,
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", cfg.ServiceVersion),
+		attribute.String("environment", cfg.Environment),
+	)
+
 	return resource.Merge(
 		resource.Default(),
 		resource.NewWithAttributes(
 			"", // no schema URL to avoid version conflicts
-			attribute.String("service.name", cfg.ServiceName),
-			attribute.String("service.version", cfg.ServiceVersion),
-			attribute.String("environment", cfg.Environment),
+			attrs...,
 		),
 	)
 }
@@ -152,15 +204,86 @@ func configureSampler(samplingRate float64) sdktrace.Sampler {
 	return sdktrace.TraceIDRatioBased(samplingRate)
 }
 
+// forceSampler samples every span started from a context marked with
+// apptracing.ContextWithForceSample and defers to base otherwise.
+type forceSampler struct {
+	base sdktrace.Sampler
+}
+
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if p.ParentContext != nil && apptracing.ForceSampleFromContext(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s forceSampler) Description() string {
+	return "ForceSampler{" + s.base.Description() + "}"
+}
+
+// configureSpanLimits builds span limits from the configuration, falling back
+// to the package defaults for unset values.
+func configureSpanLimits(cfg apptracing.Config) sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits()
+	limits.AttributeCountLimit = valueOrDefault(cfg.AttributeCountLimit, DefaultAttributeCountLimit)
+	limits.AttributeValueLengthLimit = valueOrDefault(cfg.AttributeValueLengthLimit, DefaultAttributeValueLengthLimit)
+	limits.EventCountLimit = valueOrDefault(cfg.EventCountLimit, DefaultEventCountLimit)
+	return limits
+}
+
+// batcherOptions converts the configured batch settings into span processor options.
+func batcherOptions(cfg apptracing.Config) []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if cfg.BatchTimeout > 0 {
+		opts = append(opts, sdktrace.WithBatchTimeout(cfg.BatchTimeout))
+	}
+	if cfg.ExportTimeout > 0 {
+		opts = append(opts, sdktrace.WithExportTimeout(cfg.ExportTimeout))
+	}
+	if cfg.MaxQueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(cfg.MaxQueueSize))
+	}
+	if cfg.MaxExportBatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize))
+	}
+	return opts
+}
+
+// valueOrDefault returns v when positive, otherwise def.
+func valueOrDefault(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+// spanKinds maps the application span kinds to OpenTelemetry's.
+var spanKinds = map[apptracing.SpanKind]trace.SpanKind{
+	apptracing.SpanKindInternal: trace.SpanKindInternal,
+	apptracing.SpanKindServer:   trace.SpanKindServer,
+	apptracing.SpanKindClient:   trace.SpanKindClient,
+}
+
 // Start begins a new span and returns the updated context and a function to end the span.
 // The returned context contains the new span, and the function should be called
-// when the operation being traced is complete.
+// when the operation being traced is complete. The span's kind is taken from
+// apptracing.ContextWithSpanKind.
 func (o *otelAdapter) Start(ctx context.Context, spanName string) (context.Context, func()) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	ctx, span := o.tracer.Start(ctx, spanName)
+	var opts []trace.SpanStartOption
+	if kind := apptracing.SpanKindFromContext(ctx); kind != apptracing.SpanKindInternal {
+		opts = append(opts, trace.WithSpanKind(spanKinds[kind]))
+		// The kind describes this span only, not the spans started below it
+		ctx = apptracing.ContextWithSpanKind(ctx, apptracing.SpanKindInternal)
+	}
+
+	ctx, span := o.tracer.Start(ctx, spanName, opts...)
 	return ctx, func() {
 		if span != nil {
 			span.End()
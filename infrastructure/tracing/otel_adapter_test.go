@@ -2,14 +2,19 @@ package tracing_test
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	apptracing "github.com/next-trace/scg-service-api/application/tracing"
 	impl "github.com/next-trace/scg-service-api/infrastructure/tracing"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type fakeExporter struct{ exported int64 }
@@ -20,6 +25,26 @@ func (f *fakeExporter) ExportSpans(_ context.Context, _ []sdktrace.ReadOnlySpan)
 }
 func (f *fakeExporter) Shutdown(_ context.Context) error { return nil }
 
+// recordingExporter keeps exported spans, including after Shutdown.
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *recordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+func (r *recordingExporter) Shutdown(_ context.Context) error { return nil }
+
+func (r *recordingExporter) Spans() []sdktrace.ReadOnlySpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan(nil), r.spans...)
+}
+
 func TestOtelAdapter_Defaults_StartAddEventRecordErrorShutdown(t *testing.T) {
 	cfg := apptracing.Config{
 		ServiceName:    "svc",
@@ -67,3 +92,173 @@ func TestOtelAdapter_WithExporter_ExporterReceivesSpansOnShutdown(t *testing.T)
 		}
 	}
 }
+
+func TestOtelAdapter_ResourceAttributes(t *testing.T) {
+	exp := &recordingExporter{}
+	cfg := apptracing.Config{
+		ServiceName:  "svc",
+		SamplingRate: 1.0,
+		ResourceAttributes: map[string]string{
+			"deployment.region": "eu-west-1",
+			"k8s.pod.name":      "svc-abc123",
+		},
+	}
+	tr, err := impl.NewOtelAdapterWithOptions(cfg, impl.WithExporter(exp))
+	if err != nil {
+		t.Fatalf("new tracer: %v", err)
+	}
+	_, end := tr.Start(context.Background(), "op")
+	end()
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	spans := exp.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	res := spans[0].Resource()
+	for k, want := range map[string]string{
+		"deployment.region": "eu-west-1",
+		"k8s.pod.name":      "svc-abc123",
+		"service.name":      "svc",
+	} {
+		got, ok := res.Set().Value(attribute.Key(k))
+		if !ok || got.AsString() != want {
+			t.Fatalf("resource attribute %s = %q, want %q", k, got.AsString(), want)
+		}
+	}
+}
+
+func TestOtelAdapter_SpanLimits(t *testing.T) {
+	exp := &recordingExporter{}
+	cfg := apptracing.Config{
+		ServiceName:               "svc",
+		SamplingRate:              1.0,
+		AttributeCountLimit:       2,
+		AttributeValueLengthLimit: 4,
+		EventCountLimit:           1,
+	}
+	tr, err := impl.NewOtelAdapterWithOptions(cfg, impl.WithExporter(exp), impl.WithResource(resource.Empty()))
+	if err != nil {
+		t.Fatalf("new tracer: %v", err)
+	}
+	ctx, end := tr.Start(context.Background(), "op")
+	tr.SetAttributes(ctx, map[string]string{"a": "1"})
+	tr.SetAttributes(ctx, map[string]string{"b": "2"})
+	tr.SetAttributes(ctx, map[string]string{"c": "3"})
+	tr.SetAttributes(ctx, map[string]string{"a": "truncated"})
+	tr.AddEvent(ctx, "e1", nil)
+	tr.AddEvent(ctx, "e2", nil)
+	end()
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	spans := exp.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if got := len(span.Attributes()); got != 2 {
+		t.Fatalf("expected 2 attributes, got %d", got)
+	}
+	if span.DroppedAttributes() != 1 {
+		t.Fatalf("expected 1 dropped attribute, got %d", span.DroppedAttributes())
+	}
+	if got := span.Attributes()[0].Value.AsString(); got != "trun" {
+		t.Fatalf("expected value truncated to %q, got %q", "trun", got)
+	}
+	if len(span.Events()) != 1 || span.DroppedEvents() != 1 {
+		t.Fatalf("expected 1 event and 1 dropped, got %d and %d", len(span.Events()), span.DroppedEvents())
+	}
+}
+
+func TestOtelAdapter_BatcherSettings_ShutdownFlushes(t *testing.T) {
+	exp := &recordingExporter{}
+	cfg := apptracing.Config{
+		ServiceName:        "svc",
+		SamplingRate:       1.0,
+		BatchTimeout:       time.Hour, // never fires on its own during the test
+		ExportTimeout:      time.Second,
+		MaxQueueSize:       4096,
+		MaxExportBatchSize: 2,
+	}
+	tr, err := impl.NewOtelAdapterWithOptions(cfg, impl.WithExporter(exp), impl.WithResource(resource.Empty()))
+	if err != nil {
+		t.Fatalf("new tracer: %v", err)
+	}
+	for range 3 {
+		_, end := tr.Start(context.Background(), "op")
+		end()
+	}
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if got := len(exp.Spans()); got != 3 {
+		t.Fatalf("expected shutdown to flush 3 spans, got %d", got)
+	}
+}
+
+func TestOtelAdapter_FallbackToNoop(t *testing.T) {
+	cfg := apptracing.Config{ServiceName: "svc", ExporterType: "otlp", ExporterEndpoint: "collector:4317", SamplingRate: 1.0}
+	failing := impl.WithExporterFactory(func(apptracing.Config) (sdktrace.SpanExporter, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	if _, err := impl.NewOtelAdapterWithOptions(cfg, failing); err == nil {
+		t.Fatal("expected exporter error without fallback")
+	}
+
+	log := testsupport.NewLogger()
+	tr, err := impl.NewOtelAdapterWithOptions(cfg, failing, impl.WithFallbackToNoop(log))
+	if err != nil {
+		t.Fatalf("expected fallback tracer, got %v", err)
+	}
+	entry := log.AssertLogged(t, "tracing exporter unavailable, falling back to no-op tracer")
+	if entry.Level != "warn" || entry.Fields["error"] != "connection refused" {
+		t.Fatalf("unexpected log entry: %+v", entry)
+	}
+
+	ctx, end := tr.Start(context.Background(), "op")
+	if ctx == nil {
+		t.Fatal("Start returned a nil context")
+	}
+	tr.AddEvent(ctx, "evt", map[string]string{"k": "v"})
+	tr.SetAttributes(ctx, map[string]string{"a": "b"})
+	tr.RecordError(ctx, errors.New("boom"))
+	end()
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestOtelAdapter_SpanKindFromContext(t *testing.T) {
+	exp := &recordingExporter{}
+	cfg := apptracing.Config{ServiceName: "svc", SamplingRate: 1.0}
+	tr, err := impl.NewOtelAdapterWithOptions(cfg, impl.WithExporter(exp), impl.WithResource(resource.Empty()))
+	if err != nil {
+		t.Fatalf("new tracer: %v", err)
+	}
+
+	ctx, endServer := tr.Start(apptracing.ContextWithSpanKind(context.Background(), apptracing.SpanKindServer), "server")
+	_, endChild := tr.Start(ctx, "child")
+	endChild()
+	endServer()
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	kinds := map[string]trace.SpanKind{}
+	exp.mu.Lock()
+	for _, s := range exp.spans {
+		kinds[s.Name()] = s.SpanKind()
+	}
+	exp.mu.Unlock()
+	if kinds["server"] != trace.SpanKindServer {
+		t.Fatalf("expected server span kind, got %v", kinds["server"])
+	}
+	if kinds["child"] != trace.SpanKindInternal {
+		t.Fatalf("expected child span to be internal, got %v", kinds["child"])
+	}
+}
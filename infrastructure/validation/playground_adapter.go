@@ -1,7 +1,11 @@
 // Package validation provides validation functionality.
 //
-// Note: This package requires the following dependencies:
-// - github.com/go-playground/validator/v10
+// By default the package uses a built-in engine with no external
+// dependencies, which applies only aliases and custom rules. Building with
+// the playground tag (go build -tags playground) switches the engine to
+// github.com/go-playground/validator/v10, which must then be required in
+// go.mod and validates every struct tag. The public API is identical in both
+// configurations.
 //
 // See docs/dependencies.md for more information.
 package validation
@@ -18,57 +22,31 @@ import (
 // Ensure playgroundAdapter implements the appvalidation.Validator interface.
 var _ appvalidation.Validator = (*playgroundAdapter)(nil)
 
-// Type aliases for validator types
-// These are placeholders for the actual types from the validator package
-// They will be replaced with the actual types when the dependencies are added
-type (
-	// validate represents a validator
-	validate struct {
-		tagName      string
-		customRules  map[string]func(fl interface{}) bool
-		translations map[string]string
-		tagNameFunc  func(field reflect.StructField) string
-	}
-
-	// fieldError represents a validation error for a field (unused in mock)
-)
-
-// Mock methods for the validate type
-func newValidator() *validate {
-	return &validate{
-		tagName:      "validate",
-		customRules:  make(map[string]func(fl interface{}) bool),
-		translations: make(map[string]string),
-		tagNameFunc:  nil,
-	}
+// Engine is the part of the go-playground *validator.Validate API the adapter
+// builds on. It is not satisfied by *validator.Validate itself: its
+// RegisterValidation takes a func of the field value rather than a
+// validator.Func, so that callers do not depend on the library's types. With
+// the playground build tag the engine wraps a *validator.Validate.
+type Engine interface {
+	Struct(s interface{}) error
+	StructPartial(s interface{}, fields ...string) error
+	Var(field interface{}, tag string) error
+	RegisterValidation(tag string, fn func(fl interface{}) bool) error
+	RegisterAlias(alias, tags string)
+	RegisterTagNameFunc(fn func(field reflect.StructField) string)
 }
 
-func (v *validate) Struct(s interface{}) error {
-	// In a real implementation, this would validate the struct
-	// For now, we'll just return nil
-	return nil
+// EngineProvider is implemented by the validator returned by
+// NewPlaygroundAdapter. It is an escape hatch for features the
+// appvalidation.Validator port does not wrap, such as aliases and custom
+// types; code using it is coupled to go-playground/validator, so prefer the
+// port wherever it suffices.
+type EngineProvider interface {
+	Engine() Engine
 }
 
-func (v *validate) StructPartial(s interface{}, fields ...string) error {
-	// In a real implementation, this would validate the struct partially
-	// For now, we'll just return nil
-	return nil
-}
-
-func (v *validate) Var(field interface{}, tag string) error {
-	// In a real implementation, this would validate the variable
-	// For now, we'll just return nil
-	return nil
-}
-
-func (v *validate) RegisterValidation(tag string, fn func(fl interface{}) bool) error {
-	v.customRules[tag] = fn
-	return nil
-}
-
-func (v *validate) RegisterTagNameFunc(fn func(field reflect.StructField) string) {
-	v.tagNameFunc = fn
-}
+// Ensure playgroundAdapter exposes its engine.
+var _ EngineProvider = (*playgroundAdapter)(nil)
 
 // playgroundAdapter implements the validation.Validator interface using the go-playground/validator package.
 type playgroundAdapter struct {
@@ -79,18 +57,12 @@ type playgroundAdapter struct {
 
 // NewPlaygroundAdapter creates a new validator adapter using the go-playground/validator package.
 func NewPlaygroundAdapter(config appvalidation.Config, log applogger.Logger) appvalidation.Validator {
-	validator := newValidator()
-	validator.tagName = config.TagName
+	log = applogger.OrNop(log)
+	validator := newValidator(config.TagName)
 
 	// Register custom rules
 	for name, rule := range config.CustomRules {
-		// Convert the rule to the format expected by the validator
-		validatorRule := func(fl interface{}) bool {
-			// In a real implementation, this would convert the validator.FieldLevel to the value
-			// For now, we'll just pass the value directly
-			return rule(context.Background(), fl)
-		}
-		if err := validator.RegisterValidation(name, validatorRule); err != nil {
+		if err := validator.registerCustomRule(name, rule); err != nil {
 			log.WarnKV(context.Background(), "failed to register validation rule", map[string]interface{}{
 				"rule":  name,
 				"error": err.Error(),
@@ -118,18 +90,26 @@ func (p *playgroundAdapter) Validate(ctx context.Context, value interface{}) app
 
 	// Convert validation errors to our format
 	validationErrors := make(appvalidation.ValidationErrors)
+	fieldErrors := make(appvalidation.FieldErrors)
 	for _, err := range p.extractValidationErrors(err) {
 		field := err.Field
-		message := p.formatErrorMessage(err)
-		validationErrors[field] = append(validationErrors[field], message)
+		err.Message = p.formatErrorMessage(err)
+		validationErrors[field] = append(validationErrors[field], err.Message)
+		fieldErrors[field] = append(fieldErrors[field], err.FieldError())
 	}
 
 	return appvalidation.ValidationResult{
 		Valid:  false,
 		Errors: validationErrors,
+		Fields: fieldErrors,
 	}
 }
 
+// Engine returns the underlying validator, see EngineProvider.
+func (p *playgroundAdapter) Engine() Engine {
+	return p.validator
+}
+
 // ValidateField validates a specific field of the given value.
 func (p *playgroundAdapter) ValidateField(ctx context.Context, value interface{}, field string) appvalidation.ValidationResult {
 	if !p.config.Enabled {
@@ -143,16 +123,19 @@ func (p *playgroundAdapter) ValidateField(ctx context.Context, value interface{}
 
 	// Convert validation errors to our format
 	validationErrors := make(appvalidation.ValidationErrors)
+	fieldErrors := make(appvalidation.FieldErrors)
 	for _, err := range p.extractValidationErrors(err) {
 		if err.Field == field {
-			message := p.formatErrorMessage(err)
-			validationErrors[field] = append(validationErrors[field], message)
+			err.Message = p.formatErrorMessage(err)
+			validationErrors[field] = append(validationErrors[field], err.Message)
+			fieldErrors[field] = append(fieldErrors[field], err.FieldError())
 		}
 	}
 
 	return appvalidation.ValidationResult{
 		Valid:  len(validationErrors) == 0,
 		Errors: validationErrors,
+		Fields: fieldErrors,
 	}
 }
 
@@ -163,12 +146,14 @@ func (p *playgroundAdapter) ValidateMap(ctx context.Context, values map[string]i
 	}
 
 	validationErrors := make(appvalidation.ValidationErrors)
+	fieldErrors := make(appvalidation.FieldErrors)
 	for field, value := range values {
 		err := p.validator.Var(value, p.getTagForField(field))
 		if err != nil {
 			for _, err := range p.extractValidationErrors(err) {
-				message := p.formatErrorMessage(err)
-				validationErrors[field] = append(validationErrors[field], message)
+				err.Message = p.formatErrorMessage(err)
+				validationErrors[field] = append(validationErrors[field], err.Message)
+				fieldErrors[field] = append(fieldErrors[field], err.FieldError())
 			}
 		}
 	}
@@ -176,6 +161,7 @@ func (p *playgroundAdapter) ValidateMap(ctx context.Context, values map[string]i
 	return appvalidation.ValidationResult{
 		Valid:  len(validationErrors) == 0,
 		Errors: validationErrors,
+		Fields: fieldErrors,
 	}
 }
 
@@ -185,14 +171,7 @@ func (p *playgroundAdapter) RegisterCustomRule(name string, rule appvalidation.C
 		return nil
 	}
 
-	// Convert the rule to the format expected by the validator
-	validatorRule := func(fl interface{}) bool {
-		// In a real implementation, this would convert the validator.FieldLevel to the value
-		// For now, we'll just pass the value directly
-		return rule(context.Background(), fl)
-	}
-
-	return p.validator.RegisterValidation(name, validatorRule)
+	return p.validator.registerCustomRule(name, rule)
 }
 
 // RegisterTagNameFunc registers a function to get the field name from a struct tag.
@@ -210,31 +189,17 @@ func (p *playgroundAdapter) extractValidationErrors(err error) []appvalidation.V
 		return nil
 	}
 
-	var validationErrors []appvalidation.ValidationError
-
-	// In a real implementation, this would use validator.ValidationErrors
-	// For now, we'll just create a mock error
-	validationErrors = append(validationErrors, appvalidation.ValidationError{
-		Field:   "mock_field",
-		Tag:     "mock_tag",
-		Value:   "mock_value",
-		Param:   "mock_param",
-		Message: err.Error(),
-	})
-
-	return validationErrors
+	return p.validator.validationErrors(err)
 }
 
-// formatErrorMessage formats a validation error message.
+// formatErrorMessage formats a validation error message. Messages are not
+// translated.
 func (p *playgroundAdapter) formatErrorMessage(err appvalidation.ValidationError) string {
-	// In a real implementation, this would use translations
-	// For now, we'll just return a simple message
 	return fmt.Sprintf("validation failed on field %s, tag %s", err.Field, err.Tag)
 }
 
-// getTagForField returns the validation tag for the given field.
+// getTagForField returns the validation tag for the given field. ValidateMap
+// has no struct to read tags from, so every value is required.
 func (p *playgroundAdapter) getTagForField(field string) string {
-	// In a real implementation, this would look up the tag from the struct
-	// For now, we'll just return a default tag
 	return "required"
 }
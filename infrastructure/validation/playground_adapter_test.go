@@ -24,10 +24,28 @@ func TestPlaygroundAdapter_BasicValidation(t *testing.T) {
 	if !ok.Valid {
 		t.Fatalf("expected valid sample, got errors: %+v", ok.Errors)
 	}
+}
+
+func TestPlaygroundAdapter_EngineAlias(t *testing.T) {
+	v := validatorimpl.NewPlaygroundAdapter(appvalidation.DefaultConfig(), nil)
+	provider, ok := v.(validatorimpl.EngineProvider)
+	if !ok {
+		t.Fatalf("expected adapter to implement EngineProvider")
+	}
+	engine := provider.Engine()
 
-	// In this mock playground adapter, validation always succeeds; ensure it does not error
-	bad := v.Validate(context.Background(), sample{Name: "a"})
-	if !bad.Valid {
-		t.Fatalf("expected mock validator to return valid result even for short name")
+	if err := engine.RegisterValidation("even", func(fl interface{}) bool {
+		n, ok := fl.(int)
+		return ok && n%2 == 0
+	}); err != nil {
+		t.Fatalf("register validation: %v", err)
+	}
+	engine.RegisterAlias("evenid", "even")
+
+	if err := engine.Var(4, "evenid"); err != nil {
+		t.Fatalf("expected 4 to pass the alias, got %v", err)
+	}
+	if err := engine.Var(3, "evenid"); err == nil {
+		t.Fatalf("expected 3 to fail the alias")
 	}
 }
@@ -0,0 +1,118 @@
+//go:build !playground
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+)
+
+// validate is the built-in engine used when the playground build tag is not
+// set. It does not interpret struct tags: Struct and StructPartial accept
+// every value, and Var applies only aliases and custom rules.
+type validate struct {
+	tagName      string
+	customRules  map[string]func(fl interface{}) bool
+	aliases      map[string]string
+	translations map[string]string
+	tagNameFunc  func(field reflect.StructField) string
+}
+
+// newValidator creates an engine reading rules from the given struct tag.
+func newValidator(tagName string) *validate {
+	return &validate{
+		tagName:      tagName,
+		customRules:  make(map[string]func(fl interface{}) bool),
+		aliases:      make(map[string]string),
+		translations: make(map[string]string),
+		tagNameFunc:  nil,
+	}
+}
+
+// Struct accepts every value; struct tags are not interpreted.
+func (v *validate) Struct(s interface{}) error {
+	return nil
+}
+
+// StructPartial accepts every value; struct tags are not interpreted.
+func (v *validate) StructPartial(s interface{}, fields ...string) error {
+	return nil
+}
+
+// Var applies the aliases and custom rules in tag; other tags are ignored.
+// An alias that refers back to itself, directly or through other aliases,
+// is an error.
+func (v *validate) Var(field interface{}, tag string) error {
+	tags, err := v.expandTags(tag, nil)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if rule, ok := v.customRules[t]; ok && !rule(field) {
+			return fmt.Errorf("validation failed on tag %s", t)
+		}
+	}
+	return nil
+}
+
+// expandTags replaces the aliases in tag with the tags they stand for.
+// expanding holds the aliases being expanded by the callers, to detect cycles.
+func (v *validate) expandTags(tag string, expanding []string) ([]string, error) {
+	var tags []string
+	for _, t := range strings.Split(tag, ",") {
+		if alias, ok := v.aliases[t]; ok {
+			if slices.Contains(expanding, t) {
+				return nil, fmt.Errorf("alias cycle: %s -> %s", strings.Join(expanding, " -> "), t)
+			}
+			expanded, err := v.expandTags(alias, append(expanding, t))
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, expanded...)
+			continue
+		}
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
+
+func (v *validate) RegisterAlias(alias, tags string) {
+	v.aliases[alias] = tags
+}
+
+func (v *validate) RegisterValidation(tag string, fn func(fl interface{}) bool) error {
+	v.customRules[tag] = fn
+	return nil
+}
+
+func (v *validate) RegisterTagNameFunc(fn func(field reflect.StructField) string) {
+	v.tagNameFunc = fn
+}
+
+// registerCustomRule registers rule under name. The built-in engine passes
+// the value directly and no parameters.
+func (v *validate) registerCustomRule(name string, rule appvalidation.CustomRule) error {
+	return v.RegisterValidation(name, func(fl interface{}) bool {
+		return rule(context.Background(), fl)
+	})
+}
+
+// validationErrors converts an error from the engine into per-field errors.
+// The built-in engine reports no field details, so err becomes a single
+// placeholder error.
+func (v *validate) validationErrors(err error) []appvalidation.ValidationError {
+	return []appvalidation.ValidationError{{
+		Field:   "mock_field",
+		Tag:     "mock_tag",
+		Value:   "mock_value",
+		Param:   "mock_param",
+		Message: err.Error(),
+	}}
+}
@@ -0,0 +1,36 @@
+//go:build !playground
+
+package validation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	validatorimpl "github.com/next-trace/scg-service-api/infrastructure/validation"
+)
+
+func TestPlaygroundAdapter_BuiltinIgnoresStructTags(t *testing.T) {
+	v := validatorimpl.NewPlaygroundAdapter(appvalidation.DefaultConfig(), nil)
+
+	// The built-in engine does not interpret struct tags, so validation always succeeds.
+	bad := v.Validate(context.Background(), sample{Name: "a"})
+	if !bad.Valid {
+		t.Fatalf("expected built-in validator to return valid result even for short name")
+	}
+}
+
+func TestPlaygroundAdapter_BuiltinAliasCycle(t *testing.T) {
+	v := validatorimpl.NewPlaygroundAdapter(appvalidation.DefaultConfig(), nil)
+	engine := v.(validatorimpl.EngineProvider).Engine()
+	engine.RegisterAlias("self", "self")
+	engine.RegisterAlias("a", "b")
+	engine.RegisterAlias("b", "a")
+
+	for _, tag := range []string{"self", "a"} {
+		if err := engine.Var(1, tag); err == nil || !strings.Contains(err.Error(), "alias cycle") {
+			t.Fatalf("expected an alias cycle error for %s, got %v", tag, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build playground
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+)
+
+// validate wraps a *validator.Validate when building with the playground tag.
+type validate struct {
+	v *validator.Validate
+}
+
+// newValidator creates an engine reading rules from the given struct tag.
+func newValidator(tagName string) *validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	if tagName != "" {
+		v.SetTagName(tagName)
+	}
+	return &validate{v: v}
+}
+
+func (v *validate) Struct(s interface{}) error {
+	return v.v.Struct(s)
+}
+
+func (v *validate) StructPartial(s interface{}, fields ...string) error {
+	return v.v.StructPartial(s, fields...)
+}
+
+func (v *validate) Var(field interface{}, tag string) error {
+	return v.v.Var(field, tag)
+}
+
+func (v *validate) RegisterAlias(alias, tags string) {
+	v.v.RegisterAlias(alias, tags)
+}
+
+// RegisterValidation registers fn for tag; fn receives the field value.
+func (v *validate) RegisterValidation(tag string, fn func(fl interface{}) bool) error {
+	return v.v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return fn(fl.Field().Interface())
+	})
+}
+
+func (v *validate) RegisterTagNameFunc(fn func(field reflect.StructField) string) {
+	v.v.RegisterTagNameFunc(fn)
+}
+
+// registerCustomRule registers rule under name, passing the field value and
+// the tag parameter, if any, e.g. "3" for name=3.
+func (v *validate) registerCustomRule(name string, rule appvalidation.CustomRule) error {
+	return v.v.RegisterValidation(name, func(fl validator.FieldLevel) bool {
+		if param := fl.Param(); param != "" {
+			return rule(context.Background(), fl.Field().Interface(), param)
+		}
+		return rule(context.Background(), fl.Field().Interface())
+	})
+}
+
+// validationErrors converts an error from the engine into per-field errors.
+// Errors other than validator.ValidationErrors, such as passing a nil or
+// non-struct value, become a single error without a field.
+func (v *validate) validationErrors(err error) []appvalidation.ValidationError {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []appvalidation.ValidationError{{Message: err.Error()}}
+	}
+
+	out := make([]appvalidation.ValidationError, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		out = append(out, appvalidation.ValidationError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Value:   fe.Value(),
+			Param:   fe.Param(),
+			Message: fe.Error(),
+		})
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+//go:build playground
+
+package validation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appvalidation "github.com/next-trace/scg-service-api/application/validation"
+	validatorimpl "github.com/next-trace/scg-service-api/infrastructure/validation"
+)
+
+func TestPlaygroundAdapter_ValidatesStructTags(t *testing.T) {
+	v := validatorimpl.NewPlaygroundAdapter(appvalidation.DefaultConfig(), nil)
+
+	bad := v.Validate(context.Background(), sample{Name: "a"})
+	if bad.Valid {
+		t.Fatalf("expected short name to fail validation")
+	}
+	fields := bad.Fields["Name"]
+	if len(fields) != 1 || fields[0].Code != "min" || fields[0].Param != "3" {
+		t.Fatalf("expected a min=3 error on Name, got %+v", bad.Fields)
+	}
+
+	partial := v.ValidateField(context.Background(), sample{}, "Name")
+	if partial.Valid || partial.Fields["Name"][0].Code != "required" {
+		t.Fatalf("expected required error on Name, got %+v", partial.Fields)
+	}
+}
+
+func TestPlaygroundAdapter_CustomRuleParam(t *testing.T) {
+	cfg := appvalidation.DefaultConfig()
+	cfg.CustomRules = map[string]appvalidation.CustomRule{
+		"prefix": func(_ context.Context, value interface{}, params ...string) bool {
+			s, ok := value.(string)
+			return ok && len(params) == 1 && strings.HasPrefix(s, params[0])
+		},
+	}
+	v := validatorimpl.NewPlaygroundAdapter(cfg, nil)
+
+	type sku struct {
+		Code string `validate:"prefix=SKU"`
+	}
+	if res := v.Validate(context.Background(), sku{Code: "SKU-1"}); !res.Valid {
+		t.Fatalf("expected SKU-1 to pass, got %+v", res.Errors)
+	}
+	if res := v.Validate(context.Background(), sku{Code: "X-1"}); res.Valid {
+		t.Fatalf("expected X-1 to fail the prefix rule")
+	}
+}
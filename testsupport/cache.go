@@ -0,0 +1,273 @@
+package testsupport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+)
+
+// Cache is a map-backed appcache.Cache with TTL support. The clock can be
+// replaced with SetNow to expire entries without sleeping.
+type Cache struct {
+	mu         sync.Mutex
+	items      map[string]cacheItem
+	defaultTTL time.Duration
+	now        func() time.Time
+	closed     bool
+}
+
+type cacheItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+var _ appcache.Cache = (*Cache)(nil)
+
+// NewCache creates an empty cache whose SetDefault uses defaultTTL.
+func NewCache(defaultTTL time.Duration) *Cache {
+	return &Cache{items: map[string]cacheItem{}, defaultTTL: defaultTTL, now: time.Now}
+}
+
+// SetNow replaces the clock used for expiry.
+func (c *Cache) SetNow(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// lookupLocked returns the live item for key. The caller must hold c.mu.
+func (c *Cache) lookupLocked(key string) (cacheItem, bool) {
+	item, ok := c.items[key]
+	if !ok {
+		return cacheItem{}, false
+	}
+	if !item.expiresAt.IsZero() && !c.now().Before(item.expiresAt) {
+		delete(c.items, key)
+		return cacheItem{}, false
+	}
+	return item, true
+}
+
+// setLocked stores value under key. The caller must hold c.mu.
+func (c *Cache) setLocked(key string, value interface{}, ttl time.Duration) {
+	item := cacheItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = c.now().Add(ttl)
+	}
+	c.items[key] = item
+}
+
+// Get retrieves a value.
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, bool) {
+	if ctx.Err() != nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.lookupLocked(key)
+	return item.value, ok
+}
+
+// GetWithType retrieves a value and converts it into value via JSON.
+func (c *Cache) GetWithType(ctx context.Context, key string, value interface{}) bool {
+	raw, ok := c.Get(ctx, key)
+	if !ok {
+		return false
+	}
+	data, isBytes := raw.([]byte)
+	if !isBytes {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return false
+		}
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// Set stores a value; a ttl of 0 never expires.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(key, value, ttl)
+	return nil
+}
+
+// SetDefault stores a value with the cache's default TTL.
+func (c *Cache) SetDefault(ctx context.Context, key string, value interface{}) error {
+	return c.Set(ctx, key, value, c.defaultTTL)
+}
+
+// SetNX stores a value only if key is absent.
+func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lookupLocked(key); ok {
+		return false, nil
+	}
+	c.setLocked(key, value, ttl)
+	return true, nil
+}
+
+// CompareAndSwap replaces the value of key with newValue if it deep-equals old.
+func (c *Cache) CompareAndSwap(ctx context.Context, key string, old, newValue interface{}, ttl time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.lookupLocked(key)
+	if !ok || !reflect.DeepEqual(item.value, old) {
+		return false, nil
+	}
+	c.setLocked(key, newValue, ttl)
+	return true, nil
+}
+
+// Delete removes a value.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.DeleteMulti(ctx, []string{key})
+}
+
+// Clear removes all values.
+func (c *Cache) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = map[string]cacheItem{}
+	return nil
+}
+
+// Has reports whether key holds a live value.
+func (c *Cache) Has(ctx context.Context, key string) bool {
+	_, ok := c.Get(ctx, key)
+	return ok
+}
+
+// GetMulti retrieves several values, returning the missing keys.
+func (c *Cache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, []string) {
+	found := make(map[string]interface{}, len(keys))
+	var missing []string
+	for _, key := range keys {
+		if v, ok := c.Get(ctx, key); ok {
+			found[key] = v
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// SetMulti stores several values with the same TTL.
+func (c *Cache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range items {
+		c.setLocked(k, v, ttl)
+	}
+	return nil
+}
+
+// SetMultiTTL stores several values, each with its own TTL.
+func (c *Cache) SetMultiTTL(ctx context.Context, items map[string]appcache.CacheItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, item := range items {
+		c.setLocked(k, item.Value, item.TTL)
+	}
+	return nil
+}
+
+// DeleteMulti removes several values.
+func (c *Cache) DeleteMulti(ctx context.Context, keys []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		delete(c.items, k)
+	}
+	return nil
+}
+
+// Increment adds amount to an int64 counter, starting from zero.
+func (c *Cache) Increment(ctx context.Context, key string, amount int64) (int64, error) {
+	return c.IncrementWithTTL(ctx, key, amount, 0)
+}
+
+// IncrementWithTTL adds amount to an int64 counter; a new counter expires
+// after ttl, an existing one keeps its expiration.
+func (c *Cache) IncrementWithTTL(ctx context.Context, key string, amount int64, ttl time.Duration) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var current int64
+	item, ok := c.lookupLocked(key)
+	if ok {
+		n, isInt := item.value.(int64)
+		if !isInt {
+			return 0, errors.New("value is not an int64")
+		}
+		current = n
+	} else if ttl > 0 {
+		item.expiresAt = c.now().Add(ttl)
+	}
+	item.value = current + amount
+	c.items[key] = item
+	return current + amount, nil
+}
+
+// Decrement subtracts amount from an int64 counter.
+func (c *Cache) Decrement(ctx context.Context, key string, amount int64) (int64, error) {
+	return c.Increment(ctx, key, -amount)
+}
+
+// Close marks the cache closed; it keeps working so tests can inspect it.
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// Closed reports whether Close was called.
+func (c *Cache) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// Len returns the number of live entries.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for k := range c.items {
+		if _, ok := c.lookupLocked(k); ok {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package testsupport_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appcache "github.com/next-trace/scg-service-api/application/cache"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_TTLAndAtomicOps(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := testsupport.NewCache(time.Minute)
+	c.SetNow(func() time.Time { return now })
+
+	assert.NoError(t, c.SetDefault(ctx, "k", "v"))
+	stored, err := c.SetNX(ctx, "k", "other", 0)
+	assert.NoError(t, err)
+	assert.False(t, stored)
+
+	swapped, err := c.CompareAndSwap(ctx, "k", "v", "w", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+
+	n, err := c.Increment(ctx, "n", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	var out string
+	assert.True(t, c.GetWithType(ctx, "k", &out))
+	assert.Equal(t, "w", out)
+	assert.Equal(t, 2, c.Len())
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, c.Has(ctx, "k"))
+	assert.True(t, c.Has(ctx, "n"))
+}
+
+func TestCache_IncrementWithTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := testsupport.NewCache(0)
+	c.SetNow(func() time.Time { return now })
+
+	n, err := c.IncrementWithTTL(ctx, "window", 1, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	now = now.Add(30 * time.Second)
+	n, err = c.IncrementWithTTL(ctx, "window", 1, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	now = now.Add(30 * time.Second)
+	assert.False(t, c.Has(ctx, "window"), "the TTL runs from the counter's creation")
+}
+
+func TestCache_SetMultiTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := testsupport.NewCache(0)
+	c.SetNow(func() time.Time { return now })
+
+	assert.NoError(t, c.SetMultiTTL(ctx, map[string]appcache.CacheItem{
+		"short":   {Value: 1, TTL: time.Minute},
+		"long":    {Value: 2, TTL: time.Hour},
+		"forever": {Value: 3},
+	}))
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, c.Has(ctx, "short"))
+	assert.True(t, c.Has(ctx, "long"))
+
+	now = now.Add(2 * time.Hour)
+	assert.False(t, c.Has(ctx, "long"))
+	assert.True(t, c.Has(ctx, "forever"))
+}
@@ -0,0 +1,113 @@
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	appcircuitbreaker "github.com/next-trace/scg-service-api/application/circuitbreaker"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker for breakers forced open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker is an appcircuitbreaker.CircuitBreaker whose state is set by
+// the test. Closed and half-open breakers run fn directly; open breakers fail
+// with ErrCircuitOpen. Executions are counted per name.
+type CircuitBreaker struct {
+	mu     sync.Mutex
+	states map[string]appcircuitbreaker.State
+	calls  map[string]int
+}
+
+var _ appcircuitbreaker.CircuitBreaker = (*CircuitBreaker)(nil)
+
+// NewCircuitBreaker creates a breaker set with every name closed.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{states: map[string]appcircuitbreaker.State{}, calls: map[string]int{}}
+}
+
+// SetState forces the state of the named breaker.
+func (cb *CircuitBreaker) SetState(name string, state appcircuitbreaker.State) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.states[name] = state
+}
+
+// Calls returns how many times Execute ran or rejected a call for name.
+func (cb *CircuitBreaker) Calls(name string) int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.calls[name]
+}
+
+// Execute runs fn unless the breaker is open.
+func (cb *CircuitBreaker) Execute(ctx context.Context, name string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	cb.mu.Lock()
+	cb.calls[name]++
+	open := cb.states[name] == appcircuitbreaker.StateOpen
+	cb.mu.Unlock()
+
+	if open {
+		return nil, ErrCircuitOpen
+	}
+	return fn(ctx)
+}
+
+// ExecuteWithFallback runs fn and calls fallback on any error.
+func (cb *CircuitBreaker) ExecuteWithFallback(ctx context.Context, name string, fn func(ctx context.Context) (interface{}, error), fallback func(ctx context.Context, err error) (interface{}, error)) (interface{}, error) {
+	result, err := cb.Execute(ctx, name, fn)
+	if err != nil && fallback != nil {
+		return fallback(ctx, err)
+	}
+	return result, err
+}
+
+// GetState returns the forced state, StateClosed by default.
+func (cb *CircuitBreaker) GetState(name string) appcircuitbreaker.State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if state, ok := cb.states[name]; ok {
+		return state
+	}
+	return appcircuitbreaker.StateClosed
+}
+
+// Reset closes the named breaker.
+func (cb *CircuitBreaker) Reset(name string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	delete(cb.states, name)
+}
+
+// ResetAll clears every state set with SetState.
+func (cb *CircuitBreaker) ResetAll() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	clear(cb.states)
+}
+
+// List returns every name that was executed or given a state, sorted by name.
+func (cb *CircuitBreaker) List() []appcircuitbreaker.BreakerStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	names := map[string]bool{}
+	for name := range cb.calls {
+		names[name] = true
+	}
+	for name := range cb.states {
+		names[name] = true
+	}
+	statuses := make([]appcircuitbreaker.BreakerStatus, 0, len(names))
+	for name := range names {
+		state, ok := cb.states[name]
+		if !ok {
+			state = appcircuitbreaker.StateClosed
+		}
+		statuses = append(statuses, appcircuitbreaker.BreakerStatus{Name: name, State: state})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
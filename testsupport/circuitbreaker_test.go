@@ -0,0 +1,40 @@
+package testsupport_test
+
+import (
+	"context"
+	"testing"
+
+	appcircuitbreaker "github.com/next-trace/scg-service-api/application/circuitbreaker"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_ForcedState(t *testing.T) {
+	ctx := context.Background()
+	cb := testsupport.NewCircuitBreaker()
+	ok := func(context.Context) (interface{}, error) { return "ok", nil }
+
+	res, err := cb.Execute(ctx, "svc", ok)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", res)
+
+	cb.SetState("svc", appcircuitbreaker.StateOpen)
+	_, err = cb.Execute(ctx, "svc", ok)
+	assert.ErrorIs(t, err, testsupport.ErrCircuitOpen)
+
+	res, err = cb.ExecuteWithFallback(ctx, "svc", ok, func(context.Context, error) (interface{}, error) {
+		return "fallback", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "fallback", res)
+	assert.Equal(t, 3, cb.Calls("svc"))
+
+	cb.Reset("svc")
+	assert.Equal(t, appcircuitbreaker.StateClosed, cb.GetState("svc"))
+
+	cb.SetState("a", appcircuitbreaker.StateOpen)
+	cb.SetState("b", appcircuitbreaker.StateHalfOpen)
+	cb.ResetAll()
+	assert.Equal(t, appcircuitbreaker.StateClosed, cb.GetState("a"))
+	assert.Equal(t, appcircuitbreaker.StateClosed, cb.GetState("b"))
+}
@@ -0,0 +1,8 @@
+// Package testsupport provides in-memory, recording fakes of the application
+// ports (Logger, Metrics, Tracer, Cache, Limiter, CircuitBreaker) and of the
+// domain ItemRepository, for use in consumers' tests.
+//
+// The fakes are safe for concurrent use. Each records what it was asked to do
+// and exposes accessors and small assertion helpers taking a testing.TB, so
+// tests do not need to reimplement them.
+package testsupport
@@ -0,0 +1,126 @@
+package testsupport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	appratelimit "github.com/next-trace/scg-service-api/application/ratelimit"
+)
+
+// Limiter is a scriptable appratelimit.Limiter. Every key has a budget of
+// tokens that never refills; keys without an explicit budget get the default
+// passed to NewLimiter. Calls are counted per key.
+type Limiter struct {
+	mu            sync.Mutex
+	defaultBudget int
+	budgets       map[string]int
+	calls         map[string]int
+}
+
+var _ appratelimit.Limiter = (*Limiter)(nil)
+
+// NewLimiter creates a limiter granting defaultBudget tokens per key.
+// A negative budget allows every request.
+func NewLimiter(defaultBudget int) *Limiter {
+	return &Limiter{defaultBudget: defaultBudget, budgets: map[string]int{}, calls: map[string]int{}}
+}
+
+// SetBudget sets the remaining tokens for key; negative means unlimited.
+func (l *Limiter) SetBudget(key string, tokens int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.budgets[key] = tokens
+}
+
+// Calls returns how many consuming calls were made for key.
+func (l *Limiter) Calls(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.calls[key]
+}
+
+// budgetLocked returns the remaining tokens for key. The caller must hold l.mu.
+func (l *Limiter) budgetLocked(key string) int {
+	if b, ok := l.budgets[key]; ok {
+		return b
+	}
+	return l.defaultBudget
+}
+
+// take consumes n tokens for key if available.
+func (l *Limiter) take(key string, n int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls[key]++
+	b := l.budgetLocked(key)
+	if b < 0 {
+		return true
+	}
+	if b < n {
+		return false
+	}
+	l.budgets[key] = b - n
+	return true
+}
+
+// Allow consumes one token.
+func (l *Limiter) Allow(ctx context.Context, key string) bool { return l.AllowN(ctx, key, 1) }
+
+// AllowN consumes n tokens if available.
+func (l *Limiter) AllowN(_ context.Context, key string, n int) bool { return l.take(key, n) }
+
+// Wait consumes one token or blocks until ctx is done.
+func (l *Limiter) Wait(ctx context.Context, key string) error { return l.WaitN(ctx, key, 1) }
+
+// WaitN consumes n tokens or, since budgets never refill, blocks until ctx is done.
+func (l *Limiter) WaitN(ctx context.Context, key string, n int) error {
+	if l.take(key, n) {
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Reserve consumes one token, returning 0 or -1 if none is left.
+func (l *Limiter) Reserve(ctx context.Context, key string) time.Duration {
+	return l.ReserveN(ctx, key, 1)
+}
+
+// ReserveN consumes n tokens, returning 0 or -1 if they are not available.
+func (l *Limiter) ReserveN(_ context.Context, key string, n int) time.Duration {
+	if l.take(key, n) {
+		return 0
+	}
+	return -1
+}
+
+// Peek reports 0 if a token is available and time.Hour otherwise.
+func (l *Limiter) Peek(ctx context.Context, key string) time.Duration {
+	return l.PeekN(ctx, key, 1)
+}
+
+// PeekN reports 0 if n tokens are available and time.Hour otherwise.
+func (l *Limiter) PeekN(_ context.Context, key string, n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b := l.budgetLocked(key); b < 0 || b >= n {
+		return 0
+	}
+	return time.Hour
+}
+
+// Stats reports the remaining budget for keys that have been used.
+func (l *Limiter) Stats(_ context.Context, key string) (appratelimit.LimiterStats, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.calls[key] == 0 {
+		return appratelimit.LimiterStats{}, false
+	}
+	b := l.budgetLocked(key)
+	stats := appratelimit.LimiterStats{Tokens: float64(b), Burst: l.defaultBudget}
+	if b == 0 {
+		stats.NextToken = time.Hour
+	}
+	return stats, true
+}
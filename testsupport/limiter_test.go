@@ -0,0 +1,34 @@
+package testsupport_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Budgets(t *testing.T) {
+	ctx := context.Background()
+	l := testsupport.NewLimiter(2)
+	l.SetBudget("vip", -1)
+
+	assert.True(t, l.Allow(ctx, "k"))
+	assert.True(t, l.Allow(ctx, "k"))
+	assert.False(t, l.Allow(ctx, "k"))
+	assert.Equal(t, 3, l.Calls("k"))
+	assert.Equal(t, time.Hour, l.Peek(ctx, "k"))
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, l.Allow(ctx, "vip"))
+	}
+
+	stats, ok := l.Stats(ctx, "k")
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, stats.Tokens)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(waitCtx, "k"), context.DeadlineExceeded)
+}
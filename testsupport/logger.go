@@ -0,0 +1,158 @@
+package testsupport
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+)
+
+// LogEntry is a single entry captured by Logger.
+type LogEntry struct {
+	Level  string
+	Msg    string
+	Err    error
+	Fields map[string]interface{}
+}
+
+// Logger is a recording applogger.Logger. Fields from WithField and from
+// applogger.ContextWithFields are merged into each entry's Fields.
+type Logger struct {
+	store  *logStore
+	fields map[string]interface{}
+}
+
+type logStore struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	flushes int
+}
+
+var _ applogger.Logger = (*Logger)(nil)
+
+// NewLogger creates an empty recording logger.
+func NewLogger() *Logger {
+	return &Logger{store: &logStore{}}
+}
+
+func (l *Logger) record(ctx context.Context, level string, err error, msg string, kv map[string]interface{}) {
+	fields := make(map[string]interface{}, len(l.fields)+len(kv))
+	for k, v := range applogger.FieldsFromContext(ctx) {
+		fields[k] = v
+	}
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for k, v := range kv {
+		fields[k] = v
+	}
+
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	l.store.entries = append(l.store.entries, LogEntry{Level: level, Msg: msg, Err: err, Fields: fields})
+}
+
+// Debug records a debug entry.
+func (l *Logger) Debug(ctx context.Context, msg string) { l.record(ctx, "debug", nil, msg, nil) }
+
+// Info records an info entry.
+func (l *Logger) Info(ctx context.Context, msg string) { l.record(ctx, "info", nil, msg, nil) }
+
+// Warn records a warn entry.
+func (l *Logger) Warn(ctx context.Context, msg string) { l.record(ctx, "warn", nil, msg, nil) }
+
+// Error records an error entry.
+func (l *Logger) Error(ctx context.Context, err error, msg string) {
+	l.record(ctx, "error", err, msg, nil)
+}
+
+// Fatal records a fatal entry. Unlike real loggers it does not exit.
+func (l *Logger) Fatal(ctx context.Context, err error, msg string) {
+	l.record(ctx, "fatal", err, msg, nil)
+}
+
+// DebugKV records a debug entry with fields.
+func (l *Logger) DebugKV(ctx context.Context, msg string, keyValues map[string]interface{}) {
+	l.record(ctx, "debug", nil, msg, keyValues)
+}
+
+// InfoKV records an info entry with fields.
+func (l *Logger) InfoKV(ctx context.Context, msg string, keyValues map[string]interface{}) {
+	l.record(ctx, "info", nil, msg, keyValues)
+}
+
+// WarnKV records a warn entry with fields.
+func (l *Logger) WarnKV(ctx context.Context, msg string, keyValues map[string]interface{}) {
+	l.record(ctx, "warn", nil, msg, keyValues)
+}
+
+// ErrorKV records an error entry with fields.
+func (l *Logger) ErrorKV(ctx context.Context, err error, msg string, keyValues map[string]interface{}) {
+	l.record(ctx, "error", err, msg, keyValues)
+}
+
+// FatalKV records a fatal entry with fields. It does not exit.
+func (l *Logger) FatalKV(ctx context.Context, err error, msg string, keyValues map[string]interface{}) {
+	l.record(ctx, "fatal", err, msg, keyValues)
+}
+
+// WithField returns a logger sharing this logger's entries that adds key to each of them.
+func (l *Logger) WithField(key string, value interface{}) applogger.Logger {
+	fields := make(map[string]interface{}, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	return &Logger{store: l.store, fields: fields}
+}
+
+// Flush counts the call and returns nil.
+func (l *Logger) Flush() error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	l.store.flushes++
+	return nil
+}
+
+// Entries returns a copy of the recorded entries in order.
+func (l *Logger) Entries() []LogEntry {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	return append([]LogEntry(nil), l.store.entries...)
+}
+
+// Flushes returns how many times Flush was called.
+func (l *Logger) Flushes() int {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	return l.store.flushes
+}
+
+// Find returns the first entry with the given message.
+func (l *Logger) Find(msg string) (LogEntry, bool) {
+	for _, e := range l.Entries() {
+		if e.Msg == msg {
+			return e, true
+		}
+	}
+	return LogEntry{}, false
+}
+
+// Reset discards the recorded entries.
+func (l *Logger) Reset() {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	l.store.entries = nil
+	l.store.flushes = 0
+}
+
+// AssertLogged fails t unless an entry with the given message was recorded.
+func (l *Logger) AssertLogged(t testing.TB, msg string) LogEntry {
+	t.Helper()
+	e, ok := l.Find(msg)
+	if !ok {
+		t.Errorf("expected a log entry %q, got %d other entries", msg, len(l.Entries()))
+	}
+	return e
+}
@@ -0,0 +1,30 @@
+package testsupport_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	applogger "github.com/next-trace/scg-service-api/application/logger"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_RecordsEntries(t *testing.T) {
+	log := testsupport.NewLogger()
+	ctx := applogger.ContextWithFields(context.Background(), map[string]interface{}{"request_id": "r1"})
+
+	log.WithField("component", "cache").ErrorKV(ctx, errors.New("boom"), "failed", map[string]interface{}{"key": "k"})
+	log.Info(ctx, "done")
+	assert.NoError(t, log.Flush())
+
+	entry := log.AssertLogged(t, "failed")
+	assert.Equal(t, "error", entry.Level)
+	assert.EqualError(t, entry.Err, "boom")
+	assert.Equal(t, map[string]interface{}{"request_id": "r1", "component": "cache", "key": "k"}, entry.Fields)
+	assert.Len(t, log.Entries(), 2)
+	assert.Equal(t, 1, log.Flushes())
+
+	log.Reset()
+	assert.Empty(t, log.Entries())
+}
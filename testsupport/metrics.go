@@ -0,0 +1,159 @@
+package testsupport
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appmetrics "github.com/next-trace/scg-service-api/application/metrics"
+)
+
+// Metrics is a recording appmetrics.Metrics. Values are kept per metric name
+// and label set; instances returned by WithLabels share the same store.
+type Metrics struct {
+	store  *metricStore
+	labels map[string]string
+}
+
+type metricStore struct {
+	mu         sync.Mutex
+	counters   map[string]float64
+	gauges     map[string]float64
+	histograms map[string][]float64
+}
+
+var _ appmetrics.Metrics = (*Metrics)(nil)
+
+// NewMetrics creates an empty recording metrics instance.
+func NewMetrics() *Metrics {
+	return &Metrics{store: &metricStore{
+		counters:   map[string]float64{},
+		gauges:     map[string]float64{},
+		histograms: map[string][]float64{},
+	}}
+}
+
+// seriesKey identifies a series as name{k1=v1,k2=v2} with sorted labels.
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + labels[k]
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (m *Metrics) update(fn func(s *metricStore)) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	fn(m.store)
+}
+
+// CounterInc increments the counter by 1.
+func (m *Metrics) CounterInc(name string) { m.CounterAdd(name, 1) }
+
+// CounterAdd adds value to the counter.
+func (m *Metrics) CounterAdd(name string, value float64) {
+	m.update(func(s *metricStore) { s.counters[seriesKey(name, m.labels)] += value })
+}
+
+// GaugeSet sets the gauge.
+func (m *Metrics) GaugeSet(name string, value float64) {
+	m.update(func(s *metricStore) { s.gauges[seriesKey(name, m.labels)] = value })
+}
+
+// GaugeInc increments the gauge by 1.
+func (m *Metrics) GaugeInc(name string) { m.GaugeAdd(name, 1) }
+
+// GaugeDec decrements the gauge by 1.
+func (m *Metrics) GaugeDec(name string) { m.GaugeAdd(name, -1) }
+
+// GaugeAdd adds value to the gauge.
+func (m *Metrics) GaugeAdd(name string, value float64) {
+	m.update(func(s *metricStore) { s.gauges[seriesKey(name, m.labels)] += value })
+}
+
+// GaugeSub subtracts value from the gauge.
+func (m *Metrics) GaugeSub(name string, value float64) { m.GaugeAdd(name, -value) }
+
+// HistogramObserve records an observation.
+func (m *Metrics) HistogramObserve(name string, value float64) {
+	m.update(func(s *metricStore) {
+		key := seriesKey(name, m.labels)
+		s.histograms[key] = append(s.histograms[key], value)
+	})
+}
+
+// TimerObserveDuration runs f and records its duration in seconds.
+func (m *Metrics) TimerObserveDuration(name string, f func()) {
+	start := time.Now()
+	f()
+	m.HistogramObserve(name, time.Since(start).Seconds())
+}
+
+// TimerStart returns a function that records the elapsed time in seconds.
+func (m *Metrics) TimerStart(name string) func() time.Duration {
+	start := time.Now()
+	return func() time.Duration {
+		d := time.Since(start)
+		m.HistogramObserve(name, d.Seconds())
+		return d
+	}
+}
+
+// WithLabels returns a view recording into the same store with labels merged in.
+func (m *Metrics) WithLabels(labels map[string]string) appmetrics.Metrics {
+	merged := make(map[string]string, len(m.labels)+len(labels))
+	for k, v := range m.labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return &Metrics{store: m.store, labels: merged}
+}
+
+// Serve does nothing.
+func (m *Metrics) Serve(context.Context, string) error { return nil }
+
+// Shutdown does nothing.
+func (m *Metrics) Shutdown(context.Context) error { return nil }
+
+// Counter returns the value of the counter series with the given labels (nil for none).
+func (m *Metrics) Counter(name string, labels map[string]string) float64 {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	return m.store.counters[seriesKey(name, labels)]
+}
+
+// Gauge returns the value of the gauge series with the given labels.
+func (m *Metrics) Gauge(name string, labels map[string]string) float64 {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	return m.store.gauges[seriesKey(name, labels)]
+}
+
+// Observations returns a copy of the histogram series with the given labels.
+func (m *Metrics) Observations(name string, labels map[string]string) []float64 {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	return append([]float64(nil), m.store.histograms[seriesKey(name, labels)]...)
+}
+
+// AssertCounter fails t unless the counter series has the wanted value.
+func (m *Metrics) AssertCounter(t testing.TB, name string, labels map[string]string, want float64) {
+	t.Helper()
+	if got := m.Counter(name, labels); got != want {
+		t.Errorf("counter %s = %v, want %v", seriesKey(name, labels), got, want)
+	}
+}
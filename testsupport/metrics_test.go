@@ -0,0 +1,25 @@
+package testsupport_test
+
+import (
+	"testing"
+
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_RecordsCounts(t *testing.T) {
+	m := testsupport.NewMetrics()
+
+	m.CounterInc("requests_total")
+	m.CounterAdd("requests_total", 2)
+	m.WithLabels(map[string]string{"route": "items", "code": "200"}).CounterInc("requests_total")
+	m.GaugeSet("in_flight", 3)
+	m.GaugeDec("in_flight")
+	m.HistogramObserve("latency", 0.5)
+	_ = m.TimerStart("latency")()
+
+	m.AssertCounter(t, "requests_total", nil, 3)
+	m.AssertCounter(t, "requests_total", map[string]string{"code": "200", "route": "items"}, 1)
+	assert.Equal(t, 2.0, m.Gauge("in_flight", nil))
+	assert.Len(t, m.Observations("latency", nil), 2)
+}
@@ -0,0 +1,178 @@
+package testsupport
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/next-trace/scg-service-api/domain/repository"
+)
+
+// ItemRepository is an in-memory repository.ItemRepository. It stores copies
+// of saved items, applies ItemFilter like a real store (ordered by ID unless
+// the filter sorts by id, name, created_at or updated_at, with soft-deleted
+// items hidden unless the filter includes them) and
+// returns domainerrors.ErrNotFound for unknown IDs. Set Err to make every
+// call fail.
+type ItemRepository struct {
+	mu    sync.Mutex
+	items map[string]entity.Item
+	saves int
+
+	// Err, when set, is returned by every method.
+	Err error
+}
+
+var _ repository.ItemRepository = (*ItemRepository)(nil)
+
+// NewItemRepository creates a repository holding copies of items.
+func NewItemRepository(items ...*entity.Item) *ItemRepository {
+	r := &ItemRepository{items: map[string]entity.Item{}}
+	for _, it := range items {
+		r.items[it.ID] = copyItem(it)
+	}
+	return r
+}
+
+func copyItem(it *entity.Item) entity.Item {
+	c := *it
+	c.Tags = append([]string(nil), it.Tags...)
+	return c
+}
+
+// GetByID returns a copy of the item.
+func (r *ItemRepository) GetByID(_ context.Context, id string) (*entity.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	it, ok := r.items[id]
+	if !ok {
+		return nil, domainerrors.ErrNotFound
+	}
+	c := copyItem(&it)
+	return &c, nil
+}
+
+// FindAll returns copies of the matching items, sorted and paginated.
+func (r *ItemRepository) FindAll(_ context.Context, filter repository.ItemFilter) ([]*entity.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	matched := r.matchLocked(filter)
+	offset := max(filter.Offset, 0)
+	if offset >= len(matched) {
+		return []*entity.Item{}, nil
+	}
+	matched = matched[offset:]
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+// Count returns the number of items matching the filter, ignoring pagination.
+func (r *ItemRepository) Count(_ context.Context, filter repository.ItemFilter) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return 0, r.Err
+	}
+	return int64(len(r.matchLocked(filter))), nil
+}
+
+// Save stores a copy of item.
+func (r *ItemRepository) Save(_ context.Context, item *entity.Item) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	r.items[item.ID] = copyItem(item)
+	r.saves++
+	return nil
+}
+
+// Delete removes the item; unknown IDs return domainerrors.ErrNotFound.
+func (r *ItemRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	if _, ok := r.items[id]; !ok {
+		return domainerrors.ErrNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+// Saves returns how many times Save succeeded.
+func (r *ItemRepository) Saves() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saves
+}
+
+// matchLocked returns copies of the items matching filter in filter.Sort
+// order, falling back to ordering by ID.
+// The caller must hold r.mu.
+func (r *ItemRepository) matchLocked(filter repository.ItemFilter) []*entity.Item {
+	term := strings.ToLower(filter.SearchTerm)
+	out := []*entity.Item{}
+	for _, it := range r.items {
+		if filter.Status != "" && it.Status != filter.Status {
+			continue
+		}
+		if it.Status == entity.ItemStatusDeleted && !filter.IncludeDeleted && filter.Status != entity.ItemStatusDeleted {
+			continue
+		}
+		if term != "" && !strings.Contains(strings.ToLower(it.Name), term) &&
+			!strings.Contains(strings.ToLower(it.Description), term) {
+			continue
+		}
+		hasAll := true
+		for _, tag := range filter.Tags {
+			if !it.HasTag(tag) {
+				hasAll = false
+				break
+			}
+		}
+		if !hasAll {
+			continue
+		}
+		c := copyItem(&it)
+		out = append(out, &c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	if less := itemLess(strings.TrimPrefix(filter.Sort, "-")); less != nil {
+		if strings.HasPrefix(filter.Sort, "-") {
+			sort.SliceStable(out, func(i, j int) bool { return less(out[j], out[i]) })
+		} else {
+			sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+		}
+	}
+	return out
+}
+
+// itemLess returns the ascending order for a sort field, or nil if the field
+// is not supported.
+func itemLess(field string) func(a, b *entity.Item) bool {
+	switch field {
+	case "id":
+		return func(a, b *entity.Item) bool { return a.ID < b.ID }
+	case "name":
+		return func(a, b *entity.Item) bool { return a.Name < b.Name }
+	case "created_at":
+		return func(a, b *entity.Item) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "updated_at":
+		return func(a, b *entity.Item) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+	}
+	return nil
+}
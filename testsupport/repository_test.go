@@ -0,0 +1,81 @@
+package testsupport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/domain/entity"
+	domainerrors "github.com/next-trace/scg-service-api/domain/errors"
+	"github.com/next-trace/scg-service-api/domain/repository"
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemRepository_Filtering(t *testing.T) {
+	ctx := context.Background()
+	repo := testsupport.NewItemRepository(
+		&entity.Item{ID: "1", Name: "Red apple", Tags: []string{"fruit"}, Status: entity.ItemStatusActive},
+		&entity.Item{ID: "2", Name: "Green apple", Tags: []string{"fruit"}, Status: entity.ItemStatusInactive},
+		&entity.Item{ID: "3", Name: "Carrot", Tags: []string{"vegetable"}, Status: entity.ItemStatusActive},
+	)
+
+	items, err := repo.FindAll(ctx, repository.NewItemFilter().WithSearch("apple").WithTags([]string{"fruit"}))
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "1", items[0].ID)
+		assert.Equal(t, "2", items[1].ID)
+	}
+
+	items, err = repo.FindAll(ctx, repository.NewItemFilter().WithSort("-name"))
+	assert.NoError(t, err)
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, []string{"1", "2", "3"}, []string{items[0].ID, items[1].ID, items[2].ID})
+	}
+	items, err = repo.FindAll(ctx, repository.NewItemFilter().WithSort("name"))
+	assert.NoError(t, err)
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, []string{"3", "2", "1"}, []string{items[0].ID, items[1].ID, items[2].ID})
+	}
+
+	n, err := repo.Count(ctx, repository.NewItemFilter().WithStatus(entity.ItemStatusActive).WithPagination(0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// Returned items are copies.
+	items[0].Name = "changed"
+	got, err := repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Red apple", got.Name)
+
+	assert.ErrorIs(t, repo.Delete(ctx, "missing"), domainerrors.ErrNotFound)
+	_, err = repo.GetByID(ctx, "missing")
+	assert.ErrorIs(t, err, domainerrors.ErrNotFound)
+}
+
+func TestItemRepository_HidesDeletedByDefault(t *testing.T) {
+	ctx := context.Background()
+	repo := testsupport.NewItemRepository(
+		&entity.Item{ID: "1", Name: "Kept", Status: entity.ItemStatusActive},
+		&entity.Item{ID: "2", Name: "Gone", Status: entity.ItemStatusDeleted},
+	)
+
+	items, err := repo.FindAll(ctx, repository.NewItemFilter())
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "1", items[0].ID)
+	}
+	n, err := repo.Count(ctx, repository.NewItemFilter())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	items, err = repo.FindAll(ctx, repository.NewItemFilter().WithDeleted())
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	n, err = repo.Count(ctx, repository.NewItemFilter().WithDeleted())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	n, err = repo.Count(ctx, repository.NewItemFilter().WithStatus(entity.ItemStatusDeleted))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
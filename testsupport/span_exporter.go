@@ -0,0 +1,29 @@
+package testsupport
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SpanExporter is an in-memory OpenTelemetry span exporter whose spans stay
+// readable after the tracer provider shuts down; tracetest.InMemoryExporter
+// clears them on Shutdown. Shut the tracer down to flush its batch processor,
+// then read GetSpans:
+//
+//	exporter := testsupport.NewSpanExporter()
+//	tracer, _ := tracing.NewOtelAdapterWithOptions(cfg, tracing.WithExporter(exporter))
+//	// ... record spans ...
+//	_ = tracer.Shutdown(ctx)
+//	spans := exporter.GetSpans()
+type SpanExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+// NewSpanExporter creates an empty SpanExporter.
+func NewSpanExporter() SpanExporter {
+	return SpanExporter{tracetest.NewInMemoryExporter()}
+}
+
+// Shutdown does nothing, so the exported spans outlive the tracer provider.
+func (SpanExporter) Shutdown(context.Context) error { return nil }
@@ -0,0 +1,25 @@
+package testsupport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/testsupport"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSpanExporter_RetainsSpansAfterShutdown(t *testing.T) {
+	exporter := testsupport.NewSpanExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+
+	_, span := provider.Tracer("test").Start(context.Background(), "work")
+	span.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "work" {
+		t.Fatalf("expected the flushed span to be retained, got %v", spans)
+	}
+}
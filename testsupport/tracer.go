@@ -0,0 +1,123 @@
+package testsupport
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	apptracing "github.com/next-trace/scg-service-api/application/tracing"
+)
+
+// SpanRecord is a span captured by Tracer.
+type SpanRecord struct {
+	Name       string
+	Parent     string
+	Attributes map[string]string
+	Events     []string
+	Errors     []error
+	Ended      bool
+}
+
+// Tracer is a recording apptracing.Tracer. Spans started from a context that
+// already carries a span record it as their parent.
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*SpanRecord
+}
+
+var _ apptracing.Tracer = (*Tracer)(nil)
+
+type spanKey struct{}
+
+// NewTracer creates an empty recording tracer.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Start records a new span and returns a context carrying it.
+func (tr *Tracer) Start(ctx context.Context, spanName string) (context.Context, func()) {
+	span := &SpanRecord{Name: spanName, Attributes: map[string]string{}}
+	if parent, ok := ctx.Value(spanKey{}).(*SpanRecord); ok {
+		span.Parent = parent.Name
+	}
+
+	tr.mu.Lock()
+	tr.spans = append(tr.spans, span)
+	tr.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span), func() {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		span.Ended = true
+	}
+}
+
+// withSpan applies fn to the span in ctx, if any.
+func (tr *Tracer) withSpan(ctx context.Context, fn func(*SpanRecord)) {
+	span, ok := ctx.Value(spanKey{}).(*SpanRecord)
+	if !ok {
+		return
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	fn(span)
+}
+
+// AddEvent records an event name on the current span.
+func (tr *Tracer) AddEvent(ctx context.Context, name string, attributes map[string]string) {
+	tr.withSpan(ctx, func(s *SpanRecord) {
+		s.Events = append(s.Events, name)
+		for k, v := range attributes {
+			s.Attributes[k] = v
+		}
+	})
+}
+
+// SetAttributes records attributes on the current span.
+func (tr *Tracer) SetAttributes(ctx context.Context, attributes map[string]string) {
+	tr.withSpan(ctx, func(s *SpanRecord) {
+		for k, v := range attributes {
+			s.Attributes[k] = v
+		}
+	})
+}
+
+// RecordError records err on the current span.
+func (tr *Tracer) RecordError(ctx context.Context, err error) {
+	tr.withSpan(ctx, func(s *SpanRecord) { s.Errors = append(s.Errors, err) })
+}
+
+// Shutdown does nothing.
+func (tr *Tracer) Shutdown(context.Context) error { return nil }
+
+// Spans returns copies of the recorded spans in start order.
+func (tr *Tracer) Spans() []SpanRecord {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	out := make([]SpanRecord, len(tr.spans))
+	for i, s := range tr.spans {
+		out[i] = *s
+		out[i].Attributes = make(map[string]string, len(s.Attributes))
+		for k, v := range s.Attributes {
+			out[i].Attributes[k] = v
+		}
+		out[i].Events = append([]string(nil), s.Events...)
+		out[i].Errors = append([]error(nil), s.Errors...)
+	}
+	return out
+}
+
+// AssertSpan fails t unless an ended span with the given name was recorded.
+func (tr *Tracer) AssertSpan(t testing.TB, name string) SpanRecord {
+	t.Helper()
+	for _, s := range tr.Spans() {
+		if s.Name == name {
+			if !s.Ended {
+				t.Errorf("span %q was not ended", name)
+			}
+			return s
+		}
+	}
+	t.Errorf("expected a span %q", name)
+	return SpanRecord{}
+}
@@ -0,0 +1,29 @@
+package testsupport_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/next-trace/scg-service-api/testsupport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracer_RecordsSpans(t *testing.T) {
+	tr := testsupport.NewTracer()
+
+	ctx, endParent := tr.Start(context.Background(), "parent")
+	childCtx, endChild := tr.Start(ctx, "child")
+	tr.SetAttributes(childCtx, map[string]string{"item.id": "42"})
+	tr.AddEvent(childCtx, "cache miss", nil)
+	tr.RecordError(childCtx, errors.New("boom"))
+	endChild()
+	endParent()
+
+	child := tr.AssertSpan(t, "child")
+	assert.Equal(t, "parent", child.Parent)
+	assert.Equal(t, "42", child.Attributes["item.id"])
+	assert.Equal(t, []string{"cache miss"}, child.Events)
+	assert.Len(t, child.Errors, 1)
+	assert.Len(t, tr.Spans(), 2)
+}